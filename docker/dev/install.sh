@@ -83,8 +83,8 @@ rm -rf "$HOME"/.cache/pip/*
 
 
 echo "Install Go compiler ..."
-GO_MIRROR_0="https://studygolang.com/dl/golang/go1.13.4.linux-amd64.tar.gz"
-GO_MIRROR_1="https://dl.google.com/go/go1.13.4.linux-amd64.tar.gz"
+GO_MIRROR_0="https://studygolang.com/dl/golang/go1.21.13.linux-amd64.tar.gz"
+GO_MIRROR_1="https://dl.google.com/go/go1.21.13.linux-amd64.tar.gz"
 axel --quiet --output go.tar.gz $GO_MIRROR_0 $GO_MIRROR_1
 tar -C /usr/local -xzf go.tar.gz
 rm go.tar.gz
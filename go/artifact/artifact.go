@@ -0,0 +1,227 @@
+// Copyright 2020 The SQLFlow Authors. All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package artifact keeps the generated programs, the IR, the outputs
+// and the timing of each executed statement in a directory, so that
+// users could inspect them after the request finishes or fails.
+//
+// The layout of the artifact directory of a request is:
+//
+//	<root>/<request>/<statement index>/statement.sql
+//	                                  /ir.json
+//	                                  /program_<n>.py
+//	                                  /command_<n>.stdout
+//	                                  /command_<n>.stderr
+//	                                  /timing.json
+//
+// All methods of a nil *Request or a nil *Statement are no-ops, so that
+// callers don't need to check whether artifacts are enabled.
+package artifact
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	// EnvDir is the environment variable to enable artifacts by
+	// specifying the root directory.
+	EnvDir = "SQLFLOW_ARTIFACT_DIR"
+	// EnvRetention is the environment variable specifying how long the
+	// artifacts are kept, e.g. "72h". The default is defaultRetention.
+	EnvRetention = "SQLFLOW_ARTIFACT_RETENTION"
+
+	defaultRetention = 7 * 24 * time.Hour
+)
+
+// Request is the artifact directory of a request.
+type Request struct {
+	Dir string
+}
+
+// NewRequestFromEnv creates the artifact directory of a request under
+// the root specified by SQLFLOW_ARTIFACT_DIR, and removes the expired
+// artifacts of previous requests. It returns nil if artifacts are not
+// enabled.
+func NewRequestFromEnv(requestID string) (*Request, error) {
+	root := os.Getenv(EnvDir)
+	if root == "" {
+		return nil, nil
+	}
+	retention := defaultRetention
+	if s := os.Getenv(EnvRetention); s != "" {
+		d, e := time.ParseDuration(s)
+		if e != nil {
+			return nil, fmt.Errorf("invalid %s %q: %v", EnvRetention, s, e)
+		}
+		retention = d
+	}
+	if e := Clean(root, retention); e != nil {
+		return nil, e
+	}
+	return NewRequest(root, requestID)
+}
+
+// NewRequest creates the artifact directory of a request under root.
+func NewRequest(root, requestID string) (*Request, error) {
+	dir := filepath.Join(root, fmt.Sprintf("%s-%s", time.Now().Format("20060102-150405"), requestID))
+	if e := os.MkdirAll(dir, 0755); e != nil {
+		return nil, fmt.Errorf("cannot create artifact directory %s: %v", dir, e)
+	}
+	return &Request{Dir: dir}, nil
+}
+
+// Clean removes the request directories under root which are older
+// than retention.
+func Clean(root string, retention time.Duration) error {
+	entries, e := ioutil.ReadDir(root)
+	if os.IsNotExist(e) {
+		return nil
+	}
+	if e != nil {
+		return fmt.Errorf("cannot list artifact directory %s: %v", root, e)
+	}
+	for _, fi := range entries {
+		if fi.IsDir() && time.Since(fi.ModTime()) > retention {
+			if e := os.RemoveAll(filepath.Join(root, fi.Name())); e != nil {
+				return fmt.Errorf("cannot remove expired artifacts %s: %v", fi.Name(), e)
+			}
+		}
+	}
+	return nil
+}
+
+// Statement creates the artifact directory of the idx-th statement of
+// the request and records the statement text.
+func (r *Request) Statement(idx int, sql string) (*Statement, error) {
+	if r == nil {
+		return nil, nil
+	}
+	dir := filepath.Join(r.Dir, strconv.Itoa(idx))
+	if e := os.MkdirAll(dir, 0755); e != nil {
+		return nil, fmt.Errorf("cannot create artifact directory %s: %v", dir, e)
+	}
+	s := &Statement{Dir: dir, sql: sql, start: time.Now()}
+	if e := s.WriteFile("statement.sql", []byte(sql)); e != nil {
+		return nil, e
+	}
+	return s, nil
+}
+
+// Statement is the artifact directory of a statement.
+type Statement struct {
+	Dir      string
+	sql      string
+	start    time.Time
+	m        sync.Mutex
+	commands int
+	files    []*os.File
+}
+
+type timing struct {
+	Statement        string `json:"statement"`
+	StartTime        string `json:"start_time"`
+	EndTime          string `json:"end_time"`
+	SpentTimeSeconds int64  `json:"spent_time_seconds"`
+	Error            string `json:"error,omitempty"`
+}
+
+// WriteFile writes data to the file name in the statement directory.
+func (s *Statement) WriteFile(name string, data []byte) error {
+	if s == nil {
+		return nil
+	}
+	fn := filepath.Join(s.Dir, name)
+	if e := ioutil.WriteFile(fn, data, 0644); e != nil {
+		return fmt.Errorf("cannot write artifact %s: %v", fn, e)
+	}
+	return nil
+}
+
+// WriteIR writes the IR of the statement as JSON.
+func (s *Statement) WriteIR(stmt interface{}) error {
+	if s == nil {
+		return nil
+	}
+	b, e := json.MarshalIndent(stmt, "", "  ")
+	if e != nil {
+		// Keep the error for inspection rather than failing the statement.
+		b = []byte(fmt.Sprintf("cannot encode the IR as JSON: %v", e))
+	}
+	return s.WriteFile("ir.json", b)
+}
+
+// NewCommand records the program of the next command run for the
+// statement, if any, and returns the writers to keep the stdout and
+// stderr of the command.
+func (s *Statement) NewCommand(program string) (stdout, stderr io.Writer, e error) {
+	if s == nil {
+		return ioutil.Discard, ioutil.Discard, nil
+	}
+	s.m.Lock()
+	defer s.m.Unlock()
+	s.commands++
+	if program != "" {
+		if e := s.WriteFile(fmt.Sprintf("program_%d.py", s.commands), []byte(program)); e != nil {
+			return nil, nil, e
+		}
+	}
+	out, e := os.Create(filepath.Join(s.Dir, fmt.Sprintf("command_%d.stdout", s.commands)))
+	if e != nil {
+		return nil, nil, e
+	}
+	s.files = append(s.files, out)
+	errOut, e := os.Create(filepath.Join(s.Dir, fmt.Sprintf("command_%d.stderr", s.commands)))
+	if e != nil {
+		return nil, nil, e
+	}
+	s.files = append(s.files, errOut)
+	return out, errOut, nil
+}
+
+// Close writes the timing and the error, if any, of the statement and
+// closes the opened output files.
+func (s *Statement) Close(err error) error {
+	if s == nil {
+		return nil
+	}
+	s.m.Lock()
+	for _, f := range s.files {
+		f.Close()
+	}
+	s.files = nil
+	s.m.Unlock()
+
+	end := time.Now()
+	t := timing{
+		Statement:        s.sql,
+		StartTime:        s.start.Format(time.RFC3339Nano),
+		EndTime:          end.Format(time.RFC3339Nano),
+		SpentTimeSeconds: int64(end.Sub(s.start).Seconds()),
+	}
+	if err != nil {
+		t.Error = err.Error()
+	}
+	b, e := json.MarshalIndent(t, "", "  ")
+	if e != nil {
+		return e
+	}
+	return s.WriteFile("timing.json", b)
+}
@@ -0,0 +1,83 @@
+// Copyright 2020 The SQLFlow Authors. All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package artifact
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestArtifacts(t *testing.T) {
+	a := assert.New(t)
+	root, e := ioutil.TempDir("", "sqlflow_artifacts")
+	a.NoError(e)
+	defer os.RemoveAll(root)
+
+	r, e := NewRequest(root, "some-request")
+	a.NoError(e)
+	s, e := r.Statement(0, "SELECT 1;")
+	a.NoError(e)
+	a.NoError(s.WriteIR(map[string]interface{}{"Select": "SELECT 1;"}))
+	stdout, stderr, e := s.NewCommand("print('hello')")
+	a.NoError(e)
+	fmt.Fprint(stdout, "hello\n")
+	fmt.Fprint(stderr, "some warning\n")
+	a.NoError(s.Close(fmt.Errorf("some error")))
+
+	for name, content := range map[string]string{
+		"statement.sql":    "SELECT 1;",
+		"program_1.py":     "print('hello')",
+		"command_1.stdout": "hello\n",
+		"command_1.stderr": "some warning\n",
+	} {
+		b, e := ioutil.ReadFile(filepath.Join(s.Dir, name))
+		a.NoError(e)
+		a.Equal(content, string(b))
+	}
+	b, e := ioutil.ReadFile(filepath.Join(s.Dir, "timing.json"))
+	a.NoError(e)
+	tm := &timing{}
+	a.NoError(json.Unmarshal(b, tm))
+	a.Equal("SELECT 1;", tm.Statement)
+	a.Equal("some error", tm.Error)
+
+	// expired artifacts are removed
+	old := time.Now().Add(-2 * time.Hour)
+	a.NoError(os.Chtimes(r.Dir, old, old))
+	a.NoError(Clean(root, time.Hour))
+	_, e = os.Stat(r.Dir)
+	a.True(os.IsNotExist(e))
+}
+
+func TestNilArtifacts(t *testing.T) {
+	a := assert.New(t)
+	os.Setenv(EnvDir, "")
+	r, e := NewRequestFromEnv("some-request")
+	a.NoError(e)
+	a.Nil(r)
+	s, e := r.Statement(0, "SELECT 1;")
+	a.NoError(e)
+	a.Nil(s)
+	a.NoError(s.WriteIR(nil))
+	_, _, e = s.NewCommand("")
+	a.NoError(e)
+	a.NoError(s.Close(nil))
+}
@@ -0,0 +1,80 @@
+// Copyright 2020 The SQLFlow Authors. All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package auth authenticates the requests to the SQLFlow server by the
+// tokens sent by the clients.
+package auth
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+)
+
+// Identity is the verified identity of a client.
+type Identity struct {
+	UserID string
+}
+
+// Verifier verifies a token and returns the identity it represents.
+type Verifier interface {
+	Verify(ctx context.Context, token string) (*Identity, error)
+}
+
+type identityKey struct{}
+
+// NewContext returns a context carrying id.
+func NewContext(ctx context.Context, id *Identity) context.Context {
+	return context.WithValue(ctx, identityKey{}, id)
+}
+
+// FromContext returns the identity in ctx, or nil if the request is not
+// authenticated.
+func FromContext(ctx context.Context) *Identity {
+	id, _ := ctx.Value(identityKey{}).(*Identity)
+	return id
+}
+
+// NewVerifierFromEnv returns the verifier configured by the environment
+// variable SQLFLOW_AUTH, which is one of:
+//
+//   - "static": verifies the tokens listed in the file SQLFLOW_AUTH_TOKEN_FILE.
+//   - "jwt": verifies the JWTs signed by the HMAC secret in the file
+//     SQLFLOW_AUTH_JWT_SECRET_FILE, the user ID is the claim
+//     SQLFLOW_AUTH_JWT_USER_CLAIM, "sub" by default.
+//   - "command": verifies the tokens by running SQLFLOW_AUTH_COMMAND.
+//
+// It returns nil if SQLFLOW_AUTH is empty, which disables authentication.
+func NewVerifierFromEnv() (Verifier, error) {
+	switch kind := os.Getenv("SQLFLOW_AUTH"); kind {
+	case "":
+		return nil, nil
+	case "static":
+		return NewStaticVerifier(os.Getenv("SQLFLOW_AUTH_TOKEN_FILE"))
+	case "jwt":
+		secretFile := os.Getenv("SQLFLOW_AUTH_JWT_SECRET_FILE")
+		if secretFile == "" {
+			return nil, fmt.Errorf("SQLFLOW_AUTH_JWT_SECRET_FILE is required by the jwt authentication")
+		}
+		return NewJWTVerifier(secretFile, os.Getenv("SQLFLOW_AUTH_JWT_USER_CLAIM"))
+	case "command":
+		cmd := os.Getenv("SQLFLOW_AUTH_COMMAND")
+		if cmd == "" {
+			return nil, fmt.Errorf("SQLFLOW_AUTH_COMMAND is required by the command authentication")
+		}
+		return NewCommandVerifier(cmd, 10*time.Second), nil
+	default:
+		return nil, fmt.Errorf("unsupported SQLFLOW_AUTH %s, expecting static, jwt or command", kind)
+	}
+}
@@ -0,0 +1,156 @@
+// Copyright 2020 The SQLFlow Authors. All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func writeTempFile(t *testing.T, dir, name, content string) string {
+	p := filepath.Join(dir, name)
+	assert.NoError(t, ioutil.WriteFile(p, []byte(content), 0600))
+	return p
+}
+
+func TestStaticVerifier(t *testing.T) {
+	a := assert.New(t)
+	dir, e := ioutil.TempDir("/tmp", "sqlflow_auth")
+	a.NoError(e)
+	defer os.RemoveAll(dir)
+
+	f := writeTempFile(t, dir, "tokens", "# token user\n\ntoken_a alice\n  token_b   bob  \n")
+	v, e := NewStaticVerifier(f)
+	a.NoError(e)
+	id, e := v.Verify(context.Background(), "token_a")
+	a.NoError(e)
+	a.Equal("alice", id.UserID)
+	id, e = v.Verify(context.Background(), "token_b")
+	a.NoError(e)
+	a.Equal("bob", id.UserID)
+	_, e = v.Verify(context.Background(), "token_c")
+	a.Error(e)
+	_, e = v.Verify(context.Background(), "")
+	a.Error(e)
+
+	_, e = NewStaticVerifier(writeTempFile(t, dir, "bad_tokens", "token_a\n"))
+	a.Error(e)
+	_, e = NewStaticVerifier(filepath.Join(dir, "no_such_file"))
+	a.Error(e)
+}
+
+func signJWT(secret, header, claims string) string {
+	enc := base64.RawURLEncoding
+	s := enc.EncodeToString([]byte(header)) + "." + enc.EncodeToString([]byte(claims))
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(s))
+	return s + "." + enc.EncodeToString(mac.Sum(nil))
+}
+
+func TestJWTVerifier(t *testing.T) {
+	a := assert.New(t)
+	dir, e := ioutil.TempDir("/tmp", "sqlflow_auth")
+	a.NoError(e)
+	defer os.RemoveAll(dir)
+
+	v, e := NewJWTVerifier(writeTempFile(t, dir, "secret", "my_secret\n"), "")
+	a.NoError(e)
+	v.now = func() time.Time { return time.Unix(1000, 0) }
+	hs256 := `{"alg":"HS256","typ":"JWT"}`
+
+	id, e := v.Verify(context.Background(), signJWT("my_secret", hs256, `{"sub":"alice","exp":2000,"nbf":500}`))
+	a.NoError(e)
+	a.Equal("alice", id.UserID)
+
+	for _, token := range []string{
+		signJWT("other_secret", hs256, `{"sub":"alice"}`),
+		signJWT("my_secret", hs256, `{"sub":"alice","exp":1000}`),
+		signJWT("my_secret", hs256, `{"sub":"alice","nbf":1500}`),
+		signJWT("my_secret", hs256, `{"name":"alice"}`),
+		signJWT("my_secret", `{"alg":"none"}`, `{"sub":"alice"}`),
+		"not.a.jwt",
+		"",
+	} {
+		_, e = v.Verify(context.Background(), token)
+		a.Error(e, token)
+	}
+
+	v, e = NewJWTVerifier(filepath.Join(dir, "secret"), "email")
+	a.NoError(e)
+	id, e = v.Verify(context.Background(), signJWT("my_secret", hs256, `{"sub":"1","email":"alice@example.com"}`))
+	a.NoError(e)
+	a.Equal("alice@example.com", id.UserID)
+
+	_, e = NewJWTVerifier(writeTempFile(t, dir, "empty", "\n"), "")
+	a.Error(e)
+}
+
+func TestCommandVerifier(t *testing.T) {
+	a := assert.New(t)
+	dir, e := ioutil.TempDir("/tmp", "sqlflow_auth")
+	a.NoError(e)
+	defer os.RemoveAll(dir)
+
+	script := writeTempFile(t, dir, "verify.sh", `read token
+if [ "$token" = "token_a" ]; then echo alice; exit 0; fi
+echo "unknown token" >&2
+exit 1
+`)
+	v := NewCommandVerifier("sh "+script, 5*time.Second)
+	id, e := v.Verify(context.Background(), "token_a")
+	a.NoError(e)
+	a.Equal("alice", id.UserID)
+	_, e = v.Verify(context.Background(), "token_b")
+	a.Error(e)
+	a.Contains(e.Error(), "unknown token")
+
+	v = NewCommandVerifier("sleep 10", 100*time.Millisecond)
+	_, e = v.Verify(context.Background(), "token_a")
+	a.Error(e)
+}
+
+func TestNewVerifierFromEnv(t *testing.T) {
+	a := assert.New(t)
+	defer os.Unsetenv("SQLFLOW_AUTH")
+	defer os.Unsetenv("SQLFLOW_AUTH_COMMAND")
+
+	os.Setenv("SQLFLOW_AUTH", "")
+	v, e := NewVerifierFromEnv()
+	a.NoError(e)
+	a.Nil(v)
+
+	os.Setenv("SQLFLOW_AUTH", "command")
+	os.Setenv("SQLFLOW_AUTH_COMMAND", "")
+	_, e = NewVerifierFromEnv()
+	a.Error(e)
+	os.Setenv("SQLFLOW_AUTH_COMMAND", "cat")
+	v, e = NewVerifierFromEnv()
+	a.NoError(e)
+	id, e := v.Verify(context.Background(), "alice")
+	a.NoError(e)
+	a.Equal("alice", id.UserID)
+
+	os.Setenv("SQLFLOW_AUTH", "unknown")
+	_, e = NewVerifierFromEnv()
+	a.Error(e)
+}
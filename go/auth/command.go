@@ -0,0 +1,57 @@
+// Copyright 2020 The SQLFlow Authors. All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// CommandVerifier verifies the tokens by an external command, which reads
+// the token from stdin, and prints the user ID to stdout and exits with 0
+// if the token is valid.
+type CommandVerifier struct {
+	args    []string
+	timeout time.Duration
+}
+
+// NewCommandVerifier returns a CommandVerifier running cmd, a program and
+// its arguments separated by spaces, which is killed after timeout.
+func NewCommandVerifier(cmd string, timeout time.Duration) *CommandVerifier {
+	return &CommandVerifier{args: strings.Fields(cmd), timeout: timeout}
+}
+
+// Verify implements Verifier.
+func (v *CommandVerifier) Verify(ctx context.Context, token string) (*Identity, error) {
+	ctx, cancel := context.WithTimeout(ctx, v.timeout)
+	defer cancel()
+	// NOTE: pass the token by stdin instead of the arguments, which are
+	// visible to the other users by ps.
+	cmd := exec.CommandContext(ctx, v.args[0], v.args[1:]...)
+	cmd.Stdin = strings.NewReader(token)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	if e := cmd.Run(); e != nil {
+		return nil, fmt.Errorf("invalid token: %v %s", e, strings.TrimSpace(stderr.String()))
+	}
+	user := strings.TrimSpace(stdout.String())
+	if user == "" {
+		return nil, fmt.Errorf("invalid token: %s prints no user ID", v.args[0])
+	}
+	return &Identity{UserID: user}, nil
+}
@@ -0,0 +1,127 @@
+// Copyright 2020 The SQLFlow Authors. All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"context"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"sqlflow.org/sqlflow/go/log"
+	pb "sqlflow.org/sqlflow/go/proto"
+)
+
+// UnaryServerInterceptor authenticates the unary requests by v.
+func UnaryServerInterceptor(v Verifier) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if isPublicMethod(info.FullMethod) {
+			return handler(ctx, req)
+		}
+		ctx, e := authenticate(ctx, v, info.FullMethod, req)
+		if e != nil {
+			return nil, e
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor authenticates each message received by the
+// streaming requests by v.
+func StreamServerInterceptor(v Verifier) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if isPublicMethod(info.FullMethod) {
+			return handler(srv, ss)
+		}
+		return handler(srv, &authStream{ServerStream: ss, verifier: v, method: info.FullMethod, ctx: ss.Context()})
+	}
+}
+
+type authStream struct {
+	grpc.ServerStream
+	verifier Verifier
+	method   string
+	ctx      context.Context
+}
+
+func (s *authStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *authStream) RecvMsg(m interface{}) error {
+	if e := s.ServerStream.RecvMsg(m); e != nil {
+		return e
+	}
+	ctx, e := authenticate(s.ServerStream.Context(), s.verifier, s.method, m)
+	if e != nil {
+		return e
+	}
+	s.ctx = ctx
+	return nil
+}
+
+// isPublicMethod returns true for the gRPC builtin services like reflection
+// and health checking, which don't require authentication.
+func isPublicMethod(method string) bool {
+	return strings.HasPrefix(method, "/grpc.")
+}
+
+type sessionGetter interface {
+	GetSession() *pb.Session
+}
+
+// authenticate verifies the token of the request req, and overwrites the
+// user ID in its session by the verified identity. The token is the
+// Session.Token of req if any, or the gRPC metadata authorization.
+func authenticate(ctx context.Context, v Verifier, method string, req interface{}) (context.Context, error) {
+	var session *pb.Session
+	if g, ok := req.(sessionGetter); ok {
+		session = g.GetSession()
+	}
+	token := ""
+	if session != nil {
+		token = session.Token
+	}
+	if token == "" {
+		token = metadataToken(ctx)
+	}
+	if token == "" {
+		return nil, status.Errorf(codes.Unauthenticated, "token is required by %s", method)
+	}
+	id, e := v.Verify(ctx, token)
+	if e != nil {
+		log.WithFields(log.Fields{"event": "authenticate", "method": method}).Errorf("%v", e)
+		return nil, status.Errorf(codes.Unauthenticated, "invalid token")
+	}
+	if session != nil {
+		session.UserId = id.UserID
+	}
+	return NewContext(ctx, id), nil
+}
+
+// metadataToken returns the token in the gRPC metadata authorization, with
+// or without the prefix "Bearer ".
+func metadataToken(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	values := md.Get("authorization")
+	if len(values) == 0 {
+		return ""
+	}
+	return strings.TrimSpace(strings.TrimPrefix(values[0], "Bearer "))
+}
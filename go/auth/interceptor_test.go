@@ -0,0 +1,94 @@
+// Copyright 2020 The SQLFlow Authors. All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	pb "sqlflow.org/sqlflow/go/proto"
+)
+
+type mockServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+	req *pb.Request
+}
+
+func (s *mockServerStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *mockServerStream) RecvMsg(m interface{}) error {
+	proto.Merge(m.(*pb.Request), s.req)
+	return nil
+}
+
+func TestInterceptors(t *testing.T) {
+	a := assert.New(t)
+	v := &StaticVerifier{users: map[[sha256.Size]byte]string{sha256.Sum256([]byte("token_a")): "alice"}}
+	unary := UnaryServerInterceptor(v)
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return FromContext(ctx), nil
+	}
+
+	// the user ID in the session is overwritten by the verified one
+	req := &pb.Request{Session: &pb.Session{Token: "token_a", UserId: "bob"}}
+	id, e := unary(context.Background(), req, &grpc.UnaryServerInfo{FullMethod: "/proto.SQLFlow/Submit"}, handler)
+	a.NoError(e)
+	a.Equal("alice", id.(*Identity).UserID)
+	a.Equal("alice", req.Session.UserId)
+
+	// the token could be in the metadata if the request has no session
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer token_a"))
+	id, e = unary(ctx, &pb.Job{Id: "job"}, &grpc.UnaryServerInfo{FullMethod: "/proto.SQLFlow/Cancel"}, handler)
+	a.NoError(e)
+	a.Equal("alice", id.(*Identity).UserID)
+
+	_, e = unary(context.Background(), &pb.Job{Id: "job"}, &grpc.UnaryServerInfo{FullMethod: "/proto.SQLFlow/Cancel"}, handler)
+	a.Equal(codes.Unauthenticated, status.Code(e))
+	req = &pb.Request{Session: &pb.Session{Token: "token_b"}}
+	_, e = unary(context.Background(), req, &grpc.UnaryServerInfo{FullMethod: "/proto.SQLFlow/Submit"}, handler)
+	a.Equal(codes.Unauthenticated, status.Code(e))
+	// the error of the verifier is logged but not sent to the client
+	a.Equal("invalid token", status.Convert(e).Message())
+	id, e = unary(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: "/grpc.health.v1.Health/Check"}, handler)
+	a.NoError(e)
+	a.Nil(id)
+
+	stream := StreamServerInterceptor(v)
+	ss := &mockServerStream{ctx: context.Background(), req: &pb.Request{Session: &pb.Session{Token: "token_a", UserId: "bob"}}}
+	e = stream(nil, ss, &grpc.StreamServerInfo{FullMethod: "/proto.SQLFlow/Run"}, func(srv interface{}, s grpc.ServerStream) error {
+		recv := &pb.Request{}
+		if e := s.RecvMsg(recv); e != nil {
+			return e
+		}
+		a.Equal("alice", recv.Session.UserId)
+		a.Equal("alice", FromContext(s.Context()).UserID)
+		return nil
+	})
+	a.NoError(e)
+	ss.req = &pb.Request{Session: &pb.Session{Token: "token_b"}}
+	e = stream(nil, ss, &grpc.StreamServerInfo{FullMethod: "/proto.SQLFlow/Run"}, func(srv interface{}, s grpc.ServerStream) error {
+		return s.RecvMsg(&pb.Request{})
+	})
+	a.Equal(codes.Unauthenticated, status.Code(e))
+}
@@ -0,0 +1,115 @@
+// Copyright 2020 The SQLFlow Authors. All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"io/ioutil"
+	"strings"
+	"time"
+)
+
+// JWTVerifier verifies the JSON Web Tokens signed by an HMAC secret.
+type JWTVerifier struct {
+	secret    []byte
+	userClaim string
+	now       func() time.Time
+}
+
+// NewJWTVerifier loads the HMAC secret from secretFile. The user ID is the
+// claim userClaim of a token, "sub" if userClaim is empty.
+func NewJWTVerifier(secretFile, userClaim string) (*JWTVerifier, error) {
+	secret, e := ioutil.ReadFile(secretFile)
+	if e != nil {
+		return nil, fmt.Errorf("cannot load the JWT secret: %v", e)
+	}
+	secret = bytes.TrimSpace(secret)
+	if len(secret) == 0 {
+		return nil, fmt.Errorf("the JWT secret in %s is empty", secretFile)
+	}
+	if userClaim == "" {
+		userClaim = "sub"
+	}
+	return &JWTVerifier{secret: secret, userClaim: userClaim, now: time.Now}, nil
+}
+
+var jwtHashes = map[string]func() hash.Hash{
+	"HS256": sha256.New,
+	"HS384": sha512.New384,
+	"HS512": sha512.New,
+}
+
+// Verify implements Verifier. It checks the signature and the claims exp
+// and nbf if they exist.
+func (v *JWTVerifier) Verify(ctx context.Context, token string) (*Identity, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("invalid token: malformed JWT")
+	}
+	var header struct {
+		Alg string `json:"alg"`
+	}
+	if e := decodeJWTPart(parts[0], &header); e != nil {
+		return nil, e
+	}
+	newHash, ok := jwtHashes[header.Alg]
+	if !ok {
+		return nil, fmt.Errorf("invalid token: unsupported JWT algorithm %q", header.Alg)
+	}
+	sig, e := base64.RawURLEncoding.DecodeString(parts[2])
+	if e != nil {
+		return nil, fmt.Errorf("invalid token: %v", e)
+	}
+	mac := hmac.New(newHash, v.secret)
+	mac.Write([]byte(parts[0] + "." + parts[1]))
+	if !hmac.Equal(sig, mac.Sum(nil)) {
+		return nil, fmt.Errorf("invalid token: signature mismatch")
+	}
+
+	claims := map[string]interface{}{}
+	if e := decodeJWTPart(parts[1], &claims); e != nil {
+		return nil, e
+	}
+	now := v.now().Unix()
+	if exp, ok := claims["exp"].(float64); ok && now >= int64(exp) {
+		return nil, fmt.Errorf("invalid token: expired")
+	}
+	if nbf, ok := claims["nbf"].(float64); ok && now < int64(nbf) {
+		return nil, fmt.Errorf("invalid token: not valid yet")
+	}
+	user, ok := claims[v.userClaim].(string)
+	if !ok || user == "" {
+		return nil, fmt.Errorf("invalid token: no claim %s", v.userClaim)
+	}
+	return &Identity{UserID: user}, nil
+}
+
+func decodeJWTPart(part string, v interface{}) error {
+	b, e := base64.RawURLEncoding.DecodeString(part)
+	if e != nil {
+		return fmt.Errorf("invalid token: %v", e)
+	}
+	if e := json.Unmarshal(b, v); e != nil {
+		return fmt.Errorf("invalid token: %v", e)
+	}
+	return nil
+}
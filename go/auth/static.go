@@ -0,0 +1,70 @@
+// Copyright 2020 The SQLFlow Authors. All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// StaticVerifier verifies the tokens listed in a file.
+type StaticVerifier struct {
+	// users maps the SHA256 of the tokens to the user IDs, so that the
+	// lookup time doesn't depend on the prefix shared with a valid token.
+	users map[[sha256.Size]byte]string
+}
+
+// NewStaticVerifier loads the tokens in file. Each line of the file is a
+// token and the user ID it represents separated by spaces. The empty lines
+// and the lines starting with # are ignored.
+func NewStaticVerifier(file string) (*StaticVerifier, error) {
+	if file == "" {
+		return nil, fmt.Errorf("token file is required by the static authentication")
+	}
+	f, e := os.Open(file)
+	if e != nil {
+		return nil, fmt.Errorf("cannot load tokens: %v", e)
+	}
+	defer f.Close()
+	v := &StaticVerifier{users: make(map[[sha256.Size]byte]string)}
+	scanner := bufio.NewScanner(f)
+	for lineno := 1; scanner.Scan(); lineno++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("%s:%d: expecting a token and a user ID", file, lineno)
+		}
+		v.users[sha256.Sum256([]byte(fields[0]))] = fields[1]
+	}
+	if e := scanner.Err(); e != nil {
+		return nil, fmt.Errorf("cannot load tokens: %v", e)
+	}
+	return v, nil
+}
+
+// Verify implements Verifier.
+func (v *StaticVerifier) Verify(ctx context.Context, token string) (*Identity, error) {
+	user, ok := v.users[sha256.Sum256([]byte(token))]
+	if !ok {
+		return nil, fmt.Errorf("invalid token")
+	}
+	return &Identity{UserID: user}, nil
+}
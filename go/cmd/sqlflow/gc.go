@@ -0,0 +1,65 @@
+// Copyright 2020 The SQLFlow Authors. All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"sqlflow.org/sqlflow/go/database"
+	"sqlflow.org/sqlflow/go/gc"
+)
+
+func collectGarbage(opts *options) error {
+	if opts.DataSource == "" {
+		opts.DataSource = os.Getenv("SQLFLOW_DATASOURCE")
+		if opts.DataSource == "" {
+			return fmt.Errorf("You should specify a datasource with -d or set env SQLFLOW_DATASOURCE")
+		}
+	}
+	policy := &gc.Policy{DryRun: opts.DryRun}
+	var err error
+	if policy.TmpTableAge, err = time.ParseDuration(opts.TmpTableAge); err != nil {
+		return fmt.Errorf("invalid --tmp-table-age: %v", err)
+	}
+	if policy.ModelAge, err = time.ParseDuration(opts.ModelAge); err != nil {
+		return fmt.Errorf("invalid --model-age: %v", err)
+	}
+	dbName := opts.Database
+	if dbName == "" {
+		if dbName, err = database.GetDatabaseName(opts.DataSource); err != nil {
+			return err
+		}
+	}
+	db, err := database.OpenDB(opts.DataSource)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	garbage, err := gc.Collect(db, dbName, policy)
+	if err != nil {
+		return err
+	}
+	for _, g := range garbage {
+		fmt.Printf("%s\t%s\t%s\n", g.CreatedAt.Format("2006-01-02 15:04:05"), g.Kind, g.Table)
+	}
+	if opts.DryRun {
+		fmt.Printf("%d tables to drop, run without --dry-run to drop them\n", len(garbage))
+	} else {
+		fmt.Printf("%d tables dropped\n", len(garbage))
+	}
+	return nil
+}
@@ -65,6 +65,7 @@ Usage:
     sqlflow [options] delete model <model_name> <version>
     sqlflow [options] list repo
     sqlflow [options] list model
+    sqlflow [options] gc [--dry-run] [--tmp-table-age=<age>] [--model-age=<age>] [<database>]
 
 Options:
     -v, --version                   	print the version and exit
@@ -84,7 +85,12 @@ Run Options:
 Release Options:
         --force                  force overwrite existing model
         --local                  release a model stores in a database that can be connected from local
-        --desc=<desc>            description for this model`
+        --desc=<desc>            description for this model
+
+GC Options:
+        --dry-run                list the tables to drop without dropping
+        --tmp-table-age=<age>    drop temporary and orphaned model tables older than age [default: 24h]
+        --model-age=<age>        drop model versions older than age, e.g. 720h, keep all if 0 [default: 0]`
 
 type options struct {
 	CertFile, EnvFile    string
@@ -107,6 +113,11 @@ type options struct {
 	List                 bool
 	User                 string
 	Password             string
+	GC                   bool   `docopt:"gc"`
+	DryRun               bool   `docopt:"--dry-run"`
+	TmpTableAge          string `docopt:"--tmp-table-age"`
+	ModelAge             string `docopt:"--model-age"`
+	Database             string `docopt:"<database>"`
 }
 
 func isSpace(c byte) bool {
@@ -464,6 +475,8 @@ func processOptions(opts *options) {
 		err = listRepos(opts)
 	case opts.Get && opts.Model:
 		err = downloadModelFromDB(opts)
+	case opts.GC:
+		err = collectGarbage(opts)
 	default:
 		err = runSQLFlowClient(opts)
 	}
@@ -109,7 +109,7 @@ func releaseModelFromLocal(opts *options) error {
 	if err != nil {
 		return err
 	}
-	model, err := model.ExtractMetaFromTarball(tarFile, dir)
+	model, err := model.ExtractMetaFromTarball(tarFile)
 	if err != nil {
 		return err
 	}
@@ -52,6 +52,20 @@ func render(ctx *renderContext, obj interface{}) error {
 		renderObj = arr
 	case *proto.Response_Eoe:
 	case *proto.Response_Job:
+	case *proto.Response_Progress:
+		renderObj = executor.Progress{
+			Epoch:      r.Progress.Epoch,
+			Step:       r.Progress.Step,
+			Metric:     r.Progress.Metric,
+			Value:      r.Progress.Value,
+			ETASeconds: r.Progress.EtaSeconds,
+		}
+	case *proto.Response_QueueStatus:
+		if r.QueueStatus.Status == "Queued" {
+			renderObj = fmt.Sprintf("Queued, %d requests ahead", r.QueueStatus.Ahead)
+		} else {
+			renderObj = r.QueueStatus.Status
+		}
 	case *proto.Response_Message:
 		re := regexp.MustCompile(`<div.*?>.*</div>`)
 		if re.MatchString(r.Message.Message) {
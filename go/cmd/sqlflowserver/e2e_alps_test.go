@@ -47,7 +47,7 @@ func TestEnd2EndMaxComputeALPS(t *testing.T) {
 		t.Fatalf("Must set env SQLFLOW_TEST_DB_MAXCOMPUTE_PROJECT when testing ALPS cases (SQLFLOW_submitter=alps)!!")
 	}
 
-	go start(caCrt, caKey, unitTestPort, false)
+	go start(options{caCrt: caCrt, caKey: caKey, port: unitTestPort})
 	server.WaitPortReady(fmt.Sprintf("localhost:%d", unitTestPort), 0)
 
 	t.Run("CaseTrainALPS", CaseTrainALPS)
@@ -351,9 +351,10 @@ func caseShowTrain(t *testing.T) {
 	showSQL := `SHOW TRAIN sqlflow_models.my_xgb_model_for_show_train;`
 	cols, _, _, err := connectAndRunSQL(showSQL)
 	a.NoError(err)
-	a.Equal(2, len(cols))
+	a.Equal(3, len(cols))
 	a.Equal("Model", cols[0])
 	a.Equal("Train Statement", cols[1])
+	a.Equal("Lineage", cols[2])
 }
 
 func caseTrainSQL(t *testing.T) {
@@ -34,7 +34,7 @@ func TestEnd2EndHive(t *testing.T) {
 		t.Skip("Skipping hive tests")
 	}
 	dbConnStr = database.GetTestingHiveURL()
-	go start(caCrt, caKey, unitTestPort, false)
+	go start(options{caCrt: caCrt, caKey: caKey, port: unitTestPort})
 	server.WaitPortReady(fmt.Sprintf("localhost:%d", unitTestPort), 0)
 	err = prepareTestData(dbConnStr)
 	if err != nil {
@@ -40,7 +40,7 @@ func TestEnd2EndMaxCompute(t *testing.T) {
 	SK := os.Getenv("SQLFLOW_TEST_DB_MAXCOMPUTE_SK")
 	endpoint := os.Getenv("SQLFLOW_TEST_DB_MAXCOMPUTE_ENDPOINT")
 	dbConnStr = fmt.Sprintf("maxcompute://%s:%s@%s", AK, SK, endpoint)
-	go start(caCrt, caKey, unitTestPort, false)
+	go start(options{caCrt: caCrt, caKey: caKey, port: unitTestPort})
 	server.WaitPortReady(fmt.Sprintf("localhost:%d", unitTestPort), 0)
 
 	caseDB = os.Getenv("SQLFLOW_TEST_DB_MAXCOMPUTE_PROJECT")
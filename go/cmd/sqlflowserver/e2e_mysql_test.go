@@ -161,8 +161,8 @@ func TestEnd2EndMySQL(t *testing.T) {
 	// if err != nil {
 	// 	t.Fatalf("failed to generate CA pair %v", err)
 	// }
-	// go start(caCrt, caKey, unitTestPort, false)
-	go start("", "", unitTestPort, false)
+	// go start(options{caCrt: caCrt, caKey: caKey, port: unitTestPort})
+	go start(options{port: unitTestPort})
 	server.WaitPortReady(fmt.Sprintf("localhost:%d", unitTestPort), 0)
 	err := prepareTestData(dbConnStr)
 	if err != nil {
@@ -528,7 +528,7 @@ func TestEnd2EndMaxComputePAI(t *testing.T) {
 		t.FailNow()
 	}
 
-	go start(caCrt, caKey, unitTestPort, false)
+	go start(options{caCrt: caCrt, caKey: caKey, port: unitTestPort})
 	server.WaitPortReady(fmt.Sprintf("localhost:%d", unitTestPort), 0)
 
 	t.Run("group", func(t *testing.T) {
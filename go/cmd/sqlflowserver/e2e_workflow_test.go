@@ -49,7 +49,7 @@ func TestEnd2EndWorkflow(t *testing.T) {
 		t.Fatalf("failed to generate CA pair %v", err)
 	}
 
-	go start(caCrt, caKey, unitTestPort, true)
+	go start(options{caCrt: caCrt, caKey: caKey, port: unitTestPort, argoMode: true})
 	server.WaitPortReady(fmt.Sprintf("localhost:%d", unitTestPort), 0)
 
 	if driverName == "maxcompute" {
@@ -374,7 +374,7 @@ func TestEnd2EndFluidWorkflow(t *testing.T) {
 
 	//TODO(yancey1989): using the same end-to-end workflow test with the Couler backend
 	os.Setenv("SQLFLOW_WORKFLOW_BACKEND", "fluid")
-	go start(caCrt, caKey, unitTestPort, true)
+	go start(options{caCrt: caCrt, caKey: caKey, port: unitTestPort, argoMode: true})
 	server.WaitPortReady(fmt.Sprintf("localhost:%d", unitTestPort), 0)
 	if err != nil {
 		t.Fatalf("prepare test dataset failed: %v", err)
@@ -17,50 +17,194 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"net"
+	"net/http"
+	"os"
+	"time"
 
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/reflection"
 
+	"sqlflow.org/sqlflow/go/auth"
+	"sqlflow.org/sqlflow/go/gateway"
+	"sqlflow.org/sqlflow/go/health"
 	"sqlflow.org/sqlflow/go/log"
+	"sqlflow.org/sqlflow/go/metrics"
+	"sqlflow.org/sqlflow/go/parser/external"
 	"sqlflow.org/sqlflow/go/proto"
 	sf "sqlflow.org/sqlflow/go/sql"
 	server "sqlflow.org/sqlflow/go/sqlflowserver"
+	"sqlflow.org/sqlflow/go/tracing"
 )
 
+// stopTimeout bounds the time for the pending RPCs, e.g., Fetch, to finish
+// after the running requests are done.
+const stopTimeout = 5 * time.Second
+
+// options configures the servers started by start.
+type options struct {
+	caCrt string
+	caKey string
+	// tlsServerName is the name to verify the TLS certifications of the
+	// gRPC servers called by the HTTP gateway, the dialed host if empty.
+	tlsServerName string
+	port          int
+	httpPort      int // 0 disables the HTTP gateway
+	metricsPort   int // 0 disables the metrics server
+	modelZooAddr  string
+	argoMode      bool
+}
+
 func newServer(caCrt, caKey string, logger *log.Logger) (*grpc.Server, error) {
-	var s *grpc.Server
+	opts := []grpc.ServerOption{}
+	verifier, err := auth.NewVerifierFromEnv()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the token verifier: %v", err)
+	}
+	if verifier != nil {
+		opts = append(opts,
+			grpc.UnaryInterceptor(auth.UnaryServerInterceptor(verifier)),
+			grpc.StreamInterceptor(auth.StreamServerInterceptor(verifier)))
+		logger.Infof("Launch server with %s token authentication.", os.Getenv("SQLFLOW_AUTH"))
+	}
 	if caCrt != "" && caKey != "" {
 		creds, err := credentials.NewServerTLSFromFile(caCrt, caKey)
 		if err != nil {
 			return nil, fmt.Errorf("failed to load CA crt/key files: %s, %s, %v", caCrt, caKey, err)
 		}
-		s = grpc.NewServer(grpc.Creds(creds))
+		opts = append(opts, grpc.Creds(creds))
 		logger.Info("Launch server with SSL/TLS certification.")
 	} else {
-		s = grpc.NewServer()
 		logger.Info("Launch server with insecure mode.")
 	}
-	return s, nil
+	return grpc.NewServer(opts...), nil
+}
+
+// dialOption returns the option to dial the gRPC servers by TLS with the CA
+// certification caCrt, or insecurely if caCrt or caKey is empty. The server
+// certification is verified against serverName if it isn't empty, or the
+// host of the dialed address otherwise.
+func dialOption(caCrt, caKey, serverName string) (grpc.DialOption, error) {
+	if caCrt == "" || caKey == "" {
+		return grpc.WithInsecure(), nil
+	}
+	creds, err := credentials.NewClientTLSFromFile(caCrt, serverName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load CA crt file: %s, %v", caCrt, err)
+	}
+	return grpc.WithTransportCredentials(creds), nil
+}
+
+// startGateway serves the HTTP gateway at opts.httpPort, which calls the
+// gRPC server at opts.port, and the model zoo server at opts.modelZooAddr if
+// it isn't empty. The gateway uses the same TLS certification as the gRPC
+// server. It returns the HTTP server to be shut down.
+func startGateway(opts options, logger *log.Logger) *http.Server {
+	opt, err := dialOption(opts.caCrt, opts.caKey, opts.tlsServerName)
+	if err != nil {
+		logger.Fatalf("%v", err)
+	}
+	conn, err := grpc.Dial(fmt.Sprintf("localhost:%d", opts.port), opt)
+	if err != nil {
+		logger.Fatalf("failed to connect to the gRPC server: %v", err)
+	}
+	var modelZoo proto.ModelZooServerClient
+	if opts.modelZooAddr != "" {
+		mzConn, err := grpc.Dial(opts.modelZooAddr, opt)
+		if err != nil {
+			logger.Fatalf("failed to connect to the model zoo server: %v", err)
+		}
+		modelZoo = proto.NewModelZooServerClient(mzConn)
+	}
+	hs := &http.Server{
+		Addr:    fmt.Sprintf(":%d", opts.httpPort),
+		Handler: gateway.New(proto.NewSQLFlowClient(conn), modelZoo),
+	}
+	logger.Infof("HTTP Gateway Started at %s", hs.Addr)
+	go func() {
+		var err error
+		if opts.caCrt != "" && opts.caKey != "" {
+			err = hs.ListenAndServeTLS(opts.caCrt, opts.caKey)
+		} else {
+			err = hs.ListenAndServe()
+		}
+		if err != http.ErrServerClosed {
+			logger.Fatalf("failed to serve the HTTP gateway: %v", err)
+		}
+	}()
+	return hs
+}
+
+// startMetrics serves the Prometheus metrics at /metrics of metricsPort. It
+// returns the HTTP server to be shut down.
+func startMetrics(metricsPort int, logger *log.Logger) *http.Server {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", metrics.Handler())
+	hs := &http.Server{Addr: fmt.Sprintf(":%d", metricsPort), Handler: mux}
+	logger.Infof("Metrics Started at %s/metrics", hs.Addr)
+	go func() {
+		if err := hs.ListenAndServe(); err != http.ErrServerClosed {
+			logger.Fatalf("failed to serve the metrics: %v", err)
+		}
+	}()
+	return hs
 }
 
-func start(caCrt, caKey string, port int, isArgoMode bool) {
+// shutdownHTTP stops hs after the pending HTTP requests finish, or after
+// timeout.
+func shutdownHTTP(hs *http.Server, timeout time.Duration, logger *log.Logger) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	if err := hs.Shutdown(ctx); err != nil {
+		logger.Errorf("failed to shut down the HTTP server at %s: %v", hs.Addr, err)
+	}
+}
+
+// newChecker checks the connectivity to the parser server, and to the
+// database SQLFLOW_DATASOURCE, or the database of the latest request of srv
+// if it is unset.
+func newChecker(srv *server.Server) *health.Checker {
+	checker := health.NewChecker("proto.SQLFlow")
+	checker.AddCheck("parser", func(ctx context.Context) error {
+		return external.CheckServer()
+	})
+	checker.AddCheck("database", srv.CheckDatabase)
+	return checker
+}
+
+func start(opts options) {
 	logger := log.GetDefaultLogger()
-	s, err := newServer(caCrt, caKey, logger)
+	if err := tracing.InitFromEnv(); err != nil {
+		logger.Fatalf("failed to initialize the tracing: %v", err)
+	}
+	gracePeriod, err := health.GracePeriodFromEnv()
+	if err != nil {
+		logger.Fatalf("%v", err)
+	}
+	s, err := newServer(opts.caCrt, opts.caKey, logger)
 	if err != nil {
 		logger.Fatalf("failed to create new gRPC Server: %v", err)
 	}
 
-	if isArgoMode {
-		proto.RegisterSQLFlowServer(s, server.NewServer(server.SubmitWorkflow))
+	var srv *server.Server
+	if opts.argoMode {
+		srv = server.NewArgoServer()
 	} else {
-		proto.RegisterSQLFlowServer(s, server.NewServer(sf.RunSQLProgram))
+		srv = server.NewServer(sf.RunSQLProgram)
 	}
+	proto.RegisterSQLFlowServer(s, srv)
+
+	checker := newChecker(srv)
+	checker.Register(s)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go checker.Run(ctx, health.DefaultInterval)
 
-	listenString := fmt.Sprintf(":%d", port)
+	listenString := fmt.Sprintf(":%d", opts.port)
 	lis, err := net.Listen("tcp", listenString)
 	if err != nil {
 		logger.Fatalf("failed to listen: %v", err)
@@ -68,19 +212,55 @@ func start(caCrt, caKey string, port int, isArgoMode bool) {
 
 	// Register reflection service on gRPC server.
 	reflection.Register(s)
+	var gatewayServer, metricsServer *http.Server
+	if opts.httpPort > 0 {
+		gatewayServer = startGateway(opts, logger)
+	}
+	if opts.metricsPort > 0 {
+		metricsServer = startMetrics(opts.metricsPort, logger)
+	}
 	logger.Infof("Server Started at %s", listenString)
-	if err := s.Serve(lis); err != nil {
-		logger.Fatalf("failed to serve: %v", err)
+	go func() {
+		if err := s.Serve(lis); err != nil {
+			logger.Fatalf("failed to serve: %v", err)
+		}
+	}()
+
+	sig := health.WaitForSignal()
+	logger.Infof("received %v, shutting down in %v", sig, gracePeriod)
+	checker.Drain()
+	shutdownCtx, cancelShutdown := context.WithTimeout(context.Background(), gracePeriod)
+	defer cancelShutdown()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		logger.Errorf("failed to shut down the running requests: %v", err)
+	}
+	// The gateway stops before the gRPC server, so that its pending requests
+	// could finish.
+	if gatewayServer != nil {
+		shutdownHTTP(gatewayServer, stopTimeout, logger)
+	}
+	health.StopServer(s, stopTimeout)
+	if metricsServer != nil {
+		shutdownHTTP(metricsServer, stopTimeout, logger)
+	}
+	if err := tracing.Shutdown(context.Background()); err != nil {
+		logger.Errorf("failed to shut down the tracing: %v", err)
 	}
+	logger.Info("Server stopped")
 }
 
 func main() {
 	logPath := flag.String("log", "", "path/to/log, e.g.: /var/log/sqlflow.log")
-	caCrt := flag.String("ca-crt", "", "CA certificate file.")
-	caKey := flag.String("ca-key", "", "CA private key file.")
-	port := flag.Int("port", 50051, "TCP port to listen on.")
-	isArgoMode := flag.Bool("argo-mode", false, "Enable Argo workflow model.")
+	var opts options
+	flag.StringVar(&opts.caCrt, "ca-crt", "", "CA certificate file.")
+	flag.StringVar(&opts.caKey, "ca-key", "", "CA private key file.")
+	flag.StringVar(&opts.tlsServerName, "tls-server-name", "", "Server name to verify the TLS certification of the gRPC servers called by the HTTP gateway, the dialed host by default.")
+	flag.IntVar(&opts.port, "port", 50051, "TCP port to listen on.")
+	flag.IntVar(&opts.httpPort, "http-port", 0, "TCP port of the HTTP gateway, 0 to disable it.")
+	flag.IntVar(&opts.metricsPort, "metrics-port", 0, "TCP port to serve the Prometheus metrics at /metrics, 0 to disable it.")
+	flag.StringVar(&opts.modelZooAddr, "model-zoo-addr", "", "Address of the model zoo server to be served by the HTTP gateway, e.g. localhost:50055.")
+	flag.BoolVar(&opts.argoMode, "argo-mode", false, "Enable Argo workflow model.")
 	flag.Parse()
 	log.InitLogger(*logPath, log.OrderedTextFormatter)
-	start(*caCrt, *caKey, *port, *isArgoMode)
+	start(opts)
 }
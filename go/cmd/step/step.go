@@ -14,6 +14,7 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"log"
 
@@ -21,6 +22,7 @@ import (
 	"sqlflow.org/sqlflow/go/sql"
 	"sqlflow.org/sqlflow/go/step"
 	"sqlflow.org/sqlflow/go/step/tablewriter"
+	"sqlflow.org/sqlflow/go/tracing"
 )
 
 const tablePageSize = 1000
@@ -47,7 +49,15 @@ func main() {
 	flag.StringVar(execute, "e", "", "execute SQLFlow from command line, short for --execute")
 	flag.Parse()
 
-	if e := run(*execute, sql.MakeSessionFromEnv()); e != nil {
+	if e := tracing.InitFromEnv(); e != nil {
+		log.Fatalf("failed to initialize the tracing: %v", e)
+	}
+	e := run(*execute, sql.MakeSessionFromEnv())
+	// export the spans before exiting
+	if err := tracing.Shutdown(context.Background()); err != nil {
+		log.Printf("failed to export the spans: %v", err)
+	}
+	if e != nil {
 		log.Fatal(e)
 	}
 }
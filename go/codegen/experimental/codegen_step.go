@@ -32,11 +32,16 @@ import (
 	"sqlflow.org/sqlflow/go/database"
 
 	"sqlflow.org/sqlflow/go/ir"
+	"sqlflow.org/sqlflow/go/model"
 	pb "sqlflow.org/sqlflow/go/proto"
+	"sqlflow.org/sqlflow/go/tracing"
 )
 
 // GenerateStepCodeAndImage generates step code and image
-func GenerateStepCodeAndImage(sqlStmt ir.SQLFlowStmt, stepIndex int, session *pb.Session, sqlStmts []ir.SQLFlowStmt) (string, string, error) {
+func GenerateStepCodeAndImage(sqlStmt ir.SQLFlowStmt, stepIndex int, session *pb.Session, sqlStmts []ir.SQLFlowStmt) (code string, image string, err error) {
+	_, span := tracing.StartWithTraceparent(session.GetTraceparent(), "codegen.GenerateStepCode")
+	span.SetAttribute("step", stepIndex)
+	defer func() { span.End(err) }()
 	switch stmt := sqlStmt.(type) {
 	case *ir.TrainStmt:
 		return generateTrainCodeAndImage(stmt, stepIndex, session)
@@ -243,6 +248,9 @@ func getModelMetadataFromDBImpl(dbConnStr, table string) (*Metadata, error) {
 		}
 		table = dbName + "." + table
 	}
+	if table, err = model.ResolveModelTable(db, table); err != nil {
+		return nil, err
+	}
 
 	fs, err := sqlfs.Open(db.DB, table, 1)
 	if err != nil {
@@ -16,6 +16,7 @@ package experimental
 import (
 	"bytes"
 	"encoding/json"
+	"fmt"
 	"text/template"
 
 	"sqlflow.org/sqlflow/go/ir"
@@ -37,6 +38,9 @@ type predStepFiller struct {
 
 // GeneratePredict generates the prediction code.
 func GeneratePredict(predStmt *ir.PredictStmt, stepIndex int, session *pb.Session) (string, error) {
+	if predStmt.ResultTable == "" {
+		return "", fmt.Errorf("TO PREDICT without INTO is not supported in the workflow mode yet, please specify the result table by INTO")
+	}
 	dbConnStr, err := GeneratePyDbConnStr(session)
 	if err != nil {
 		return "", err
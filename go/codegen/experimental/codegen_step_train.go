@@ -20,7 +20,9 @@ import (
 	"strings"
 	"text/template"
 
+	"sqlflow.org/sqlflow/go/database"
 	"sqlflow.org/sqlflow/go/ir"
+	"sqlflow.org/sqlflow/go/model"
 	pb "sqlflow.org/sqlflow/go/proto"
 )
 
@@ -41,6 +43,7 @@ type trainStepFiller struct {
 	Load                 string
 	Submitter            string
 	User                 string
+	LineageJSON          string
 }
 
 func escapeSpecialRunesAndTrimSpace(s string) string {
@@ -84,6 +87,20 @@ func GenerateTrain(trainStmt *ir.TrainStmt, stepIndex int, session *pb.Session)
 	if err != nil {
 		return "", err
 	}
+	// NOTE: the row counts and the fingerprints are unknown until the
+	// training step reads the data, so only the inputs are recorded.
+	dbName, err := database.GetDatabaseName(session.DbConnStr)
+	if err != nil {
+		return "", err
+	}
+	lineage, err := json.Marshal(&model.Lineage{
+		Inputs:         model.QualifyInputs(trainStmt.Inputs, dbName),
+		SQLFlowVersion: model.SQLFlowVersion,
+		CodeGenerator:  "experimental",
+	})
+	if err != nil {
+		return "", err
+	}
 
 	filler := trainStepFiller{
 		StepIndex:            stepIndex,
@@ -102,6 +119,7 @@ func GenerateTrain(trainStmt *ir.TrainStmt, stepIndex int, session *pb.Session)
 		Load:                 trainStmt.PreTrainedModel,
 		Submitter:            getSubmitter(session),
 		User:                 session.UserId,
+		LineageJSON:          string(lineage),
 	}
 	var program bytes.Buffer
 	var trainTemplate = template.Must(template.New("Train").Parse(trainStepTemplate))
@@ -141,5 +159,6 @@ def step_entry_{{.StepIndex}}():
               validation_params=validation_params,
               save='''{{.Save}}''',
               load='''{{.Load}}''',
-              user='''{{.User}}''')
+              user='''{{.User}}''',
+              lineage=json.loads('''{{.LineageJSON}}'''))
 `
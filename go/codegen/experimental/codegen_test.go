@@ -20,6 +20,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"sqlflow.org/sqlflow/go/database"
+	"sqlflow.org/sqlflow/go/ir"
 	pb "sqlflow.org/sqlflow/go/proto"
 )
 
@@ -36,6 +37,7 @@ func TestExperimentalXGBCodegen(t *testing.T) {
 		t.Errorf("error %s", err)
 	}
 	a.True(strings.Contains(coulerCode, `couler.run_script(image="sqlflow/sqlflow:step", command="bash", source="\n".join(codes), env=step_envs, resources=resources)`))
+	a.True(strings.Contains(coulerCode, `"inputs":["iris.train"]`))
 
 	// test with COLUMN clause
 	sql = "SELECT * FROM iris.train TO TRAIN xgboost.gbtree WITH objective=\"multi:softmax\",num_class=3 COLUMN petal_length LABEL class INTO sqlflow_models.xgb_classification;"
@@ -47,6 +49,13 @@ func TestExperimentalXGBCodegen(t *testing.T) {
 	a.True(strings.Contains(coulerCode, expected))
 }
 
+func TestGeneratePredictWithoutInto(t *testing.T) {
+	a := assert.New(t)
+	_, err := GeneratePredict(&ir.PredictStmt{Using: "sqlflow_models.my_model"}, 0, &pb.Session{})
+	a.Error(err)
+	a.Contains(err.Error(), "INTO")
+}
+
 func TestGeneratePyDbConnStr(t *testing.T) {
 	mysqlSession := &pb.Session{
 		DbConnStr: database.GetTestingMySQLURL(),
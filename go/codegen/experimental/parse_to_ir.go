@@ -14,29 +14,33 @@
 package experimental
 
 import (
+	"context"
 	"fmt"
 	"strings"
 
 	"sqlflow.org/sqlflow/go/ir"
 	"sqlflow.org/sqlflow/go/parser"
 	pb "sqlflow.org/sqlflow/go/proto"
+	"sqlflow.org/sqlflow/go/tracing"
 )
 
 // GenerateIRStatement generates IR statement from parser.SQLFlowStmt
-func GenerateIRStatement(sql *parser.SQLFlowStmt, session *pb.Session) (ir.SQLFlowStmt, error) {
-	var r ir.SQLFlowStmt
-	var err error
+func GenerateIRStatement(sql *parser.SQLFlowStmt, session *pb.Session) (r ir.SQLFlowStmt, err error) {
+	ctx, span := tracing.StartWithTraceparent(session.GetTraceparent(), "ir.Generate")
+	defer func() { span.End(err) }()
 	if sql.IsExtendedSyntax() {
 		if sql.Train {
 			r, err = ir.GenerateTrainStmt(sql.SQLFlowSelectStmt)
 		} else if sql.ShowTrain {
 			r, err = ir.GenerateShowTrainStmt(sql.SQLFlowSelectStmt)
+		} else if sql.ImportModel {
+			r, err = ir.GenerateImportModelStmt(sql.SQLFlowSelectStmt)
 		} else if sql.Explain {
-			r, err = ir.GenerateExplainStmt(sql.SQLFlowSelectStmt, session.DbConnStr, "", false)
+			r, err = ir.GenerateExplainStmt(ctx, sql.SQLFlowSelectStmt, session.DbConnStr, "", false)
 		} else if sql.Predict {
-			r, err = ir.GeneratePredictStmt(sql.SQLFlowSelectStmt, session.DbConnStr, "", false)
+			r, err = ir.GeneratePredictStmt(ctx, sql.SQLFlowSelectStmt, session.DbConnStr, "", false)
 		} else if sql.Evaluate {
-			r, err = ir.GenerateEvaluateStmt(sql.SQLFlowSelectStmt, session.DbConnStr, "", false)
+			r, err = ir.GenerateEvaluateStmt(ctx, sql.SQLFlowSelectStmt, session.DbConnStr, "", false)
 		} else if sql.Optimize {
 			r, err = ir.GenerateOptimizeStmt(sql.SQLFlowSelectStmt)
 		} else if sql.Run {
@@ -65,7 +69,8 @@ func parseToIR(sqlProgram string, session *pb.Session) ([]ir.SQLFlowStmt, error)
 	}
 	dbDriver = dbDriverParts[0]
 
-	stmts, err := parser.Parse(dbDriver, sqlProgram)
+	ctx := tracing.ContextWithTraceparent(context.Background(), session.Traceparent)
+	stmts, err := parser.ParseContext(ctx, dbDriver, sqlProgram)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,57 @@
+// Copyright 2020 The SQLFlow Authors. All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package database
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// TimeLayout is the layout of the datetime literals, see TimeLiteral.
+const TimeLayout = "2006-01-02 15:04:05"
+
+// QuoteString returns s as a single-quoted SQL string literal.
+func QuoteString(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `'`, `\'`)
+	s = strings.ReplaceAll(s, "\n", `\n`)
+	return "'" + s + "'"
+}
+
+// ColumnTypes returns the types of the short string, long text and datetime
+// columns in the dialect of driver, e.g., VARCHAR(255), TEXT and DATETIME in
+// MySQL.
+func ColumnTypes(driver string) (str, text, datetime string, e error) {
+	switch driver {
+	case "mysql":
+		return "VARCHAR(255)", "TEXT", "DATETIME", nil
+	case "hive":
+		return "STRING", "STRING", "TIMESTAMP", nil
+	case "maxcompute", "alisa":
+		return "STRING", "STRING", "DATETIME", nil
+	default:
+		return "", "", "", fmt.Errorf("unsupported driver type %s", driver)
+	}
+}
+
+// TimeLiteral returns the SQL literal of t in the dialect of driver, which
+// could be inserted into a column of the datetime type of ColumnTypes.
+func TimeLiteral(driver string, t time.Time) string {
+	literal := QuoteString(t.Format(TimeLayout))
+	if _, _, datetime, e := ColumnTypes(driver); e == nil && driver != "mysql" {
+		return fmt.Sprintf("CAST(%s AS %s)", literal, datetime)
+	}
+	return literal
+}
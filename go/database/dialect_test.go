@@ -0,0 +1,37 @@
+// Copyright 2020 The SQLFlow Authors. All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package database
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuoteString(t *testing.T) {
+	a := assert.New(t)
+	a.Equal(`'it\'s'`, QuoteString("it's"))
+	a.Equal(`'a\\nb\nc'`, QuoteString(`a\nb`+"\nc"))
+}
+
+func TestTimeLiteral(t *testing.T) {
+	a := assert.New(t)
+	ts := time.Date(2020, 10, 17, 8, 30, 0, 0, time.Local)
+	a.Equal("'2020-10-17 08:30:00'", TimeLiteral("mysql", ts))
+	a.Equal("CAST('2020-10-17 08:30:00' AS TIMESTAMP)", TimeLiteral("hive", ts))
+	a.Equal("CAST('2020-10-17 08:30:00' AS DATETIME)", TimeLiteral("maxcompute", ts))
+	_, _, _, e := ColumnTypes("sqlite3")
+	a.Error(e)
+}
@@ -122,12 +122,22 @@ func (s *alisaExecutor) ExecuteTrain(ts *ir.TrainStmt) (e error) {
 		return err
 	}
 
-	code, paiCmd, requirements, e := pai.Train(ts, s.Session, scriptPath, paramsPath, ts.Into, ossModelPathToSave, ts.PreTrainedModel, s.Cwd)
+	code, paiCmd, requirements, e := pai.Train(ts, s.Session, scriptPath, paramsPath, model.OSSModelName(ts.Into), ossModelPathToSave, ts.PreTrainedModel, s.Cwd)
 	if e != nil {
 		return e
 	}
 	// upload generated program to OSS and submit an Alisa task.
-	return s.uploadResourceAndSubmitAlisaTask(code, requirements, paiCmd, ts.Estimator)
+	if e = s.uploadResourceAndSubmitAlisaTask(code, requirements, paiCmd, ts.Estimator); e != nil {
+		return e
+	}
+	// copy the model saved on OSS by the task to the model URI
+	if strings.Contains(ts.Into, "://") {
+		if e = downloadOSSModel(ossModelPathToSave+"/", s.Cwd); e != nil {
+			return e
+		}
+		return s.SaveModel(ts, "pai")
+	}
+	return nil
 }
 
 func (s *alisaExecutor) ExecutePredict(ps *ir.PredictStmt) error {
@@ -141,8 +151,9 @@ func (s *alisaExecutor) ExecutePredict(ps *ir.PredictStmt) error {
 	if e := createPredictionResultTable(ps, s.Db); e != nil {
 		return e
 	}
+	defer dropPredictionStagingTable(ps, s.Db)
 
-	ossModelPath, e := model.GetOSSModelPath(ps.Using, s.Session)
+	ossModelPath, e := ossModelPathOf(ps.Using, s.pythonExecutor)
 	if e != nil {
 		return e
 	}
@@ -156,11 +167,14 @@ func (s *alisaExecutor) ExecutePredict(ps *ir.PredictStmt) error {
 	if err := createPAIHyperParamFile(s.Cwd, paramsFile, ossModelPath); err != nil {
 		return err
 	}
-	code, paiCmd, requirements, e := pai.Predict(ps, s.Session, scriptPath, paramsPath, ps.Using, ossModelPath, s.Cwd, modelType)
+	code, paiCmd, requirements, e := pai.Predict(ps, s.Session, scriptPath, paramsPath, model.OSSModelName(ps.Using), ossModelPath, s.Cwd, modelType)
 	if e != nil {
 		return e
 	}
-	return s.uploadResourceAndSubmitAlisaTask(code, requirements, paiCmd, estimator)
+	if e := s.uploadResourceAndSubmitAlisaTask(code, requirements, paiCmd, estimator); e != nil {
+		return e
+	}
+	return commitPredictionResult(ps, s.Db)
 }
 
 func (s *alisaExecutor) uploadResourceAndSubmitAlisaTask(entryCode, requirements, alisaExecCode, estimator string) error {
@@ -198,7 +212,7 @@ func (s *alisaExecutor) ExecuteExplain(cl *ir.ExplainStmt) error {
 	cl.TmpExplainTable = strings.Join([]string{dbName, tableName}, ".")
 	defer dropTmpTables([]string{cl.TmpExplainTable}, s.Session.DbConnStr)
 
-	ossModelPath, e := model.GetOSSModelPath(cl.ModelName, s.Session)
+	ossModelPath, e := ossModelPathOf(cl.ModelName, s.pythonExecutor)
 	if e != nil {
 		return e
 	}
@@ -217,7 +231,7 @@ func (s *alisaExecutor) ExecuteExplain(cl *ir.ExplainStmt) error {
 	if err := createPAIHyperParamFile(s.Cwd, paramsFile, ossModelPath); err != nil {
 		return err
 	}
-	expn, e := pai.Explain(cl, s.Session, scriptPath, paramsPath, cl.ModelName, ossModelPath, s.Cwd, modelType)
+	expn, e := pai.Explain(cl, s.Session, scriptPath, paramsPath, model.OSSModelName(cl.ModelName), ossModelPath, s.Cwd, modelType)
 	if e != nil {
 		return e
 	}
@@ -238,7 +252,7 @@ func (s *alisaExecutor) ExecuteEvaluate(es *ir.EvaluateStmt) error {
 	es.TmpEvaluateTable = strings.Join([]string{dbName, tableName}, ".")
 	defer dropTmpTables([]string{es.TmpEvaluateTable}, s.Session.DbConnStr)
 
-	ossModelPath, e := model.GetOSSModelPath(es.ModelName, s.Session)
+	ossModelPath, e := ossModelPathOf(es.ModelName, s.pythonExecutor)
 	if e != nil {
 		return e
 	}
@@ -252,27 +266,24 @@ func (s *alisaExecutor) ExecuteEvaluate(es *ir.EvaluateStmt) error {
 		return e
 	}
 
-	// default always output evaluation loss
-	metricNames := []string{"loss"}
-	metricsAttr, ok := es.Attributes["validation.metrics"]
-	if ok {
-		metricsList := strings.Split(metricsAttr.(string), ",")
-		metricNames = append(metricNames, metricsList...)
-	}
-	if e = createEvaluationResultTable(s.Db, es.Into, metricNames); e != nil {
+	if e = createEvaluationResultTable(s.Db, es, evaluationMetricNames(es)); e != nil {
 		return e
 	}
+	defer dropEvaluationStagingTable(es, s.Db)
 
 	scriptPath := fmt.Sprintf("file://@@%s", resourceName)
 	paramsPath := fmt.Sprintf("file://@@%s", paramsFile)
 	if e = createPAIHyperParamFile(s.Cwd, paramsFile, ossModelPath); e != nil {
 		return e
 	}
-	code, paiCmd, requirements, e := pai.Evaluate(es, s.Session, scriptPath, paramsPath, es.ModelName, ossModelPath, s.Cwd, modelType)
+	code, paiCmd, requirements, e := pai.Evaluate(es, s.Session, scriptPath, paramsPath, model.OSSModelName(es.ModelName), ossModelPath, s.Cwd, modelType)
 	if e != nil {
 		return e
 	}
-	return s.uploadResourceAndSubmitAlisaTask(code, requirements, paiCmd, estimator)
+	if e = s.uploadResourceAndSubmitAlisaTask(code, requirements, paiCmd, estimator); e != nil {
+		return e
+	}
+	return commitEvaluationResult(es, s.Db)
 }
 
 func (s *alisaExecutor) ExecuteOptimize(stmt *ir.OptimizeStmt) error {
@@ -323,6 +334,10 @@ func (s *alisaExecutor) ExecuteRun(runStmt *ir.RunStmt) error {
 
 func (s *alisaExecutor) GetTrainStmtFromModel() bool { return false }
 
+func (s *alisaExecutor) ExecuteImportModel(stmt *ir.ImportModelStmt) error {
+	return fmt.Errorf("Alisa executor does not support IMPORT MODEL")
+}
+
 func findPyModulePath(pyModuleName string) (string, error) {
 	var b bytes.Buffer
 	wStdout := bufio.NewWriter(&b)
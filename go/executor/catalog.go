@@ -0,0 +1,70 @@
+// Copyright 2020 The SQLFlow Authors. All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor
+
+import (
+	"encoding/json"
+	"sort"
+	"time"
+
+	"sqlflow.org/sqlflow/go/ir"
+	"sqlflow.org/sqlflow/go/model"
+	pb "sqlflow.org/sqlflow/go/proto"
+)
+
+// newCatalogEntry returns the model catalog entry of the model m saved by
+// the training statement cl.
+func newCatalogEntry(cl *ir.TrainStmt, m *model.Model, session *pb.Session) *model.CatalogEntry {
+	attrs, e := json.Marshal(cl.Attributes)
+	if e != nil {
+		attrs = []byte("{}")
+	}
+	label := ""
+	if cl.Label != nil && len(cl.Label.GetFieldDesc()) > 0 {
+		label = cl.Label.GetFieldDesc()[0].Name
+	}
+	targets := []string{}
+	for target := range cl.Features {
+		targets = append(targets, target)
+	}
+	sort.Strings(targets)
+	features := []string{}
+	for _, target := range targets {
+		for _, fc := range cl.Features[target] {
+			for _, fd := range fc.GetFieldDesc() {
+				features = append(features, fd.Name)
+			}
+		}
+	}
+	lineage := ""
+	if l := m.Lineage(); l != nil {
+		if b, e := json.Marshal(l); e == nil {
+			lineage = string(b)
+		}
+	}
+	return &model.CatalogEntry{
+		Name:        cl.Into,
+		Owner:       session.UserId,
+		Estimator:   cl.Estimator,
+		TrainSelect: cl.Select,
+		Attributes:  string(attrs),
+		Label:       label,
+		Features:    features,
+		CreatedAt:   time.Now(),
+		Size:        m.Size,
+		Version:     m.Version,
+		StorageURI:  m.StorageURI,
+		Lineage:     lineage,
+	}
+}
@@ -16,7 +16,9 @@ package executor
 import (
 	"bufio"
 	"bytes"
+	"context"
 	"encoding/base64"
+	"encoding/json"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -27,8 +29,12 @@ import (
 	"regexp"
 	"strings"
 	"sync"
+	"syscall"
+	"time"
 
+	"sqlflow.org/sqlflow/go/artifact"
 	"sqlflow.org/sqlflow/go/codegen/experimental"
+	"sqlflow.org/sqlflow/go/metrics"
 
 	"sqlflow.org/sqlflow/go/verifier"
 
@@ -38,9 +44,11 @@ import (
 	"sqlflow.org/sqlflow/go/codegen/xgboost"
 	"sqlflow.org/sqlflow/go/database"
 	"sqlflow.org/sqlflow/go/ir"
+	"sqlflow.org/sqlflow/go/log"
 	"sqlflow.org/sqlflow/go/model"
 	"sqlflow.org/sqlflow/go/pipe"
 	pb "sqlflow.org/sqlflow/go/proto"
+	"sqlflow.org/sqlflow/go/tracing"
 )
 
 var rePyDiagnostics = regexp.MustCompile("runtime.diagnostics.SQLFlowDiagnostic: (.*)")
@@ -67,6 +75,7 @@ type Executor interface {
 	ExecuteExplain(*ir.ExplainStmt) error
 	ExecuteEvaluate(*ir.EvaluateStmt) error
 	ExecuteShowTrain(*ir.ShowTrainStmt) error
+	ExecuteImportModel(*ir.ImportModelStmt) error
 	ExecuteOptimize(*ir.OptimizeStmt) error
 	ExecuteRun(*ir.RunStmt) error
 	GetTrainStmtFromModel() bool
@@ -107,6 +116,9 @@ type logChanWriter struct {
 // as the discussion of https://github.com/sql-machine-learning/sqlflow/issues/2494,
 // SQLFlow would generate target code instead of interpret an IR.
 func Run(it Executor, stmt ir.SQLFlowStmt) error {
+	if v, ok := stmt.(*ir.PredictStmt); ok && v.ResultTable == "" {
+		return executePredictToClient(it, v)
+	}
 	pyExec := it.GetPythonExecutor()
 	if ok, err := pyExec.tryExperimentalExecute(stmt, false); ok {
 		return err
@@ -129,6 +141,8 @@ func Run(it Executor, stmt ir.SQLFlowStmt) error {
 		return it.ExecuteQuery(v)
 	case *ir.ShowTrainStmt:
 		return it.ExecuteShowTrain(v)
+	case *ir.ImportModelStmt:
+		return it.ExecuteImportModel(v)
 	default:
 		return fmt.Errorf("unregistered SQLFlow IR type: %s", v)
 	}
@@ -151,7 +165,11 @@ func (cw *logChanWriter) Write(p []byte) (n int, err error) {
 		if err != nil {
 			break
 		}
-		if err := cw.wr.Write(cw.prev); err != nil {
+		var item interface{} = cw.prev
+		if p, ok := parseProgress(cw.prev); ok {
+			item = p
+		}
+		if err := cw.wr.Write(item); err != nil {
 			return len(cw.prev), err
 		}
 		cw.prev = ""
@@ -171,6 +189,9 @@ type pythonExecutor struct {
 	Db      *database.DB
 	Cwd     string
 	Session *pb.Session
+	// Artifacts keeps the generated programs and the outputs of the
+	// statement, nil if artifacts are not enabled.
+	Artifacts *artifact.Statement
 }
 
 // UseExperimentalExecutor returns whether to use the experimental codegen
@@ -215,7 +236,7 @@ EOF
 
 	cmd := exec.Command("bash", "-c", fmt.Sprintf(bashCodeTmpl, stepCode))
 	cmd.Dir = s.Cwd
-	errorLog, err := s.runCommand(cmd, nil, logStderr)
+	errorLog, err := s.runCommand(cmd, stepCode, nil, logStderr)
 	if err != nil {
 		return true, fmt.Errorf("%v\n%s", err, errorLog)
 	}
@@ -231,17 +252,45 @@ func (s *pythonExecutor) GetPythonExecutor() *pythonExecutor {
 	return s
 }
 
-func (s *pythonExecutor) SaveModel(cl *ir.TrainStmt) error {
+// logger returns the logger with the trace ID of the session.
+func (s *pythonExecutor) logger() *log.Logger {
+	return tracing.Logger(tracing.ContextWithTraceparent(context.Background(), s.Session.GetTraceparent()), nil)
+}
+
+func (s *pythonExecutor) SaveModel(cl *ir.TrainStmt, codeGenerator string) error {
 	m := model.New(s.Cwd, cl.OriginalSQL)
+	// NOTE: failing to record the lineage should not fail the training.
+	if e := setLineage(m, cl, s.Db, s.Session.DbConnStr, codeGenerator); e != nil {
+		s.logger().Errorf("failed to record model lineage: %v", e)
+	}
 	modelURI := cl.Into
-	return m.Save(modelURI, s.Session)
+	if e := m.Save(modelURI, s.Session); e != nil {
+		return e
+	}
+	if m.Version > 0 {
+		s.Writer.Write(fmt.Sprintf("Model is saved as %s@%d", modelURI, m.Version))
+	}
+	// NOTE: the model catalog is an index of the models, failing to update it
+	// should not fail the training.
+	if e := model.UpsertCatalog(s.Db, model.CatalogTable(), newCatalogEntry(cl, m, s.Session)); e != nil {
+		s.logger().Errorf("failed to update model catalog: %v", e)
+	}
+	return nil
+}
+
+// generate runs the code generator gen in a span named name.
+func (s *pythonExecutor) generate(name string, gen func() (string, error)) (string, error) {
+	_, span := tracing.StartWithTraceparent(s.Session.GetTraceparent(), name)
+	code, e := gen()
+	span.End(e)
+	return code, e
 }
 
 func (s *pythonExecutor) runProgram(program string, logStderr bool) error {
 	cmd := sqlflowCmd(s.Cwd, s.Db.DriverName)
 	cmd.Stdin = bytes.NewBufferString(program)
 
-	errorLog, e := s.runCommand(cmd, nil, logStderr)
+	errorLog, e := s.runCommand(cmd, program, nil, logStderr)
 	if e != nil {
 		// return the diagnostic message
 		sub := rePyDiagnostics.FindStringSubmatch(errorLog)
@@ -254,52 +303,104 @@ func (s *pythonExecutor) runProgram(program string, logStderr bool) error {
 	return nil
 }
 
-func (s *pythonExecutor) runCommand(cmd *exec.Cmd, context map[string]string, logStderr bool) (string, error) {
+// runCommand runs cmd and returns the error log if it fails. program is
+// the code run by cmd, which is kept along with the outputs of cmd if
+// artifacts are enabled. The trace of the session is propagated to cmd by
+// the environment variables TRACEPARENT and SQLFLOW_TRACE_ID.
+func (s *pythonExecutor) runCommand(cmd *exec.Cmd, program string, context map[string]string, logStderr bool) (errorLog string, e error) {
+	ctx, span := tracing.StartWithTraceparent(s.Session.GetTraceparent(), "executor.runCommand")
+	span.SetAttribute("program", filepath.Base(cmd.Args[0]))
+	defer func() { span.End(e) }()
+
 	cw := &logChanWriter{wr: s.Writer}
 	defer cw.Close()
 
 	for k, v := range context {
 		os.Setenv(k, v)
 	}
+	if env := tracing.Environ(ctx); env != nil {
+		if cmd.Env == nil {
+			cmd.Env = os.Environ()
+		}
+		cmd.Env = append(cmd.Env, env...)
+	}
+
+	artifactStdout, artifactStderr, e := s.Artifacts.NewCommand(program)
+	if e != nil {
+		return "", e
+	}
 
 	var stderr bytes.Buffer
 	var stdout bytes.Buffer
 	if logStderr {
-		w := io.MultiWriter(cw, &stderr)
-		wStdout := bufio.NewWriter(&stdout)
+		w := io.MultiWriter(cw, &stderr, artifactStderr)
+		wStdout := bufio.NewWriter(io.MultiWriter(&stdout, artifactStdout))
+		defer wStdout.Flush()
 		cmd.Stdout, cmd.Stderr = wStdout, w
 	} else {
-		w := io.MultiWriter(cw, &stdout)
-		wStderr := bufio.NewWriter(&stderr)
+		w := io.MultiWriter(cw, &stdout, artifactStdout)
+		wStderr := bufio.NewWriter(io.MultiWriter(&stderr, artifactStderr))
+		defer wStderr.Flush()
 		cmd.Stdout, cmd.Stderr = w, wStderr
 	}
 
-	if e := cmd.Run(); e != nil {
+	if e := runCancellable(cmd, s.Writer); e != nil {
 		return stderr.String(), e
 	}
 
 	return ``, nil
 }
 
+// runCancellable runs cmd and kills it, along with the processes it starts,
+// if the reader of w cancels the pipe.
+func runCancellable(cmd *exec.Cmd, w *pipe.Writer) (e error) {
+	defer func() {
+		if e != nil {
+			metrics.SubprocessFailures.WithLabelValues(filepath.Base(cmd.Args[0])).Inc()
+		}
+	}()
+	if w == nil {
+		return cmd.Run()
+	}
+	// NOTE: run cmd in a new process group, so that the subprocesses of
+	// cmd, e.g. the workers of a training job, are killed together.
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	if e := cmd.Start(); e != nil {
+		return e
+	}
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-w.Cancelled():
+			syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+		case <-done:
+		}
+	}()
+	return cmd.Wait()
+}
+
 func (s *pythonExecutor) ExecuteQuery(stmt *ir.NormalStmt) error {
 	return runNormalStmt(s.Writer, string(*stmt), s.Db)
 }
 
 func (s *pythonExecutor) ExecuteTrain(cl *ir.TrainStmt) (e error) {
-	var code string
+	var code, codeGenerator string
 	if cl.GetModelKind() == ir.XGBoost {
-		if code, e = xgboost.Train(cl, s.Session); e != nil {
+		if code, e = s.generate("codegen.xgboost.Train", func() (string, error) { return xgboost.Train(cl, s.Session) }); e != nil {
 			return e
 		}
+		codeGenerator = "xgboost"
 	} else {
-		if code, e = tensorflow.Train(cl, s.Session); e != nil {
+		if code, e = s.generate("codegen.tensorflow.Train", func() (string, error) { return tensorflow.Train(cl, s.Session) }); e != nil {
 			return e
 		}
+		codeGenerator = "tensorflow"
 	}
 	if e := s.runProgram(code, false); e != nil {
 		return e
 	}
-	return s.SaveModel(cl)
+	return s.SaveModel(cl, codeGenerator)
 }
 
 func (s *pythonExecutor) ExecutePredict(cl *ir.PredictStmt) (e error) {
@@ -307,18 +408,22 @@ func (s *pythonExecutor) ExecutePredict(cl *ir.PredictStmt) (e error) {
 	if e = createPredictionResultTable(cl, s.Db); e != nil {
 		return e
 	}
+	defer dropPredictionStagingTable(cl, s.Db)
 
 	var code string
 	if cl.TrainStmt.GetModelKind() == ir.XGBoost {
-		if code, e = xgboost.Pred(cl, s.Session); e != nil {
+		if code, e = s.generate("codegen.xgboost.Pred", func() (string, error) { return xgboost.Pred(cl, s.Session) }); e != nil {
 			return e
 		}
 	} else {
-		if code, e = tensorflow.Pred(cl, s.Session); e != nil {
+		if code, e = s.generate("codegen.tensorflow.Pred", func() (string, error) { return tensorflow.Pred(cl, s.Session) }); e != nil {
 			return e
 		}
 	}
-	return s.runProgram(code, false)
+	if e = s.runProgram(code, false); e != nil {
+		return e
+	}
+	return commitPredictionResult(cl, s.Db)
 }
 
 func (s *pythonExecutor) ExecuteExplain(cl *ir.ExplainStmt) error {
@@ -333,10 +438,10 @@ func (s *pythonExecutor) ExecuteExplain(cl *ir.ExplainStmt) error {
 
 	var modelType int
 	if cl.TrainStmt.GetModelKind() == ir.XGBoost {
-		code, err = xgboost.Explain(cl, s.Session)
+		code, err = s.generate("codegen.xgboost.Explain", func() (string, error) { return xgboost.Explain(cl, s.Session) })
 		modelType = model.XGBOOST
 	} else {
-		code, err = tensorflow.Explain(cl, s.Session)
+		code, err = s.generate("codegen.tensorflow.Explain", func() (string, error) { return tensorflow.Explain(cl, s.Session) })
 		modelType = model.TENSORFLOW
 	}
 
@@ -369,43 +474,38 @@ func (s *pythonExecutor) ExecuteExplain(cl *ir.ExplainStmt) error {
 
 func (s *pythonExecutor) ExecuteEvaluate(cl *ir.EvaluateStmt) error {
 	// NOTE(typhoonzero): model is already loaded under s.Cwd
-	var code string
-	var err error
-	if cl.TrainStmt.GetModelKind() == ir.XGBoost {
-		code, err = xgboost.Evaluate(cl, s.Session)
+	if cl.Into != "" {
+		// create evaluation result table before generating the code, since
+		// the generated program writes into the staging table.
+		db, err := database.OpenAndConnectDB(s.Session.DbConnStr)
 		if err != nil {
 			return err
 		}
-	} else {
-		code, err = tensorflow.Evaluate(cl, s.Session)
-		if err != nil {
+		defer db.Close()
+		if err = createEvaluationResultTable(db, cl, evaluationMetricNames(cl)); err != nil {
 			return err
 		}
+		defer dropEvaluationStagingTable(cl, db)
 	}
 
-	if cl.Into != "" {
-		// create evaluation result table
-		db, err := database.OpenAndConnectDB(s.Session.DbConnStr)
+	var code string
+	var err error
+	if cl.TrainStmt.GetModelKind() == ir.XGBoost {
+		code, err = s.generate("codegen.xgboost.Evaluate", func() (string, error) { return xgboost.Evaluate(cl, s.Session) })
 		if err != nil {
 			return err
 		}
-		defer db.Close()
-		// default always output evaluation loss
-		metricNames := []string{"loss"}
-		metricsAttr, ok := cl.Attributes["validation.metrics"]
-		if ok {
-			metricsList := strings.Split(metricsAttr.(string), ",")
-			metricNames = append(metricNames, metricsList...)
-		}
-		err = createEvaluationResultTable(db, cl.Into, metricNames)
+	} else {
+		code, err = s.generate("codegen.tensorflow.Evaluate", func() (string, error) { return tensorflow.Evaluate(cl, s.Session) })
 		if err != nil {
 			return err
 		}
 	}
+
 	if err = s.runProgram(code, false); err != nil {
 		return err
 	}
-	return nil
+	return commitEvaluationResult(cl, s.Db)
 }
 
 func (s *pythonExecutor) ExecuteOptimize(stmt *ir.OptimizeStmt) error {
@@ -474,7 +574,9 @@ func (s *pythonExecutor) ExecuteOptimize(stmt *ir.OptimizeStmt) error {
 		return err
 	}
 
-	program, err := optimize.GenerateOptimizeCode(stmt, s.Session, "", false)
+	program, err := s.generate("codegen.optimize.GenerateOptimizeCode", func() (string, error) {
+		return optimize.GenerateOptimizeCode(stmt, s.Session, "", false)
+	})
 	if err != nil {
 		return err
 	}
@@ -506,7 +608,7 @@ func (s *pythonExecutor) ExecuteRun(runStmt *ir.RunStmt) error {
 		cmd := exec.Command(program, runStmt.Parameters[1:]...)
 		cmd.Dir = s.Cwd
 
-		errMsg, e = s.runCommand(cmd, context, false)
+		errMsg, e = s.runCommand(cmd, "", context, false)
 	} else if strings.EqualFold(fileExtension, ".py") {
 		// If the first parameter is a Python program
 		// Build the command
@@ -515,7 +617,7 @@ func (s *pythonExecutor) ExecuteRun(runStmt *ir.RunStmt) error {
 		cmd := exec.Command("python", pyCmdParams...)
 		cmd.Dir = s.Cwd
 
-		errMsg, e = s.runCommand(cmd, context, false)
+		errMsg, e = s.runCommand(cmd, "", context, false)
 	} else {
 		// TODO(brightcoder01): Implement the execution of the program built using other script languages.
 		return fmt.Errorf("The other executable except Python program is not supported yet")
@@ -528,31 +630,130 @@ func (s *pythonExecutor) ExecuteRun(runStmt *ir.RunStmt) error {
 	return e
 }
 
-func createEvaluationResultTable(db *database.DB, tableName string, metricNames []string) error {
-	dropStmt := fmt.Sprintf(`DROP TABLE IF EXISTS %s;`, tableName)
-	var e error
-	if _, e = db.Exec(dropStmt); e != nil {
-		return fmt.Errorf("failed executing %s: %q", dropStmt, e)
+const (
+	evaluateModeOverwrite = "overwrite"
+	evaluateModeAppend    = "append"
+
+	evaluationModelColumn = "model_name"
+	evaluationTimeColumn  = "evaluated_at"
+	// evaluationMetricType is the type of the metric columns in all the
+	// dialects, the same as create_evaluate_table in
+	// python/runtime/step/create_result_table.py.
+	evaluationMetricType = "DOUBLE"
+)
+
+// evaluationMetricNames returns the metric columns of the evaluation result
+// table, which always includes the evaluation loss.
+func evaluationMetricNames(es *ir.EvaluateStmt) []string {
+	metricNames := []string{"loss"}
+	metricsAttr, ok := es.Attributes["validation.metrics"]
+	if ok {
+		for _, m := range strings.Split(metricsAttr.(string), ",") {
+			metricNames = append(metricNames, strings.TrimSpace(m))
+		}
 	}
-	columnDef := ""
-	columnDefList := []string{}
-	if db.DriverName == "mysql" {
-		for _, mn := range metricNames {
-			columnDefList = append(columnDefList,
-				fmt.Sprintf("%s VARCHAR(255)", mn))
+	return metricNames
+}
+
+func resolveEvaluateMode(es *ir.EvaluateStmt) (string, error) {
+	m, ok := es.Attributes["evaluate.mode"]
+	if !ok {
+		return evaluateModeOverwrite, nil
+	}
+	mode, ok := m.(string)
+	if !ok {
+		return "", fmt.Errorf("evaluate.mode must be string")
+	}
+	switch mode = strings.ToLower(mode); mode {
+	case evaluateModeOverwrite, evaluateModeAppend:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("evaluate.mode should be one of overwrite and append, got %s", mode)
+	}
+}
+
+// createEvaluationResultTable creates the evaluation result table es.Into,
+// which has a column for the model name, a column for the evaluation time
+// and numeric columns for the metrics. If evaluate.mode is "append" and the
+// table exists, it checks the schema of the table instead.
+//
+// The generated program writes only the metrics, so createEvaluationResultTable
+// points es.Into to a staging table of the metric columns. Call
+// commitEvaluationResult after the program to move the metrics into the
+// result table, and call dropEvaluationStagingTable to clean up.
+func createEvaluationResultTable(db *database.DB, es *ir.EvaluateStmt, metricNames []string) error {
+	mode, e := resolveEvaluateMode(es)
+	if e != nil {
+		return e
+	}
+	strType, _, timeType, e := database.ColumnTypes(db.DriverName)
+	if e != nil {
+		return e
+	}
+	metricTypes := []string{}
+	for range metricNames {
+		metricTypes = append(metricTypes, evaluationMetricType)
+	}
+	names := append([]string{evaluationModelColumn, evaluationTimeColumn}, metricNames...)
+	types := append([]string{strType, timeType}, metricTypes...)
+
+	if mode == evaluateModeOverwrite {
+		dropStmt := fmt.Sprintf(`DROP TABLE IF EXISTS %s;`, es.Into)
+		if _, e = db.Exec(dropStmt); e != nil {
+			return fmt.Errorf("failed executing %s: %q", dropStmt, e)
+		}
+		if e = createTableWithSchema(db, es.Into, names, types, ""); e != nil {
+			return e
 		}
+	} else if e = createOrCheckOutputTable(db, es.Into, names, types, ""); e != nil {
+		return e
+	}
 
+	staging := tmpTableName(es.Into)
+	if e = createTableWithSchema(db, staging, metricNames, metricTypes, ""); e != nil {
+		return e
+	}
+	es.OutputTable, es.Into = es.Into, staging
+	return nil
+}
+
+// commitEvaluationResult inserts the metrics in the staging table into the
+// evaluation result table along with the model name and the evaluation time.
+func commitEvaluationResult(es *ir.EvaluateStmt, db *database.DB) error {
+	if es.OutputTable == "" {
+		return nil
+	}
+	metricNames := evaluationMetricNames(es)
+	evaluatedAt := database.TimeLiteral(db.DriverName, time.Now())
+	metrics := strings.Join(metricNames, ",")
+	modelName := database.QuoteString(es.ModelName)
+	var stmt string
+	if db.DriverName == "mysql" {
+		stmt = fmt.Sprintf("INSERT INTO %s (%s,%s,%s) SELECT %s,%s,%s FROM %s",
+			es.OutputTable, evaluationModelColumn, evaluationTimeColumn, metrics,
+			modelName, evaluatedAt, metrics, es.Into)
 	} else {
-		// Hive, MaxCompute
-		for _, mn := range metricNames {
-			columnDefList = append(columnDefList,
-				fmt.Sprintf("%s STRING", mn))
-		}
+		stmt = fmt.Sprintf("INSERT INTO TABLE %s SELECT %s,%s,%s FROM %s",
+			es.OutputTable, modelName, evaluatedAt, metrics, es.Into)
 	}
-	columnDef = strings.Join(columnDefList, ",")
-	createStmt := fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (%s);`, tableName, columnDef)
-	if _, e = db.Exec(createStmt); e != nil {
-		return fmt.Errorf("failed executing %s: %q", createStmt, e)
+	if _, e := db.Exec(stmt); e != nil {
+		return fmt.Errorf("failed executing %s: %q", stmt, e)
+	}
+	return nil
+}
+
+// dropEvaluationStagingTable drops the staging table created by
+// createEvaluationResultTable, if any, and points es.Into back to the
+// evaluation result table.
+func dropEvaluationStagingTable(es *ir.EvaluateStmt, db *database.DB) error {
+	if es.OutputTable == "" {
+		return nil
+	}
+	staging := es.Into
+	es.Into, es.OutputTable = es.OutputTable, ""
+	dropStmt := fmt.Sprintf("DROP TABLE IF EXISTS %s", staging)
+	if _, e := db.Exec(dropStmt); e != nil {
+		return fmt.Errorf("failed executing %s: %q", dropStmt, e)
 	}
 	return nil
 }
@@ -574,15 +775,56 @@ func readExplainResult(target string) (string, error) {
 func (s *pythonExecutor) GetTrainStmtFromModel() bool { return true }
 
 func (s *pythonExecutor) ExecuteShowTrain(showTrain *ir.ShowTrainStmt) error {
-	model, err := model.Load(showTrain.ModelName, "", s.Db)
+	ctx := tracing.ContextWithTraceparent(context.Background(), s.Session.GetTraceparent())
+	model, err := model.LoadContext(ctx, showTrain.ModelName, "", s.Db)
 	if err != nil {
 		s.Writer.Write("Load model meta " + showTrain.ModelName + " failed.")
 		return err
 	}
 	header := make(map[string]interface{})
-	header["columnNames"] = []string{"Model", "Train Statement"}
+	lineage := ""
+	if l := model.Lineage(); l != nil {
+		if b, e := json.Marshal(l); e == nil {
+			lineage = string(b)
+		}
+	}
+	header["columnNames"] = []string{"Model", "Train Statement", "Lineage"}
 	s.Writer.Write(header)
-	s.Writer.Write([]interface{}{showTrain.ModelName, strings.TrimSpace(model.TrainSelect)})
+	s.Writer.Write([]interface{}{showTrain.ModelName, strings.TrimSpace(model.TrainSelect), lineage})
+
+	return nil
+}
 
+func (s *pythonExecutor) ExecuteImportModel(stmt *ir.ImportModelStmt) error {
+	kind := model.TENSORFLOW
+	if stmt.TrainStmt.GetModelKind() == ir.XGBoost {
+		kind = model.XGBOOST
+	}
+	// NOTE: keep the metadata the same as the Python runtime collects when
+	// training, see python/runtime/model/metadata.py
+	meta := map[string]interface{}{
+		"original_sql":      stmt.OriginalSQL,
+		"select":            "",
+		"validation_select": "",
+		"model_repo_image":  stmt.TrainStmt.ModelImage,
+		"class_name":        stmt.TrainStmt.Estimator,
+		"attributes":        stmt.TrainStmt.Attributes,
+		"imported_from":     stmt.ModelPath,
+	}
+	m, e := model.Import(s.Cwd, stmt.ModelPath, kind, meta)
+	if e != nil {
+		return e
+	}
+	if e := m.Save(stmt.Into, s.Session); e != nil {
+		return e
+	}
+	if m.Version > 0 {
+		s.Writer.Write(fmt.Sprintf("Model is imported as %s@%d", stmt.Into, m.Version))
+	} else {
+		s.Writer.Write(fmt.Sprintf("Model is imported as %s", stmt.Into))
+	}
+	if e := model.UpsertCatalog(s.Db, model.CatalogTable(), newCatalogEntry(stmt.TrainStmt, m, s.Session)); e != nil {
+		s.logger().Errorf("failed to update model catalog: %v", e)
+	}
 	return nil
 }
@@ -14,7 +14,15 @@
 package executor
 
 import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"sqlflow.org/sqlflow/go/pipe"
@@ -54,3 +62,39 @@ func TestLogChanWriter_Write(t *testing.T) {
 	_, more := <-c
 	a.False(more)
 }
+
+func TestRunCancellable(t *testing.T) {
+	a := assert.New(t)
+	dir, e := ioutil.TempDir("/tmp", "sqlflow_cancel")
+	a.NoError(e)
+	defer os.RemoveAll(dir)
+	pidFile := filepath.Join(dir, "pid")
+
+	// cmd starts a subprocess, which should be killed on cancelling too
+	rd, wr := pipe.Pipe()
+	cmd := exec.Command("bash", "-c", fmt.Sprintf("sleep 60 & echo $! > %s; wait", pidFile))
+	done := make(chan error)
+	go func() { done <- runCancellable(cmd, wr) }()
+	var pid int
+	for i := 0; i < 100 && pid == 0; i++ {
+		time.Sleep(50 * time.Millisecond)
+		b, _ := ioutil.ReadFile(pidFile)
+		pid, _ = strconv.Atoi(strings.TrimSpace(string(b)))
+	}
+	a.NotZero(pid)
+	rd.Cancel()
+	select {
+	case e = <-done:
+		a.Error(e)
+	case <-time.After(10 * time.Second):
+		a.Fail("runCancellable is not cancelled")
+	}
+	alive := true
+	for i := 0; i < 100 && alive; i++ {
+		// NOTE: the killed subprocess may be a zombie until init reaps it.
+		stat, e := ioutil.ReadFile(fmt.Sprintf("/proc/%d/stat", pid))
+		alive = e == nil && !strings.Contains(string(stat), ") Z ")
+		time.Sleep(50 * time.Millisecond)
+	}
+	a.False(alive)
+}
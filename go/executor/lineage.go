@@ -0,0 +1,34 @@
+// Copyright 2020 The SQLFlow Authors. All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor
+
+import (
+	"sqlflow.org/sqlflow/go/database"
+	"sqlflow.org/sqlflow/go/ir"
+	"sqlflow.org/sqlflow/go/model"
+)
+
+// setLineage records the lineage of the model m trained by cl in the model
+// metadata.
+func setLineage(m *model.Model, cl *ir.TrainStmt, db *database.DB, dataSource, codeGenerator string) error {
+	dbName, e := database.GetDatabaseName(dataSource)
+	if e != nil {
+		return e
+	}
+	l, e := model.NewLineage(db, model.QualifyInputs(cl.Inputs, dbName), cl.Select, cl.ValidationSelect, codeGenerator)
+	if e != nil {
+		return e
+	}
+	return m.SetLineage(l)
+}
@@ -36,7 +36,6 @@ import (
 	"sqlflow.org/sqlflow/go/ir"
 	"sqlflow.org/sqlflow/go/model"
 	pb "sqlflow.org/sqlflow/go/proto"
-	"sqlflow.org/sqlflow/go/randstring"
 )
 
 const (
@@ -62,7 +61,7 @@ func createTmpTableFromSelect(selectStmt, dataSource string) (string, string, er
 		return "", "", err
 	}
 	defer db.Close()
-	tableName := randstring.Generate(16)
+	tableName := tmpTableName("")
 	// FIXME(typhoonzero): only work if specify database name in connect string.
 	databaseName, err := database.GetDatabaseName(dataSource)
 	if err != nil {
@@ -142,6 +141,11 @@ func createPAIHyperParamFile(cwd string, filename string, modelPath string) erro
 }
 
 func preExecuteTrainOnPAI(cl *ir.TrainStmt, session *pb.Session) (e error) {
+	// NOTE: PAI saves the models of its built-in algorithms as offline models
+	// of MaxCompute, which could not be saved to model URIs like s3://bucket/path.
+	if strings.Contains(cl.Into, "://") && isPAIMLEstimator(cl.Estimator) {
+		return fmt.Errorf("cannot save the %s model to %s, please use a model name", cl.Estimator, cl.Into)
+	}
 	// create tmp table for training and validating
 	cl.TmpTrainTable, cl.TmpValidateTable, e = createTempTrainAndValTable(cl.Select, cl.ValidationSelect, session.DbConnStr)
 	if e != nil {
@@ -163,7 +167,7 @@ func getPaiTrainCode(s *pythonExecutor, trainStmt *ir.TrainStmt) (string, string
 
 	ossModelPathToLoad := ""
 	if trainStmt.PreTrainedModel != "" {
-		ossModelPathToLoad, e = model.GetOSSModelPath(trainStmt.PreTrainedModel, s.Session)
+		ossModelPathToLoad, e = ossModelPathOf(trainStmt.PreTrainedModel, s)
 		if e != nil {
 			return "", "", "", e
 		}
@@ -183,7 +187,7 @@ func getPaiTrainCode(s *pythonExecutor, trainStmt *ir.TrainStmt) (string, string
 	if err := createPAIHyperParamFile(s.Cwd, paramsFile, ossModelPathToSave); err != nil {
 		return "", "", "", err
 	}
-	code, paiCmd, requirements, e := pai.Train(trainStmt, s.Session, scriptPath, paramsPath, trainStmt.Into, ossModelPathToSave,
+	code, paiCmd, requirements, e := pai.Train(trainStmt, s.Session, scriptPath, paramsPath, model.OSSModelName(trainStmt.Into), ossModelPathToSave,
 		ossModelPathToLoad, s.Cwd)
 	if e != nil {
 		return "", "", "", e
@@ -214,14 +218,15 @@ func (s *paiExecutor) ExecuteTrain(cl *ir.TrainStmt) (e error) {
 	// download model from OSS to local cwd and save to sqlfs
 	// NOTE(typhoonzero): model in sqlfs will be used by sqlflow model zoo currently
 	// should use the model in sqlfs when predicting.
-	if e = downloadOSSModel(ossModelPathToSave + "/"); e != nil {
+	if e = downloadOSSModel(ossModelPathToSave+"/", s.Cwd); e != nil {
 		return e
 	}
-	m := model.New(s.Cwd, cl.OriginalSQL)
-	return m.Save(cl.Into, s.Session)
+	return s.SaveModel(cl, "pai")
 }
 
-func downloadOSSModel(ossModelPath string) error {
+// downloadOSSModel downloads the model directory ossModelPath on OSS to the
+// directory of the same base name under cwd.
+func downloadOSSModel(ossModelPath, cwd string) error {
 	bucket, err := model.GetOSSModelBucket()
 	if err != nil {
 		return err
@@ -231,7 +236,60 @@ func downloadOSSModel(ossModelPath string) error {
 	}
 	localDirParts := strings.Split(ossModelPath, "/")
 	localDir := localDirParts[len(localDirParts)-2] // the last char must be /
-	return downloadDirRecursive(bucket, ossModelPath, localDir+"/")
+	return downloadDirRecursive(bucket, ossModelPath, filepath.Join(cwd, localDir)+"/")
+}
+
+// uploadDirRecursive uploads the files under localDir to the directory dir,
+// which ends with '/', on the OSS.
+func uploadDirRecursive(bucket *oss.Bucket, localDir, dir string) error {
+	return filepath.Walk(localDir, func(fn string, fi os.FileInfo, e error) error {
+		if e != nil || fi.IsDir() {
+			return e
+		}
+		rel, e := filepath.Rel(localDir, fn)
+		if e != nil {
+			return e
+		}
+		return bucket.PutObjectFromFile(dir+filepath.ToSlash(rel), fn)
+	})
+}
+
+// ossModelPathOf returns the OSS path where PAI jobs load the model
+// modelName. For model URIs like s3://bucket/path, it stages the model files
+// trained on PAI, see downloadOSSModel, to the OSS path first.
+func ossModelPathOf(modelName string, s *pythonExecutor) (string, error) {
+	ossModelPath, e := model.GetOSSModelPath(modelName, s.Session)
+	if e != nil || !strings.Contains(modelName, "://") {
+		return ossModelPath, e
+	}
+	dir, e := ioutil.TempDir("/tmp", "sqlflow_models")
+	if e != nil {
+		return "", e
+	}
+	defer os.RemoveAll(dir)
+	if _, e := model.Load(modelName, dir, nil); e != nil {
+		return "", e
+	}
+	localDir := filepath.Join(dir, path.Base(ossModelPath))
+	if _, e := os.Stat(localDir); e != nil {
+		return "", fmt.Errorf("model %s is not trained on PAI: %v", modelName, e)
+	}
+	bucket, e := model.GetOSSModelBucket()
+	if e != nil {
+		return "", e
+	}
+	if e := deleteDirRecursive(bucket, ossModelPath+"/"); e != nil {
+		return "", e
+	}
+	if e := uploadDirRecursive(bucket, localDir, ossModelPath+"/"); e != nil {
+		return "", e
+	}
+	return ossModelPath, nil
+}
+
+func isPAIMLEstimator(estimator string) bool {
+	e := strings.ToLower(estimator)
+	return e == "randomforests" || e == "kmeans"
 }
 
 // downloadDirRecursive recursively download a directory on the OSS
@@ -331,7 +389,7 @@ func getPaiPredictCode(s *pythonExecutor, cl *ir.PredictStmt) (string, string, s
 		return "", "", "", "", e
 	}
 
-	ossModelPath, e := model.GetOSSModelPath(cl.Using, s.Session)
+	ossModelPath, e := ossModelPathOf(cl.Using, s)
 	if e != nil {
 		return "", "", "", "", e
 	}
@@ -344,7 +402,7 @@ func getPaiPredictCode(s *pythonExecutor, cl *ir.PredictStmt) (string, string, s
 	if err := createPAIHyperParamFile(s.Cwd, paramsFile, ossModelPath); err != nil {
 		return "", "", "", "", err
 	}
-	code, paiCmd, requirements, err := pai.Predict(cl, s.Session, scriptPath, paramsPath, cl.Using, ossModelPath, s.Cwd, modelType)
+	code, paiCmd, requirements, err := pai.Predict(cl, s.Session, scriptPath, paramsPath, model.OSSModelName(cl.Using), ossModelPath, s.Cwd, modelType)
 	if err != nil {
 		return "", "", "", "", err
 	}
@@ -354,10 +412,14 @@ func getPaiPredictCode(s *pythonExecutor, cl *ir.PredictStmt) (string, string, s
 func (s *paiExecutor) ExecutePredict(cl *ir.PredictStmt) error {
 	code, paiCmd, requirements, estimator, err := getPaiPredictCode(s.pythonExecutor, cl)
 	defer dropTmpTables([]string{cl.TmpPredictTable}, s.Session.DbConnStr)
+	defer dropPredictionStagingTable(cl, s.Db)
 	if err != nil {
 		return err
 	}
-	return s.submitPAITask(code, paiCmd, requirements, estimator)
+	if err := s.submitPAITask(code, paiCmd, requirements, estimator); err != nil {
+		return err
+	}
+	return commitPredictionResult(cl, s.Db)
 }
 
 func getPaiExplainCode(s *pythonExecutor, cl *ir.ExplainStmt) (*pai.ExplainRender, string, error) {
@@ -369,7 +431,7 @@ func getPaiExplainCode(s *pythonExecutor, cl *ir.ExplainStmt) (*pai.ExplainRende
 	}
 	cl.TmpExplainTable = strings.Join([]string{dbName, tableName}, ".")
 
-	ossModelPath, e := model.GetOSSModelPath(cl.ModelName, s.Session)
+	ossModelPath, e := ossModelPathOf(cl.ModelName, s)
 	if e != nil {
 		return nil, "", e
 	}
@@ -406,7 +468,7 @@ func getPaiExplainCode(s *pythonExecutor, cl *ir.ExplainStmt) (*pai.ExplainRende
 	if err := createPAIHyperParamFile(s.Cwd, paramsFile, ossModelPath); err != nil {
 		return nil, "", err
 	}
-	expn, e := pai.Explain(cl, s.Session, scriptPath, paramsPath, cl.ModelName, ossModelPath, s.Cwd, modelType)
+	expn, e := pai.Explain(cl, s.Session, scriptPath, paramsPath, model.OSSModelName(cl.ModelName), ossModelPath, s.Cwd, modelType)
 	if e != nil {
 		return nil, "", e
 	}
@@ -438,7 +500,7 @@ func getPaiEvaluateCode(s *pythonExecutor, cl *ir.EvaluateStmt) (string, string,
 	}
 	cl.TmpEvaluateTable = strings.Join([]string{dbName, tableName}, ".")
 
-	ossModelPath, e := model.GetOSSModelPath(cl.ModelName, s.Session)
+	ossModelPath, e := ossModelPathOf(cl.ModelName, s)
 	if e != nil {
 		return "", "", "", "", e
 	}
@@ -469,14 +531,7 @@ func getPaiEvaluateCode(s *pythonExecutor, cl *ir.EvaluateStmt) (string, string,
 			return "", "", "", "", err
 		}
 		defer db.Close()
-		// default always output evaluation loss
-		metricNames := []string{"loss"}
-		metricsAttr, ok := cl.Attributes["validation.metrics"]
-		if ok {
-			metricsList := strings.Split(metricsAttr.(string), ",")
-			metricNames = append(metricNames, metricsList...)
-		}
-		err = createEvaluationResultTable(db, cl.Into, metricNames)
+		err = createEvaluationResultTable(db, cl, evaluationMetricNames(cl))
 		if err != nil {
 			return "", "", "", "", err
 		}
@@ -486,7 +541,7 @@ func getPaiEvaluateCode(s *pythonExecutor, cl *ir.EvaluateStmt) (string, string,
 	if err := createPAIHyperParamFile(s.Cwd, paramsFile, ossModelPath); err != nil {
 		return "", "", "", "", err
 	}
-	code, paiCmd, requirements, e := pai.Evaluate(cl, s.Session, scriptPath, paramsPath, cl.ModelName, ossModelPath, s.Cwd, modelType)
+	code, paiCmd, requirements, e := pai.Evaluate(cl, s.Session, scriptPath, paramsPath, model.OSSModelName(cl.ModelName), ossModelPath, s.Cwd, modelType)
 	if e != nil {
 		return "", "", "", "", e
 	}
@@ -515,10 +570,14 @@ func fillDefaultValidationMetrics(es *ir.EvaluateStmt, modelType int) error {
 func (s *paiExecutor) ExecuteEvaluate(cl *ir.EvaluateStmt) error {
 	code, paiCmd, requirements, estimator, e := getPaiEvaluateCode(s.pythonExecutor, cl)
 	defer dropTmpTables([]string{cl.TmpEvaluateTable}, s.Session.DbConnStr)
+	defer dropEvaluationStagingTable(cl, s.Db)
 	if e != nil {
 		return e
 	}
-	return s.submitPAITask(code, paiCmd, requirements, estimator)
+	if e := s.submitPAITask(code, paiCmd, requirements, estimator); e != nil {
+		return e
+	}
+	return commitEvaluationResult(cl, s.Db)
 }
 
 func executeOptimizeUsingOptFlow(pythonExecutor *pythonExecutor, stmt *ir.OptimizeStmt) error {
@@ -739,6 +798,10 @@ func achieveResource(cwd, entryCode, requirements, tarball, estimator string) er
 
 func (s *paiExecutor) GetTrainStmtFromModel() bool { return false }
 
+func (s *paiExecutor) ExecuteImportModel(stmt *ir.ImportModelStmt) error {
+	return fmt.Errorf("PAI executor does not support IMPORT MODEL")
+}
+
 func pickPAILogViewerURL(output string) []string {
 	return reODPSLogURL.FindAllString(output, -1)
 }
@@ -51,17 +51,17 @@ func (s *paiLocalExecutor) ExecuteTrain(trainStmt *ir.TrainStmt) error {
 	// download model from OSS to local cwd and save to sqlfs
 	// NOTE(typhoonzero): model in sqlfs will be used by sqlflow model zoo currently
 	// should use the model in sqlfs when predicting.
-	if e = downloadOSSModel(ossModelPathToSave + "/"); e != nil {
+	if e = downloadOSSModel(ossModelPathToSave+"/", s.Cwd); e != nil {
 		return e
 	}
-	m := model.New(s.Cwd, trainStmt.OriginalSQL)
-	return m.Save(trainStmt.Into, s.Session)
+	return s.SaveModel(trainStmt, "pai")
 }
 
 func (s *paiLocalExecutor) ExecutePredict(predStmt *ir.PredictStmt) error {
 	code, _, _, _, err := getPaiPredictCode(s.pythonExecutor, predStmt)
 
 	defer dropTmpTables([]string{predStmt.TmpPredictTable}, s.Session.DbConnStr)
+	defer dropPredictionStagingTable(predStmt, s.Db)
 	if err != nil {
 		return err
 	}
@@ -70,7 +70,10 @@ func (s *paiLocalExecutor) ExecutePredict(predStmt *ir.PredictStmt) error {
 		return e
 	}
 	setLocalFlagsCode := fmt.Sprintf(setLocalFlagsCodeTmpl, pai.OSSModelURL(ossModelPathToSave))
-	return s.runProgram(setLocalFlagsCode+code, true)
+	if e := s.runProgram(setLocalFlagsCode+code, true); e != nil {
+		return e
+	}
+	return commitPredictionResult(predStmt, s.Db)
 }
 
 func (s *paiLocalExecutor) ExecuteExplain(explainStmt *ir.ExplainStmt) error {
@@ -90,6 +93,7 @@ func (s *paiLocalExecutor) ExecuteExplain(explainStmt *ir.ExplainStmt) error {
 func (s *paiLocalExecutor) ExecuteEvaluate(evalStmt *ir.EvaluateStmt) error {
 	code, _, _, _, err := getPaiEvaluateCode(s.pythonExecutor, evalStmt)
 	defer dropTmpTables([]string{evalStmt.TmpEvaluateTable}, s.Session.DbConnStr)
+	defer dropEvaluationStagingTable(evalStmt, s.Db)
 	if err != nil {
 		return err
 	}
@@ -98,5 +102,8 @@ func (s *paiLocalExecutor) ExecuteEvaluate(evalStmt *ir.EvaluateStmt) error {
 		return e
 	}
 	setLocalFlagsCode := fmt.Sprintf(setLocalFlagsCodeTmpl, pai.OSSModelURL(ossModelPathToSave))
-	return s.runProgram(setLocalFlagsCode+code, true)
+	if e := s.runProgram(setLocalFlagsCode+code, true); e != nil {
+		return e
+	}
+	return commitEvaluationResult(evalStmt, s.Db)
 }
@@ -15,9 +15,12 @@ package executor
 
 import (
 	"fmt"
+	"regexp"
 	"strings"
 
+	"github.com/go-sql-driver/mysql"
 	"sqlflow.org/sqlflow/go/database"
+	"sqlflow.org/sqlflow/go/gc"
 	"sqlflow.org/sqlflow/go/ir"
 	"sqlflow.org/sqlflow/go/model"
 	"sqlflow.org/sqlflow/go/verifier"
@@ -98,11 +101,67 @@ func getPredictionTableFieldNamesAndTypes(predStmt *ir.PredictStmt, db *database
 	return fieldNames, fieldTypes, nil
 }
 
+const (
+	predictModeOverwrite = "overwrite"
+	predictModeAppend    = "append"
+	predictModePartition = "partition"
+)
+
+var (
+	rePartitionColumn = regexp.MustCompile(`^\w+$`)
+	rePartitionValue  = regexp.MustCompile(`^[\w\-:. ]+$`)
+)
+
+// resolvePredictMode returns the write mode of the prediction result table
+// specified by predict.mode, and the partition column and value specified by
+// predict.partition, e.g. predict.partition="dt='2020-10-17'".
+func resolvePredictMode(predStmt *ir.PredictStmt) (mode, partitionColumn, partitionValue string, e error) {
+	mode = predictModeOverwrite
+	if m, ok := predStmt.Attributes["predict.mode"]; ok {
+		if mode, ok = m.(string); !ok {
+			return "", "", "", fmt.Errorf("predict.mode must be string")
+		}
+		mode = strings.ToLower(mode)
+	}
+	switch mode {
+	case predictModeOverwrite, predictModeAppend:
+		return mode, "", "", nil
+	case predictModePartition:
+	default:
+		return "", "", "", fmt.Errorf("predict.mode should be one of overwrite, append and partition, got %s", mode)
+	}
+	p, ok := predStmt.Attributes["predict.partition"]
+	if !ok {
+		return "", "", "", fmt.Errorf("need to specify WITH predict.partition=\"column='value'\" when predict.mode is partition")
+	}
+	partition, ok := p.(string)
+	if !ok {
+		return "", "", "", fmt.Errorf("predict.partition must be string")
+	}
+	kv := strings.SplitN(partition, "=", 2)
+	if len(kv) != 2 {
+		return "", "", "", fmt.Errorf("predict.partition should be like column='value', got %s", partition)
+	}
+	partitionColumn = strings.TrimSpace(kv[0])
+	partitionValue = strings.Trim(strings.TrimSpace(kv[1]), `'"`)
+	if !rePartitionColumn.MatchString(partitionColumn) || !rePartitionValue.MatchString(partitionValue) {
+		return "", "", "", fmt.Errorf("invalid predict.partition %s", partition)
+	}
+	return mode, partitionColumn, partitionValue, nil
+}
+
 // Create prediction table using the `PredictStmt`.
+//
+// If predict.mode is "append" or "partition", createPredictionResultTable
+// creates the INTO table if it doesn't exist, or checks that the schema of
+// the existing one matches the prediction result. Then it points
+// predStmt.ResultTable to a staging table for the prediction job to write
+// into. Call commitPredictionResult after the job to move the rows into the
+// INTO table, and call dropPredictionStagingTable to clean up.
 func createPredictionResultTable(predStmt *ir.PredictStmt, db *database.DB) error {
-	dropStmt := fmt.Sprintf("drop table if exists %s;", predStmt.ResultTable)
-	if _, e := db.Exec(dropStmt); e != nil {
-		return fmt.Errorf("failed executing %s: %q", dropStmt, e)
+	mode, partitionColumn, _, e := resolvePredictMode(predStmt)
+	if e != nil {
+		return e
 	}
 
 	names, types, e := getPredictionTableFieldNamesAndTypes(predStmt, db)
@@ -110,25 +169,213 @@ func createPredictionResultTable(predStmt *ir.PredictStmt, db *database.DB) erro
 		return e
 	}
 
+	if mode == predictModeOverwrite {
+		dropStmt := fmt.Sprintf("drop table if exists %s;", predStmt.ResultTable)
+		if _, e := db.Exec(dropStmt); e != nil {
+			return fmt.Errorf("failed executing %s: %q", dropStmt, e)
+		}
+		return createTableWithSchema(db, predStmt.ResultTable, names, types, "")
+	}
+
+	if e := createOrCheckOutputTable(db, predStmt.ResultTable, names, types, partitionColumn); e != nil {
+		return e
+	}
+	staging := tmpTableName(predStmt.ResultTable)
+	if e := createTableWithSchema(db, staging, names, types, ""); e != nil {
+		return e
+	}
+	predStmt.OutputTable, predStmt.ResultTable = predStmt.ResultTable, staging
+	return nil
+}
+
+// commitPredictionResult moves the prediction result from the staging table
+// into the INTO table if predict.mode is "append" or "partition".
+func commitPredictionResult(predStmt *ir.PredictStmt, db *database.DB) error {
+	if predStmt.OutputTable == "" {
+		return nil
+	}
+	mode, partitionColumn, partitionValue, e := resolvePredictMode(predStmt)
+	if e != nil {
+		return e
+	}
+	names, _, e := getTableSchema(db, predStmt.ResultTable)
+	if e != nil {
+		return e
+	}
+	columns := strings.Join(names, ",")
+
+	var stmts []string
+	switch {
+	case mode == predictModeAppend && db.DriverName == "mysql":
+		stmts = append(stmts, fmt.Sprintf("INSERT INTO %s (%s) SELECT %s FROM %s",
+			predStmt.OutputTable, columns, columns, predStmt.ResultTable))
+	case mode == predictModeAppend:
+		stmts = append(stmts, fmt.Sprintf("INSERT INTO TABLE %s SELECT %s FROM %s",
+			predStmt.OutputTable, columns, predStmt.ResultTable))
+	case db.DriverName == "mysql":
+		// MySQL doesn't have partitions by value, so we replace the rows whose
+		// partition column equals to the value.
+		stmts = append(stmts,
+			fmt.Sprintf("DELETE FROM %s WHERE %s='%s'", predStmt.OutputTable, partitionColumn, partitionValue),
+			fmt.Sprintf("INSERT INTO %s (%s,%s) SELECT %s,'%s' FROM %s",
+				predStmt.OutputTable, columns, partitionColumn, columns, partitionValue, predStmt.ResultTable))
+	default:
+		// Hive, MaxCompute
+		stmts = append(stmts, fmt.Sprintf("INSERT OVERWRITE TABLE %s PARTITION (%s='%s') SELECT %s FROM %s",
+			predStmt.OutputTable, partitionColumn, partitionValue, columns, predStmt.ResultTable))
+	}
+	for _, stmt := range stmts {
+		if _, e := db.Exec(stmt); e != nil {
+			return fmt.Errorf("failed executing %s: %q", stmt, e)
+		}
+	}
+	return nil
+}
+
+// dropPredictionStagingTable drops the staging table created by
+// createPredictionResultTable, if any, and points predStmt.ResultTable back
+// to the INTO table.
+func dropPredictionStagingTable(predStmt *ir.PredictStmt, db *database.DB) error {
+	if predStmt.OutputTable == "" {
+		return nil
+	}
+	staging := predStmt.ResultTable
+	predStmt.ResultTable, predStmt.OutputTable = predStmt.OutputTable, ""
+	dropStmt := fmt.Sprintf("DROP TABLE IF EXISTS %s", staging)
+	if _, e := db.Exec(dropStmt); e != nil {
+		return fmt.Errorf("failed executing %s: %q", dropStmt, e)
+	}
+	return nil
+}
+
+// createOrCheckOutputTable creates the table to accumulate prediction results
+// if it doesn't exist, otherwise it checks that the schema of the existing
+// table matches the prediction result.
+func createOrCheckOutputTable(db *database.DB, table string, names, types []string, partitionColumn string) error {
+	existingNames, existingTypes, e := getTableSchema(db, table)
+	if e != nil {
+		if isTableNotFound(e) {
+			return createTableWithSchema(db, table, names, types, partitionColumn)
+		}
+		return fmt.Errorf("failed to get the schema of table %s: %v", table, e)
+	}
+
+	expectedNames := append([]string{}, names...)
+	expectedTypes := append([]string{}, types...)
+	if partitionColumn != "" {
+		strType, e := stringFieldType(db.DriverName)
+		if e != nil {
+			return e
+		}
+		expectedNames = append(expectedNames, partitionColumn)
+		expectedTypes = append(expectedTypes, strType)
+	}
+	mismatch := fmt.Errorf("the schema of table %s (%s) doesn't match the prediction result (%s)",
+		table, formatSchema(existingNames, existingTypes), formatSchema(expectedNames, expectedTypes))
+	if len(existingNames) != len(expectedNames) {
+		return mismatch
+	}
+	for i := range expectedNames {
+		if !strings.EqualFold(existingNames[i], expectedNames[i]) ||
+			normalizeColumnType(existingTypes[i]) != normalizeColumnType(expectedTypes[i]) {
+			return mismatch
+		}
+	}
+	return nil
+}
+
+// createTableWithSchema creates a table with the given columns. If
+// partitionColumn is not empty, it is created as a partition column in Hive
+// and MaxCompute, or an ordinary column in MySQL.
+func createTableWithSchema(db *database.DB, table string, names, types []string, partitionColumn string) error {
 	var fieldItems []string
 	for idx := range names {
 		fieldItems = append(fieldItems, fmt.Sprintf("%s %s", names[idx], types[idx]))
 	}
+	partitionClause := ""
+	if partitionColumn != "" {
+		strType, e := stringFieldType(db.DriverName)
+		if e != nil {
+			return e
+		}
+		if db.DriverName == "mysql" {
+			fieldItems = append(fieldItems, fmt.Sprintf("%s %s", partitionColumn, strType))
+		} else {
+			partitionClause = fmt.Sprintf(" PARTITIONED BY (%s %s)", partitionColumn, strType)
+		}
+	}
 
 	var template string
 	if db.DriverName == "hive" {
-		template = "CREATE TABLE %s (%s) ROW FORMAT DELIMITED FIELDS TERMINATED BY \"\\001\" STORED AS TEXTFILE;"
+		template = "CREATE TABLE %s (%s)%s ROW FORMAT DELIMITED FIELDS TERMINATED BY \"\\001\" STORED AS TEXTFILE;"
 	} else {
-		template = "CREATE TABLE %s (%s);"
+		template = "CREATE TABLE %s (%s)%s;"
 	}
 
-	createStmt := fmt.Sprintf(template, predStmt.ResultTable, strings.Join(fieldItems, ","))
+	createStmt := fmt.Sprintf(template, table, strings.Join(fieldItems, ","), partitionClause)
 	if _, e := db.Exec(createStmt); e != nil {
 		return fmt.Errorf("failed executing %s: %q", createStmt, e)
 	}
 	return nil
 }
 
+// getTableSchema returns the column names and types of a table. It returns
+// an error if the table doesn't exist.
+func getTableSchema(db *database.DB, table string) ([]string, []string, error) {
+	rows, e := db.Query(fmt.Sprintf("SELECT * FROM %s LIMIT 1", table))
+	if e != nil {
+		return nil, nil, e
+	}
+	defer rows.Close()
+	columnTypes, e := rows.ColumnTypes()
+	if e != nil {
+		return nil, nil, e
+	}
+	var names, types []string
+	for _, ct := range columnTypes {
+		_, name := verifier.Decomp(ct.Name())
+		names = append(names, name)
+		types = append(types, ct.DatabaseTypeName())
+	}
+	return names, types, nil
+}
+
+// isTableNotFound returns true if e is the error of querying a table that
+// doesn't exist, rather than a connection or permission error.
+func isTableNotFound(e error) bool {
+	if me, ok := e.(*mysql.MySQLError); ok {
+		return me.Number == 1146 // ER_NO_SUCH_TABLE
+	}
+	// Hive: "SemanticException [Error 10001]: Line 1:14 Table not found 'iris.predict'"
+	// MaxCompute: "ODPS-0130131:[1,15] Table not found - table iris.predict cannot be resolved"
+	msg := strings.ToLower(e.Error())
+	return strings.Contains(msg, "table not found") || strings.Contains(msg, "doesn't exist")
+}
+
+// normalizeColumnType removes the length and the driver specific suffix of
+// a column type, e.g. VARCHAR(255) => VARCHAR, STRING_TYPE => STRING.
+func normalizeColumnType(typ string) string {
+	typ = strings.ToUpper(strings.TrimSpace(typ))
+	if i := strings.Index(typ, "("); i >= 0 {
+		typ = typ[:i]
+	}
+	return strings.TrimSuffix(typ, "_TYPE")
+}
+
+func formatSchema(names, types []string) string {
+	var items []string
+	for i := range names {
+		items = append(items, fmt.Sprintf("%s %s", names[i], types[i]))
+	}
+	return strings.Join(items, ", ")
+}
+
+// tmpTableName returns a random table name in the same database of table.
+// The name follows the convention of the gc package to be collected.
+func tmpTableName(table string) string {
+	return gc.TmpTableName(table)
+}
+
 func createExplainResultTable(db *database.DB, ir *ir.ExplainStmt, tableName string, modelType int, estimator string) error {
 	dropStmt := fmt.Sprintf(`DROP TABLE IF EXISTS %s;`, tableName)
 	var e error
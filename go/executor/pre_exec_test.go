@@ -0,0 +1,164 @@
+// Copyright 2020 The SQLFlow Authors. All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/stretchr/testify/assert"
+	"sqlflow.org/sqlflow/go/database"
+	"sqlflow.org/sqlflow/go/ir"
+	"sqlflow.org/sqlflow/go/test"
+)
+
+func TestResolvePredictMode(t *testing.T) {
+	a := assert.New(t)
+	stmt := &ir.PredictStmt{Attributes: map[string]interface{}{}}
+	mode, _, _, e := resolvePredictMode(stmt)
+	a.NoError(e)
+	a.Equal(predictModeOverwrite, mode)
+
+	stmt.Attributes["predict.mode"] = "APPEND"
+	mode, _, _, e = resolvePredictMode(stmt)
+	a.NoError(e)
+	a.Equal(predictModeAppend, mode)
+
+	stmt.Attributes["predict.mode"] = "partition"
+	_, _, _, e = resolvePredictMode(stmt)
+	a.Error(e)
+
+	stmt.Attributes["predict.partition"] = "dt='2020-10-17'"
+	mode, col, val, e := resolvePredictMode(stmt)
+	a.NoError(e)
+	a.Equal(predictModePartition, mode)
+	a.Equal("dt", col)
+	a.Equal("2020-10-17", val)
+
+	stmt.Attributes["predict.partition"] = "dt='1'; DROP TABLE iris.train"
+	_, _, _, e = resolvePredictMode(stmt)
+	a.Error(e)
+
+	stmt.Attributes["predict.mode"] = "upsert"
+	_, _, _, e = resolvePredictMode(stmt)
+	a.Error(e)
+}
+
+func TestNormalizeColumnType(t *testing.T) {
+	a := assert.New(t)
+	a.Equal("VARCHAR", normalizeColumnType("varchar(255)"))
+	a.Equal("STRING", normalizeColumnType("STRING_TYPE"))
+	a.Equal("DOUBLE", normalizeColumnType("DOUBLE"))
+}
+
+func TestIsTableNotFound(t *testing.T) {
+	a := assert.New(t)
+	a.True(isTableNotFound(&mysql.MySQLError{Number: 1146, Message: "Table 'iris.predict' doesn't exist"}))
+	a.False(isTableNotFound(&mysql.MySQLError{Number: 1142, Message: "SELECT command denied to user 'guest'"}))
+	a.True(isTableNotFound(fmt.Errorf("FAILED: SemanticException [Error 10001]: Line 1:14 Table not found 'iris.predict'")))
+	a.True(isTableNotFound(fmt.Errorf("ODPS-0130131:[1,15] Table not found - table iris.predict cannot be resolved")))
+	a.False(isTableNotFound(fmt.Errorf("dial tcp 127.0.0.1:3306: connect: connection refused")))
+}
+
+func TestTmpTableName(t *testing.T) {
+	a := assert.New(t)
+	a.True(strings.HasPrefix(tmpTableName("iris.predict"), "iris.sqlflow_tmp_"))
+	a.True(strings.HasPrefix(tmpTableName("predict"), "sqlflow_tmp_"))
+}
+
+func TestAppendPredictionResult(t *testing.T) {
+	if test.GetEnv("SQLFLOW_TEST_DB", "mysql") != "mysql" {
+		t.Skip("only test appending prediction result on MySQL")
+	}
+	a := assert.New(t)
+	db := database.GetTestingDBSingleton()
+	table := "iris.predict_append_test"
+	_, e := db.Exec(fmt.Sprintf("DROP TABLE IF EXISTS %s", table))
+	a.NoError(e)
+
+	for i := 0; i < 2; i++ {
+		stmt := &ir.PredictStmt{
+			Select:       "SELECT * FROM iris.test",
+			ResultTable:  table,
+			ResultColumn: "class",
+			Attributes:   map[string]interface{}{"predict.mode": "partition", "predict.partition": "dt='2020-10-17'"},
+		}
+		a.NoError(createPredictionResultTable(stmt, db))
+		a.Equal(table, stmt.OutputTable)
+		a.NotEqual(table, stmt.ResultTable)
+		_, e = db.Exec(fmt.Sprintf("INSERT INTO %s SELECT * FROM iris.test", stmt.ResultTable))
+		a.NoError(e)
+		a.NoError(commitPredictionResult(stmt, db))
+		a.NoError(dropPredictionStagingTable(stmt, db))
+		a.Equal(table, stmt.ResultTable)
+	}
+
+	// the rows of the partition are replaced
+	var predicted, total int
+	a.NoError(db.QueryRow(fmt.Sprintf("SELECT COUNT(*) FROM %s", table)).Scan(&predicted))
+	a.NoError(db.QueryRow("SELECT COUNT(*) FROM iris.test").Scan(&total))
+	a.Equal(total, predicted)
+
+	// mismatched schema
+	stmt := &ir.PredictStmt{
+		Select:       "SELECT sepal_length, class FROM iris.test",
+		ResultTable:  table,
+		ResultColumn: "class",
+		Attributes:   map[string]interface{}{"predict.mode": "append"},
+	}
+	a.Error(createPredictionResultTable(stmt, db))
+}
+
+func TestAppendEvaluationResult(t *testing.T) {
+	if test.GetEnv("SQLFLOW_TEST_DB", "mysql") != "mysql" {
+		t.Skip("only test appending evaluation result on MySQL")
+	}
+	a := assert.New(t)
+	db := database.GetTestingDBSingleton()
+	table := "iris.evaluate_append_test"
+	_, e := db.Exec(fmt.Sprintf("DROP TABLE IF EXISTS %s", table))
+	a.NoError(e)
+
+	for i := 0; i < 2; i++ {
+		stmt := &ir.EvaluateStmt{
+			ModelName:  "sqlflow_models.my_dnn_model",
+			Into:       table,
+			Attributes: map[string]interface{}{"evaluate.mode": "append", "validation.metrics": "Accuracy"},
+		}
+		a.NoError(createEvaluationResultTable(db, stmt, evaluationMetricNames(stmt)))
+		a.Equal(table, stmt.OutputTable)
+		_, e = db.Exec(fmt.Sprintf("INSERT INTO %s VALUES (0.5, 0.9)", stmt.Into))
+		a.NoError(e)
+		a.NoError(commitEvaluationResult(stmt, db))
+		a.NoError(dropEvaluationStagingTable(stmt, db))
+		a.Equal(table, stmt.Into)
+	}
+
+	var rows int
+	var accuracy float64
+	a.NoError(db.QueryRow(fmt.Sprintf("SELECT COUNT(*), MAX(Accuracy) FROM %s WHERE model_name='sqlflow_models.my_dnn_model'", table)).Scan(&rows, &accuracy))
+	a.Equal(2, rows)
+	a.Equal(0.9, accuracy)
+
+	stmt := &ir.EvaluateStmt{Into: table, Attributes: map[string]interface{}{"evaluate.mode": "upsert"}}
+	a.Error(createEvaluationResultTable(db, stmt, evaluationMetricNames(stmt)))
+}
+
+func TestEvaluationMetricNames(t *testing.T) {
+	a := assert.New(t)
+	stmt := &ir.EvaluateStmt{Attributes: map[string]interface{}{"validation.metrics": "Accuracy, AUC"}}
+	a.Equal([]string{"loss", "Accuracy", "AUC"}, evaluationMetricNames(stmt))
+}
@@ -0,0 +1,98 @@
+// Copyright 2020 The SQLFlow Authors. All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"sqlflow.org/sqlflow/go/database"
+	"sqlflow.org/sqlflow/go/ir"
+)
+
+const (
+	// EnvPredictMaxRows is the environment variable to set the maximum
+	// number of prediction rows returned to the client when the INTO
+	// clause is omitted.
+	EnvPredictMaxRows     = "SQLFLOW_PREDICT_MAX_ROWS"
+	defaultPredictMaxRows = 1000
+)
+
+func predictMaxRows() (int, error) {
+	s := os.Getenv(EnvPredictMaxRows)
+	if s == "" {
+		return defaultPredictMaxRows, nil
+	}
+	n, e := strconv.Atoi(s)
+	if e != nil || n <= 0 {
+		return 0, fmt.Errorf("%s should be a positive integer, got %q", EnvPredictMaxRows, s)
+	}
+	return n, nil
+}
+
+// predictionTmpTable returns a temporary table name for the prediction
+// result. The table is in the database of the data source, or in the
+// database of the model if the data source doesn't specify one.
+func predictionTmpTable(predStmt *ir.PredictStmt, dataSource string) (string, error) {
+	dbName, e := database.GetDatabaseName(dataSource)
+	if e != nil {
+		return "", e
+	}
+	if dbName == "" {
+		if idx := strings.Index(predStmt.Using, "."); idx > 0 {
+			dbName = predStmt.Using[:idx]
+		}
+	}
+	if dbName == "" {
+		return "", fmt.Errorf("please specify the database in the data source or use the INTO clause to predict")
+	}
+	return tmpTableName(dbName + ".predict"), nil
+}
+
+// executePredictToClient runs the prediction job into a temporary table
+// and writes at most SQLFLOW_PREDICT_MAX_ROWS rows of the result to the
+// client as the result of a query, then drops the table. The table is named
+// by tmpTableName, so that it is collected by gc if dropping fails.
+func executePredictToClient(it Executor, predStmt *ir.PredictStmt) error {
+	mode, _, _, e := resolvePredictMode(predStmt)
+	if e != nil {
+		return e
+	}
+	if mode != predictModeOverwrite {
+		return fmt.Errorf("predict.mode=%s requires the INTO clause", mode)
+	}
+	maxRows, e := predictMaxRows()
+	if e != nil {
+		return e
+	}
+	s := it.GetPythonExecutor()
+	resultTable, e := predictionTmpTable(predStmt, s.Session.DbConnStr)
+	if e != nil {
+		return e
+	}
+	predStmt.ResultTable = resultTable
+	defer func() {
+		if _, e := s.Db.Exec(fmt.Sprintf("DROP TABLE IF EXISTS %s", resultTable)); e != nil {
+			s.logger().Errorf("failed to drop the prediction result table %s, leaving it to gc: %v", resultTable, e)
+		}
+		predStmt.ResultTable = ""
+	}()
+
+	if e := Run(it, predStmt); e != nil {
+		return e
+	}
+	return runQuery(s.Writer, fmt.Sprintf("SELECT * FROM %s LIMIT %d", resultTable, maxRows), s.Db)
+}
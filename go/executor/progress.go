@@ -0,0 +1,60 @@
+// Copyright 2020 The SQLFlow Authors. All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// progressLinePrefix marks a line printed by runtime.progress.report in
+// the generated Python program. The rest of the line is a JSON object.
+const progressLinePrefix = "SQLFLOW_PROGRESS "
+
+// Progress is a structured training progress record, e.g. the loss value
+// at some step of some epoch.
+type Progress struct {
+	Epoch      int64   `json:"epoch"`
+	Step       int64   `json:"step"`
+	Metric     string  `json:"metric"`
+	Value      float64 `json:"value"`
+	ETASeconds int64   `json:"eta_seconds"`
+}
+
+// String formats the progress for terminal output.
+func (p Progress) String() string {
+	s := fmt.Sprintf("epoch %d, step %d: %s = %g", p.Epoch, p.Step, p.Metric, p.Value)
+	if p.ETASeconds >= 0 {
+		s += fmt.Sprintf(", ETA %ds", p.ETASeconds)
+	}
+	return s
+}
+
+// parseProgress returns the progress record if line is printed by
+// runtime.progress.report, or false if line is an ordinary log line.
+func parseProgress(line string) (Progress, bool) {
+	line = strings.TrimSpace(line)
+	if !strings.HasPrefix(line, progressLinePrefix) {
+		return Progress{}, false
+	}
+	p := Progress{ETASeconds: -1}
+	if e := json.Unmarshal([]byte(strings.TrimPrefix(line, progressLinePrefix)), &p); e != nil {
+		return Progress{}, false
+	}
+	if p.Metric == "" {
+		return Progress{}, false
+	}
+	return p, true
+}
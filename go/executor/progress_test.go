@@ -0,0 +1,58 @@
+// Copyright 2020 The SQLFlow Authors. All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"sqlflow.org/sqlflow/go/pipe"
+)
+
+func TestParseProgress(t *testing.T) {
+	a := assert.New(t)
+	p, ok := parseProgress(`SQLFLOW_PROGRESS {"epoch": 2, "step": 100, "metric": "loss", "value": 0.25, "eta_seconds": 30}` + "\n")
+	a.True(ok)
+	a.Equal(Progress{Epoch: 2, Step: 100, Metric: "loss", Value: 0.25, ETASeconds: 30}, p)
+	a.Equal("epoch 2, step 100: loss = 0.25, ETA 30s", p.String())
+
+	p, ok = parseProgress(`SQLFLOW_PROGRESS {"epoch": 1, "step": 1, "metric": "auc", "value": 0.5}`)
+	a.True(ok)
+	a.Equal(int64(-1), p.ETASeconds)
+	a.Equal("epoch 1, step 1: auc = 0.5", p.String())
+
+	_, ok = parseProgress("WARNING: something happened\n")
+	a.False(ok)
+	_, ok = parseProgress("SQLFLOW_PROGRESS not a json")
+	a.False(ok)
+	_, ok = parseProgress(`SQLFLOW_PROGRESS {"epoch": 1}`)
+	a.False(ok)
+}
+
+func TestLogChanWriterProgress(t *testing.T) {
+	a := assert.New(t)
+	rd, wr := pipe.Pipe()
+	go func() {
+		defer wr.Close()
+		cw := &logChanWriter{wr: wr}
+		cw.Write([]byte("Start training\nSQLFLOW_PROGRESS {\"epoch\": 1, \"step\": 10, "))
+		cw.Write([]byte("\"metric\": \"loss\", \"value\": 1.5}\n"))
+	}()
+
+	c := rd.ReadAll()
+	a.Equal("Start training\n", <-c)
+	a.Equal(Progress{Epoch: 1, Step: 10, Metric: "loss", Value: 1.5, ETASeconds: -1}, <-c)
+	_, more := <-c
+	a.False(more)
+}
@@ -0,0 +1,311 @@
+// Copyright 2020 The SQLFlow Authors. All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package gateway serves the SQLFlow gRPC service and the model zoo service
+// over HTTP for the clients that cannot speak gRPC. The request and response
+// bodies are the JSON encoded proto messages. A request to the method M of
+// the SQLFlow service is `POST /v1/sqlflow/M`, and of the model zoo service
+// is `POST /v1/modelzoo/M`. The responses of the streaming methods Run and
+// DownloadModel are written as Server-Sent Events if the request accepts
+// text/event-stream, or as JSON lines otherwise.
+//
+// The gateway calls the gRPC servers as a client, so the requests go
+// through the same authentication as the gRPC requests. The HTTP header
+// Authorization is forwarded as the gRPC metadata authorization.
+package gateway
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	pb "sqlflow.org/sqlflow/go/proto"
+)
+
+type unaryMethod struct {
+	newRequest func() proto.Message
+	call       func(ctx context.Context, req proto.Message) (proto.Message, error)
+}
+
+type streamMethod struct {
+	newRequest func() proto.Message
+	// call starts the stream and returns the function to receive the
+	// responses, which returns io.EOF at the end of the stream.
+	call func(ctx context.Context, req proto.Message) (func() (proto.Message, error), error)
+}
+
+// Gateway is the HTTP handler calling the gRPC services.
+type Gateway struct {
+	mux       *http.ServeMux
+	marshaler *jsonpb.Marshaler
+}
+
+// New returns a Gateway calling sqlflow and modelZoo. modelZoo could be nil
+// if there is no model zoo server.
+func New(sqlflow pb.SQLFlowClient, modelZoo pb.ModelZooServerClient) *Gateway {
+	g := &Gateway{
+		mux:       http.NewServeMux(),
+		marshaler: &jsonpb.Marshaler{OrigName: true, EmitDefaults: true},
+	}
+	g.handleStream("/v1/sqlflow/Run", streamMethod{
+		newRequest: func() proto.Message { return &pb.Request{} },
+		call: func(ctx context.Context, req proto.Message) (func() (proto.Message, error), error) {
+			stream, e := sqlflow.Run(ctx, req.(*pb.Request))
+			if e != nil {
+				return nil, e
+			}
+			return func() (proto.Message, error) { return stream.Recv() }, nil
+		},
+	})
+	g.handleUnary("/v1/sqlflow/Fetch", unaryMethod{
+		newRequest: func() proto.Message { return &pb.FetchRequest{} },
+		call: func(ctx context.Context, req proto.Message) (proto.Message, error) {
+			return sqlflow.Fetch(ctx, req.(*pb.FetchRequest))
+		},
+	})
+	g.handleUnary("/v1/sqlflow/ListModels", unaryMethod{
+		newRequest: func() proto.Message { return &pb.ListModelsRequest{} },
+		call: func(ctx context.Context, req proto.Message) (proto.Message, error) {
+			return sqlflow.ListModels(ctx, req.(*pb.ListModelsRequest))
+		},
+	})
+	g.handleUnary("/v1/sqlflow/Cancel", unaryMethod{
+		newRequest: func() proto.Message { return &pb.Job{} },
+		call: func(ctx context.Context, req proto.Message) (proto.Message, error) {
+			return sqlflow.Cancel(ctx, req.(*pb.Job))
+		},
+	})
+	g.handleUnary("/v1/sqlflow/Submit", unaryMethod{
+		newRequest: func() proto.Message { return &pb.Request{} },
+		call: func(ctx context.Context, req proto.Message) (proto.Message, error) {
+			return sqlflow.Submit(ctx, req.(*pb.Request))
+		},
+	})
+	g.handleUnary("/v1/sqlflow/List", unaryMethod{
+		newRequest: func() proto.Message { return &pb.ListJobsRequest{} },
+		call: func(ctx context.Context, req proto.Message) (proto.Message, error) {
+			return sqlflow.List(ctx, req.(*pb.ListJobsRequest))
+		},
+	})
+	if modelZoo != nil {
+		g.handleModelZoo(modelZoo)
+	}
+	return g
+}
+
+func (g *Gateway) handleModelZoo(modelZoo pb.ModelZooServerClient) {
+	g.handleUnary("/v1/modelzoo/ListModelRepos", unaryMethod{
+		newRequest: func() proto.Message { return &pb.ListModelRequest{} },
+		call: func(ctx context.Context, req proto.Message) (proto.Message, error) {
+			return modelZoo.ListModelRepos(ctx, req.(*pb.ListModelRequest))
+		},
+	})
+	g.handleUnary("/v1/modelzoo/ListModels", unaryMethod{
+		newRequest: func() proto.Message { return &pb.ListModelRequest{} },
+		call: func(ctx context.Context, req proto.Message) (proto.Message, error) {
+			return modelZoo.ListModels(ctx, req.(*pb.ListModelRequest))
+		},
+	})
+	g.handleUnary("/v1/modelzoo/DropModelRepo", unaryMethod{
+		newRequest: func() proto.Message { return &pb.ReleaseModelRepoRequest{} },
+		call: func(ctx context.Context, req proto.Message) (proto.Message, error) {
+			return modelZoo.DropModelRepo(ctx, req.(*pb.ReleaseModelRepoRequest))
+		},
+	})
+	g.handleUnary("/v1/modelzoo/ReleaseModel", unaryMethod{
+		newRequest: func() proto.Message { return &pb.ReleaseModelRequest{} },
+		call: func(ctx context.Context, req proto.Message) (proto.Message, error) {
+			return modelZoo.ReleaseModel(ctx, req.(*pb.ReleaseModelRequest))
+		},
+	})
+	g.handleUnary("/v1/modelzoo/DropModel", unaryMethod{
+		newRequest: func() proto.Message { return &pb.ReleaseModelRequest{} },
+		call: func(ctx context.Context, req proto.Message) (proto.Message, error) {
+			return modelZoo.DropModel(ctx, req.(*pb.ReleaseModelRequest))
+		},
+	})
+	g.handleUnary("/v1/modelzoo/GetModelMeta", unaryMethod{
+		newRequest: func() proto.Message { return &pb.ReleaseModelRequest{} },
+		call: func(ctx context.Context, req proto.Message) (proto.Message, error) {
+			return modelZoo.GetModelMeta(ctx, req.(*pb.ReleaseModelRequest))
+		},
+	})
+	g.handleStream("/v1/modelzoo/DownloadModel", streamMethod{
+		newRequest: func() proto.Message { return &pb.ReleaseModelRequest{} },
+		call: func(ctx context.Context, req proto.Message) (func() (proto.Message, error), error) {
+			stream, e := modelZoo.DownloadModel(ctx, req.(*pb.ReleaseModelRequest))
+			if e != nil {
+				return nil, e
+			}
+			return func() (proto.Message, error) { return stream.Recv() }, nil
+		},
+	})
+}
+
+// ServeHTTP implements http.Handler.
+func (g *Gateway) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	g.mux.ServeHTTP(w, r)
+}
+
+func (g *Gateway) handleUnary(path string, m unaryMethod) {
+	g.mux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+		req, ctx, ok := g.decodeRequest(w, r, m.newRequest())
+		if !ok {
+			return
+		}
+		res, e := m.call(ctx, req)
+		if e != nil {
+			g.writeError(w, e)
+			return
+		}
+		var buf bytes.Buffer
+		if e := g.marshaler.Marshal(&buf, res); e != nil {
+			g.writeError(w, e)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(buf.Bytes())
+	})
+}
+
+func (g *Gateway) handleStream(path string, m streamMethod) {
+	g.mux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+		req, ctx, ok := g.decodeRequest(w, r, m.newRequest())
+		if !ok {
+			return
+		}
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		recv, e := m.call(ctx, req)
+		if e != nil {
+			g.writeError(w, e)
+			return
+		}
+		// The error of a gRPC stream is returned by the first Recv, write it
+		// with the HTTP status code if the stream fails at once.
+		res, e := recv()
+		if e != nil && e != io.EOF {
+			g.writeError(w, e)
+			return
+		}
+		sse := strings.Contains(r.Header.Get("Accept"), "text/event-stream")
+		if sse {
+			w.Header().Set("Content-Type", "text/event-stream")
+			w.Header().Set("Cache-Control", "no-cache")
+		} else {
+			w.Header().Set("Content-Type", "application/x-ndjson")
+		}
+		flusher, _ := w.(http.Flusher)
+		for ; e != io.EOF; res, e = recv() {
+			event := "message"
+			var buf bytes.Buffer
+			if e != nil {
+				event = "error"
+				buf.Write(errorBody(e))
+			} else if e := g.marshaler.Marshal(&buf, res); e != nil {
+				event = "error"
+				buf.Write(errorBody(e))
+			}
+			if sse {
+				fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, buf.Bytes())
+			} else {
+				fmt.Fprintf(w, "%s\n", buf.Bytes())
+			}
+			if flusher != nil {
+				flusher.Flush()
+			}
+			if event == "error" {
+				return
+			}
+		}
+	})
+}
+
+// decodeRequest decodes the body of r into req, and returns the context
+// carrying the authorization header. It writes the error to w and returns
+// false if failed.
+func (g *Gateway) decodeRequest(w http.ResponseWriter, r *http.Request, req proto.Message) (proto.Message, context.Context, bool) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		w.Write(errorBody(status.Errorf(codes.Unimplemented, "method %s is not allowed", r.Method)))
+		return nil, nil, false
+	}
+	if e := (&jsonpb.Unmarshaler{AllowUnknownFields: true}).Unmarshal(r.Body, req); e != nil && e != io.EOF {
+		g.writeError(w, status.Errorf(codes.InvalidArgument, "cannot decode the request: %v", e))
+		return nil, nil, false
+	}
+	ctx := r.Context()
+	if auth := r.Header.Get("Authorization"); auth != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, "authorization", auth)
+	}
+	return req, ctx, true
+}
+
+func (g *Gateway) writeError(w http.ResponseWriter, e error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(httpStatus(status.Code(e)))
+	w.Write(errorBody(e))
+}
+
+// errorBody encodes e as `{"error": {"code": <gRPC code>, "message": ...}}`.
+func errorBody(e error) []byte {
+	s := status.Convert(e)
+	b, _ := json.Marshal(map[string]interface{}{
+		"error": map[string]interface{}{
+			"code":    int(s.Code()),
+			"message": s.Message(),
+		},
+	})
+	return b
+}
+
+// httpStatus maps the gRPC status code to the HTTP status code.
+func httpStatus(code codes.Code) int {
+	switch code {
+	case codes.OK:
+		return http.StatusOK
+	case codes.Canceled:
+		return 499 // client closed request
+	case codes.InvalidArgument, codes.FailedPrecondition, codes.OutOfRange:
+		return http.StatusBadRequest
+	case codes.DeadlineExceeded:
+		return http.StatusGatewayTimeout
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.AlreadyExists, codes.Aborted:
+		return http.StatusConflict
+	case codes.PermissionDenied:
+		return http.StatusForbidden
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized
+	case codes.ResourceExhausted:
+		return http.StatusTooManyRequests
+	case codes.Unimplemented:
+		return http.StatusNotImplemented
+	case codes.Unavailable:
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
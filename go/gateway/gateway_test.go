@@ -0,0 +1,140 @@
+// Copyright 2020 The SQLFlow Authors. All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gateway
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	pb "sqlflow.org/sqlflow/go/proto"
+)
+
+type mockSQLFlowClient struct {
+	pb.SQLFlowClient
+}
+
+func (c *mockSQLFlowClient) Run(ctx context.Context, req *pb.Request, opts ...grpc.CallOption) (pb.SQLFlow_RunClient, error) {
+	if req.Stmts == "ERROR" {
+		return &mockRunClient{err: status.Error(codes.InvalidArgument, "bad statement")}, nil
+	}
+	log0, _ := pb.EncodeMessage("log 0")
+	log1, _ := pb.EncodeMessage("log 1")
+	return &mockRunClient{responses: []*pb.Response{log0, log1}, err: io.EOF}, nil
+}
+
+func (c *mockSQLFlowClient) Fetch(ctx context.Context, req *pb.FetchRequest, opts ...grpc.CallOption) (*pb.FetchResponse, error) {
+	md, _ := metadata.FromOutgoingContext(ctx)
+	if auth := md.Get("authorization"); len(auth) == 0 || auth[0] != "Bearer token" {
+		return nil, status.Error(codes.Unauthenticated, "invalid token")
+	}
+	return &pb.FetchResponse{UpdatedFetchSince: req, Eof: true}, nil
+}
+
+type mockRunClient struct {
+	grpc.ClientStream
+	responses []*pb.Response
+	err       error
+}
+
+func (c *mockRunClient) Recv() (*pb.Response, error) {
+	if len(c.responses) == 0 {
+		return nil, c.err
+	}
+	res := c.responses[0]
+	c.responses = c.responses[1:]
+	return res, nil
+}
+
+func post(url, body string, header map[string]string) (int, string, error) {
+	req, e := http.NewRequest(http.MethodPost, url, strings.NewReader(body))
+	if e != nil {
+		return 0, "", e
+	}
+	for k, v := range header {
+		req.Header.Set(k, v)
+	}
+	res, e := http.DefaultClient.Do(req)
+	if e != nil {
+		return 0, "", e
+	}
+	defer res.Body.Close()
+	b, e := ioutil.ReadAll(res.Body)
+	return res.StatusCode, string(b), e
+}
+
+func TestGateway(t *testing.T) {
+	a := assert.New(t)
+	ts := httptest.NewServer(New(&mockSQLFlowClient{}, nil))
+	defer ts.Close()
+
+	run := ts.URL + "/v1/sqlflow/Run"
+	code, body, e := post(run, `{"stmts": "SELECT 1;", "session": {"user_id": "alice"}}`, nil)
+	a.NoError(e)
+	a.Equal(http.StatusOK, code)
+	a.Equal(`{"message":{"message":"log 0"}}
+{"message":{"message":"log 1"}}
+`, body)
+
+	code, body, e = post(run, `{"stmts": "SELECT 1;"}`, map[string]string{"Accept": "text/event-stream"})
+	a.NoError(e)
+	a.Equal(http.StatusOK, code)
+	a.Equal(`event: message
+data: {"message":{"message":"log 0"}}
+
+event: message
+data: {"message":{"message":"log 1"}}
+
+`, body)
+
+	code, body, e = post(run, `{"stmts": "ERROR"}`, nil)
+	a.NoError(e)
+	a.Equal(http.StatusBadRequest, code)
+	a.Equal(fmt.Sprintf(`{"error":{"code":%d,"message":"bad statement"}}`, codes.InvalidArgument), body)
+
+	code, _, e = post(run, `{"no_such_field": `, nil)
+	a.NoError(e)
+	a.Equal(http.StatusBadRequest, code)
+
+	fetch := ts.URL + "/v1/sqlflow/Fetch"
+	code, body, e = post(fetch, `{"job": {"id": "job_1"}, "step_id": "2"}`, map[string]string{"Authorization": "Bearer token"})
+	a.NoError(e)
+	a.Equal(http.StatusOK, code)
+	a.Contains(body, `"updated_fetch_since":{"job":{"id":"job_1","namespace":""},"step_id":"2","step_phase":""}`)
+	a.Contains(body, `"eof":true`)
+
+	code, _, e = post(fetch, `{"job": {"id": "job_1"}}`, nil)
+	a.NoError(e)
+	a.Equal(http.StatusUnauthorized, code)
+
+	res, e := http.Get(fetch)
+	a.NoError(e)
+	res.Body.Close()
+	a.Equal(http.StatusMethodNotAllowed, res.StatusCode)
+
+	// the model zoo methods are not served without a model zoo server
+	code, _, e = post(ts.URL+"/v1/modelzoo/ListModels", `{}`, nil)
+	a.NoError(e)
+	a.Equal(http.StatusNotFound, code)
+}
@@ -0,0 +1,230 @@
+// Copyright 2020 The SQLFlow Authors. All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package gc finds and drops the tables SQLFlow leaves behind: temporary
+// tables, half-written model tables and expired model versions.
+package gc
+
+import (
+	"database/sql"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"sqlflow.org/sqlflow/go/database"
+	"sqlflow.org/sqlflow/go/model"
+	"sqlflow.org/sqlflow/go/randstring"
+)
+
+// TmpTablePrefix is the name prefix of the temporary tables created by SQLFlow.
+const TmpTablePrefix = "sqlflow_tmp_"
+
+// Kinds of garbage.
+const (
+	TmpTable      = "temporary table"
+	OrphanedModel = "orphaned model table"
+	ExpiredModel  = "expired model version"
+)
+
+var versionTableRegexp = regexp.MustCompile(`^(.+)__v(\d+)$`)
+
+// TmpTableName returns a temporary table name in the same database of
+// table. The name records the creation time, so that Collect could tell
+// the age of the table on all databases. The tables left by the failed
+// statements, e.g., the prediction results of PREDICT without INTO that
+// failed to be dropped, are collected by the name.
+func TmpTableName(table string) string {
+	name := fmt.Sprintf("%s%d_%s", TmpTablePrefix, time.Now().Unix(), randstring.Generate(16))
+	if idx := strings.LastIndex(table, "."); idx >= 0 {
+		return table[:idx+1] + name
+	}
+	return name
+}
+
+// tmpTableTime returns the creation time recorded in the temporary table
+// name, or the zero time if not recorded.
+func tmpTableTime(name string) time.Time {
+	parts := strings.SplitN(strings.TrimPrefix(name, TmpTablePrefix), "_", 2)
+	if len(parts) != 2 {
+		return time.Time{}
+	}
+	sec, e := strconv.ParseInt(parts[0], 10, 64)
+	if e != nil {
+		return time.Time{}
+	}
+	return time.Unix(sec, 0)
+}
+
+// Policy tells what to collect.
+type Policy struct {
+	// TmpTableAge is the minimum age of the temporary tables and the
+	// orphaned model tables to drop.
+	TmpTableAge time.Duration
+	// ModelAge is the minimum age of the model versions to drop. The
+	// default 0 keeps all model versions.
+	ModelAge time.Duration
+	// DryRun lists the garbage without dropping.
+	DryRun bool
+}
+
+// Garbage is a table to drop.
+type Garbage struct {
+	Table     string
+	Kind      string
+	CreatedAt time.Time
+}
+
+type table struct {
+	name      string
+	createdAt time.Time // zero if unknown
+}
+
+// Collect finds the garbage in the database dbName by the policy, and drops
+// them unless policy.DryRun. Tables of unknown age, i.e. temporary tables
+// created before their names record the creation time and model tables on
+// databases other than MySQL, are never collected.
+func Collect(db *database.DB, dbName string, policy *Policy) ([]*Garbage, error) {
+	tables, e := listTables(db, dbName)
+	if e != nil {
+		return nil, fmt.Errorf("cannot list tables in %s: %v", dbName, e)
+	}
+	records := []*model.VersionRecord{}
+	for _, t := range tables {
+		if t.name == model.VersionRegistryTable {
+			if records, e = model.ListVersionRecords(db, dbName); e != nil {
+				return nil, fmt.Errorf("cannot list model versions in %s: %v", dbName, e)
+			}
+		}
+	}
+
+	now := time.Now()
+	registered := map[string]bool{}
+	for _, r := range records {
+		registered[strings.TrimPrefix(r.Table, dbName+".")] = true
+	}
+	garbage := []*Garbage{}
+	for _, t := range tables {
+		kind := ""
+		if strings.HasPrefix(t.name, TmpTablePrefix) {
+			kind = TmpTable
+			if c := tmpTableTime(t.name); !c.IsZero() {
+				t.createdAt = c
+			}
+		} else if versionTableRegexp.MatchString(t.name) && !registered[t.name] && model.HasModelHeader(db, qualify(dbName, t.name)) {
+			// NOTE: a user table may be named like a model version too.
+			kind = OrphanedModel
+		}
+		if kind == "" || t.createdAt.IsZero() || now.Sub(t.createdAt) < policy.TmpTableAge {
+			continue
+		}
+		garbage = append(garbage, &Garbage{Table: qualify(dbName, t.name), Kind: kind, CreatedAt: t.createdAt})
+	}
+	latest := map[string]int64{}
+	for _, r := range records {
+		if r.Version > latest[r.Name] {
+			latest[r.Name] = r.Version
+		}
+	}
+	expired := map[string][]int64{}
+	if policy.ModelAge > 0 {
+		for _, r := range records {
+			// the latest version is kept however old it is
+			if r.Version == latest[r.Name] {
+				continue
+			}
+			if !r.CreatedAt.IsZero() && now.Sub(r.CreatedAt) >= policy.ModelAge {
+				garbage = append(garbage, &Garbage{Table: r.Table, Kind: ExpiredModel, CreatedAt: r.CreatedAt})
+				expired[r.Name] = append(expired[r.Name], r.Version)
+			}
+		}
+	}
+	sort.SliceStable(garbage, func(i, j int) bool { return garbage[i].CreatedAt.Before(garbage[j].CreatedAt) })
+	if policy.DryRun {
+		return garbage, nil
+	}
+
+	for _, g := range garbage {
+		if g.Kind == ExpiredModel {
+			continue
+		}
+		if _, e := db.Exec(fmt.Sprintf("DROP TABLE IF EXISTS %s", g.Table)); e != nil {
+			return nil, fmt.Errorf("cannot drop %s %s: %v", g.Kind, g.Table, e)
+		}
+	}
+	for name, versions := range expired {
+		if e := model.RemoveVersions(db, name, versions); e != nil {
+			return nil, fmt.Errorf("cannot remove expired versions of model %s: %v", name, e)
+		}
+		if e := model.DeleteCatalog(db, model.CatalogTable(), name, versions); e != nil {
+			return nil, fmt.Errorf("cannot remove expired versions of model %s from the catalog: %v", name, e)
+		}
+	}
+	return garbage, nil
+}
+
+func qualify(dbName, name string) string {
+	if dbName == "" {
+		return name
+	}
+	return dbName + "." + name
+}
+
+// listTables returns the tables in dbName. The creation time is known only
+// on MySQL.
+func listTables(db *database.DB, dbName string) ([]*table, error) {
+	var query string
+	switch db.DriverName {
+	case "mysql":
+		query = fmt.Sprintf("SELECT TABLE_NAME, CREATE_TIME FROM information_schema.TABLES WHERE TABLE_SCHEMA='%s'", dbName)
+	case "hive", "maxcompute", "alisa":
+		query = fmt.Sprintf("SHOW TABLES IN %s", dbName)
+	default:
+		return nil, fmt.Errorf("unsupported driver type %s", db.DriverName)
+	}
+	rows, e := db.Query(query)
+	if e != nil {
+		return nil, e
+	}
+	defer rows.Close()
+	tables := []*table{}
+	for rows.Next() {
+		t := &table{}
+		if db.DriverName == "mysql" {
+			var createdAt sql.NullString
+			if e := rows.Scan(&t.name, &createdAt); e != nil {
+				return nil, e
+			}
+			t.createdAt = parseTime(createdAt.String)
+		} else {
+			if e := rows.Scan(&t.name); e != nil {
+				return nil, e
+			}
+			// NOTE: MaxCompute lists tables like owner:table.
+			t.name = t.name[strings.LastIndex(t.name, ":")+1:]
+		}
+		tables = append(tables, t)
+	}
+	return tables, rows.Err()
+}
+
+func parseTime(s string) time.Time {
+	for _, layout := range []string{"2006-01-02 15:04:05", time.RFC3339Nano} {
+		if t, e := time.ParseInLocation(layout, s, time.Local); e == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
@@ -0,0 +1,103 @@
+// Copyright 2020 The SQLFlow Authors. All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gc
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"sqlflow.org/sqlflow/go/database"
+	"sqlflow.org/sqlflow/go/sqlfs"
+	"sqlflow.org/sqlflow/go/test"
+)
+
+func TestTmpTableName(t *testing.T) {
+	a := assert.New(t)
+	name := TmpTableName("iris.predict")
+	a.True(strings.HasPrefix(name, "iris."+TmpTablePrefix))
+	a.True(strings.HasPrefix(TmpTableName("predict"), TmpTablePrefix))
+	a.WithinDuration(time.Now(), tmpTableTime(strings.TrimPrefix(name, "iris.")), 2*time.Second)
+	// temporary tables named before recording the creation time
+	a.True(tmpTableTime("sqlflow_tmp_abcdefgh12345678").IsZero())
+}
+
+func TestCollect(t *testing.T) {
+	if test.GetEnv("SQLFLOW_TEST_DB", "mysql") != "mysql" {
+		t.Skip("only test gc on MySQL")
+	}
+	a := assert.New(t)
+	db := database.GetTestingDBSingleton()
+	dbName := "sqlflow_gc_test"
+	exec := func(format string, args ...interface{}) {
+		_, e := db.Exec(fmt.Sprintf(format, args...))
+		a.NoError(e)
+	}
+	exec("DROP DATABASE IF EXISTS %s", dbName)
+	exec("CREATE DATABASE %s", dbName)
+	defer exec("DROP DATABASE IF EXISTS %s", dbName)
+
+	oldTmp := fmt.Sprintf("%s.%s%d_old", dbName, TmpTablePrefix, time.Now().Add(-48*time.Hour).Unix())
+	newTmp := TmpTableName(dbName + ".t")
+	for _, table := range []string{oldTmp, newTmp, dbName + ".my_model__v1", dbName + ".my_model__v2", dbName + ".old_model__v1", dbName + ".user_table", dbName + ".user_table__v2"} {
+		exec("CREATE TABLE %s (id INT)", table)
+	}
+	// model tables saved by the Go and the Python runtime
+	for table, header := range map[string]string{"orphan__v3": "SQLFLOW_MODEL\x01\x00", "py_orphan__v1": "0x00000002{}"} {
+		w, e := sqlfs.Create(db, dbName+"."+table, nil)
+		a.NoError(e)
+		_, e = w.Write([]byte(header))
+		a.NoError(e)
+		a.NoError(w.Close())
+	}
+	exec("CREATE TABLE %s.sqlflow_model_versions (model_name VARCHAR(255), version BIGINT, table_name VARCHAR(255), created_at DATETIME)", dbName)
+	old, now := time.Now().Add(-30*24*time.Hour).Format("2006-01-02 15:04:05"), time.Now().Format("2006-01-02 15:04:05")
+	exec("INSERT INTO %s.sqlflow_model_versions VALUES ('%[1]s.my_model', 1, '%[1]s.my_model__v1', '%[2]s'), ('%[1]s.my_model', 2, '%[1]s.my_model__v2', '%[3]s'), ('%[1]s.old_model', 1, '%[1]s.old_model__v1', '%[2]s')",
+		dbName, old, now)
+
+	policy := &Policy{TmpTableAge: 24 * time.Hour, ModelAge: 7 * 24 * time.Hour, DryRun: true}
+	garbage, e := Collect(db, dbName, policy)
+	a.NoError(e)
+	a.Equal(2, len(garbage))
+	a.Equal(dbName+".my_model__v1", garbage[0].Table)
+	a.Equal(ExpiredModel, garbage[0].Kind)
+	a.Equal(oldTmp, garbage[1].Table)
+	a.Equal(TmpTable, garbage[1].Kind)
+
+	// the orphaned model tables are just created, user_table__v2 isn't a
+	// model, and the latest version of old_model is kept
+	policy.TmpTableAge = 0
+	garbage, e = Collect(db, dbName, policy)
+	a.NoError(e)
+	a.Equal(5, len(garbage))
+	for _, g := range garbage {
+		a.NotContains([]string{dbName + ".user_table__v2", dbName + ".old_model__v1"}, g.Table)
+	}
+
+	policy.TmpTableAge, policy.DryRun = 24*time.Hour, false
+	_, e = Collect(db, dbName, policy)
+	a.NoError(e)
+	tables, e := listTables(db, dbName)
+	a.NoError(e)
+	names := []string{}
+	for _, t := range tables {
+		names = append(names, t.name)
+	}
+	a.ElementsMatch([]string{strings.TrimPrefix(newTmp, dbName+"."), "orphan__v3", "py_orphan__v1", "my_model__v2", "old_model__v1", "user_table", "user_table__v2", "sqlflow_model_versions"}, names)
+	garbage, e = Collect(db, dbName, &Policy{TmpTableAge: 24 * time.Hour, ModelAge: 7 * 24 * time.Hour, DryRun: true})
+	a.NoError(e)
+	a.Equal(0, len(garbage))
+}
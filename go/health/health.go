@@ -0,0 +1,189 @@
+// Copyright 2020 The SQLFlow Authors. All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package health reports the health of the SQLFlow gRPC servers by the gRPC
+// health checking protocol, and shuts them down gracefully.
+package health
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"google.golang.org/grpc"
+	grpchealth "google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"sqlflow.org/sqlflow/go/log"
+)
+
+const (
+	// DefaultInterval is the default interval between the checks.
+	DefaultInterval = 10 * time.Second
+	// checkTimeout bounds the time of each check.
+	checkTimeout = 5 * time.Second
+	// defaultGracePeriod is the default time for the running requests to
+	// finish after the server receives SIGTERM.
+	defaultGracePeriod = 20 * time.Second
+)
+
+// Check returns an error if a dependency of the server, e.g., the database,
+// is unavailable.
+type Check func(ctx context.Context) error
+
+type namedCheck struct {
+	name  string
+	check Check
+}
+
+// Checker runs the checks periodically, and reports SERVING by the gRPC
+// health service if all of them pass, or NOT_SERVING otherwise.
+type Checker struct {
+	server   *grpchealth.Server
+	services []string
+	mu       sync.Mutex
+	checks   []namedCheck
+	failures map[string]string // the last errors of the failed checks
+}
+
+// NewChecker returns a Checker reporting the status of the server, whose
+// name is "", and the gRPC services, e.g., proto.SQLFlow.
+func NewChecker(services ...string) *Checker {
+	c := &Checker{
+		server:   grpchealth.NewServer(),
+		services: services,
+		failures: map[string]string{},
+	}
+	c.setStatus(healthpb.HealthCheckResponse_NOT_SERVING)
+	return c
+}
+
+// AddCheck adds the check named name.
+func (c *Checker) AddCheck(name string, check Check) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.checks = append(c.checks, namedCheck{name, check})
+}
+
+// Register registers the gRPC health service to s.
+func (c *Checker) Register(s *grpc.Server) {
+	healthpb.RegisterHealthServer(s, c.server)
+}
+
+// Run runs the checks every interval until ctx is done.
+func (c *Checker) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		c.CheckOnce(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// CheckOnce runs the checks and updates the status. It returns the error of
+// the failed checks.
+func (c *Checker) CheckOnce(ctx context.Context) error {
+	c.mu.Lock()
+	checks := append([]namedCheck{}, c.checks...)
+	c.mu.Unlock()
+
+	failures := map[string]string{}
+	for _, nc := range checks {
+		ctx, cancel := context.WithTimeout(ctx, checkTimeout)
+		if e := nc.check(ctx); e != nil {
+			failures[nc.name] = e.Error()
+		}
+		cancel()
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for name, e := range failures {
+		if c.failures[name] != e {
+			log.GetDefaultLogger().Errorf("health check %s failed: %s", name, e)
+		}
+	}
+	for name := range c.failures {
+		if _, ok := failures[name]; !ok {
+			log.GetDefaultLogger().Infof("health check %s recovered", name)
+		}
+	}
+	c.failures = failures
+	if len(failures) > 0 {
+		c.setStatus(healthpb.HealthCheckResponse_NOT_SERVING)
+		return fmt.Errorf("health checks failed: %v", failures)
+	}
+	c.setStatus(healthpb.HealthCheckResponse_SERVING)
+	return nil
+}
+
+func (c *Checker) setStatus(status healthpb.HealthCheckResponse_ServingStatus) {
+	c.server.SetServingStatus("", status)
+	for _, s := range c.services {
+		c.server.SetServingStatus(s, status)
+	}
+}
+
+// Drain reports NOT_SERVING from now on, so that the load balancers stop
+// sending requests to the server before it shuts down.
+func (c *Checker) Drain() {
+	c.server.Shutdown()
+}
+
+// GracePeriodFromEnv returns the time for the running requests to finish
+// after the server receives SIGTERM, which is SQLFLOW_SHUTDOWN_GRACE_PERIOD,
+// e.g., 1m, or 20s by default.
+func GracePeriodFromEnv() (time.Duration, error) {
+	v := os.Getenv("SQLFLOW_SHUTDOWN_GRACE_PERIOD")
+	if v == "" {
+		return defaultGracePeriod, nil
+	}
+	d, e := time.ParseDuration(v)
+	if e != nil || d < 0 {
+		return 0, fmt.Errorf("invalid SQLFLOW_SHUTDOWN_GRACE_PERIOD %s, should be a duration like 30s", v)
+	}
+	return d, nil
+}
+
+// WaitForSignal blocks until the process receives SIGTERM or SIGINT, and
+// returns the signal.
+func WaitForSignal() os.Signal {
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, syscall.SIGTERM, syscall.SIGINT)
+	defer signal.Stop(ch)
+	return <-ch
+}
+
+// StopServer stops s gracefully, i.e., waits for the pending RPCs to finish,
+// and stops s forcibly if they don't finish in timeout.
+func StopServer(s *grpc.Server, timeout time.Duration) {
+	done := make(chan struct{})
+	go func() {
+		s.GracefulStop()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(timeout):
+		log.GetDefaultLogger().Errorf("the pending RPCs didn't finish in %v, stop the server forcibly", timeout)
+		s.Stop()
+		<-done
+	}
+}
@@ -0,0 +1,108 @@
+// Copyright 2020 The SQLFlow Authors. All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package health
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+func status(a *assert.Assertions, c *Checker, service string) healthpb.HealthCheckResponse_ServingStatus {
+	res, e := c.server.Check(context.Background(), &healthpb.HealthCheckRequest{Service: service})
+	a.NoError(e)
+	return res.Status
+}
+
+func TestChecker(t *testing.T) {
+	a := assert.New(t)
+	c := NewChecker("proto.SQLFlow")
+	a.Equal(healthpb.HealthCheckResponse_NOT_SERVING, status(a, c, ""))
+
+	var dbErr error
+	c.AddCheck("database", func(ctx context.Context) error { return dbErr })
+	c.AddCheck("parser", func(ctx context.Context) error { return nil })
+	a.NoError(c.CheckOnce(context.Background()))
+	a.Equal(healthpb.HealthCheckResponse_SERVING, status(a, c, ""))
+	a.Equal(healthpb.HealthCheckResponse_SERVING, status(a, c, "proto.SQLFlow"))
+
+	dbErr = fmt.Errorf("connection refused")
+	e := c.CheckOnce(context.Background())
+	a.Error(e)
+	a.Contains(e.Error(), "database")
+	a.Equal(healthpb.HealthCheckResponse_NOT_SERVING, status(a, c, "proto.SQLFlow"))
+
+	dbErr = nil
+	a.NoError(c.CheckOnce(context.Background()))
+	a.Equal(healthpb.HealthCheckResponse_SERVING, status(a, c, ""))
+
+	// the status is NOT_SERVING after draining even if the checks pass
+	c.Drain()
+	a.NoError(c.CheckOnce(context.Background()))
+	a.Equal(healthpb.HealthCheckResponse_NOT_SERVING, status(a, c, ""))
+}
+
+func TestGracePeriodFromEnv(t *testing.T) {
+	a := assert.New(t)
+	defer os.Unsetenv("SQLFLOW_SHUTDOWN_GRACE_PERIOD")
+	d, e := GracePeriodFromEnv()
+	a.NoError(e)
+	a.Equal(defaultGracePeriod, d)
+	os.Setenv("SQLFLOW_SHUTDOWN_GRACE_PERIOD", "1m")
+	d, e = GracePeriodFromEnv()
+	a.NoError(e)
+	a.Equal(time.Minute, d)
+	os.Setenv("SQLFLOW_SHUTDOWN_GRACE_PERIOD", "60")
+	_, e = GracePeriodFromEnv()
+	a.Error(e)
+}
+
+func TestStopServer(t *testing.T) {
+	a := assert.New(t)
+	lis, e := net.Listen("tcp", "127.0.0.1:0")
+	a.NoError(e)
+	s := grpc.NewServer()
+	c := NewChecker()
+	c.Register(s)
+	a.NoError(c.CheckOnce(context.Background()))
+	served := make(chan error, 1)
+	go func() { served <- s.Serve(lis) }()
+
+	conn, e := grpc.Dial(lis.Addr().String(), grpc.WithInsecure())
+	a.NoError(e)
+	defer conn.Close()
+	client := healthpb.NewHealthClient(conn)
+	res, e := client.Check(context.Background(), &healthpb.HealthCheckRequest{})
+	a.NoError(e)
+	a.Equal(healthpb.HealthCheckResponse_SERVING, res.Status)
+
+	// a pending Watch stream blocks GracefulStop until the timeout
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stream, e := client.Watch(ctx, &healthpb.HealthCheckRequest{})
+	a.NoError(e)
+	_, e = stream.Recv()
+	a.NoError(e)
+	start := time.Now()
+	StopServer(s, 100*time.Millisecond)
+	a.True(time.Since(start) >= 100*time.Millisecond)
+	a.NoError(<-served)
+}
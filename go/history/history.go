@@ -0,0 +1,177 @@
+// Copyright 2020 The SQLFlow Authors. All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package history records each executed statement, whether it succeeded or
+// failed, into the job history table, so that we could audit who trained
+// and used which model.
+package history
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"sqlflow.org/sqlflow/go/database"
+	"sqlflow.org/sqlflow/go/ir"
+)
+
+const (
+	// EnvTable is the environment variable to enable the job history by
+	// specifying the table, e.g. "sqlflow_meta.job_history".
+	EnvTable = "SQLFLOW_JOB_HISTORY_TABLE"
+
+	// StatusSucceeded is the status of a successfully executed statement.
+	StatusSucceeded = "SUCCEEDED"
+	// StatusFailed is the status of a failed statement.
+	StatusFailed = "FAILED"
+
+	// IRTypeWorkflow is the IR type of the record of a SQL program
+	// submitted as a workflow.
+	IRTypeWorkflow = "Workflow"
+)
+
+// Record is a row of the job history table.
+type Record struct {
+	UserID    string
+	Statement string
+	// IRType is the type of the IR of the statement, e.g. "TrainStmt", empty
+	// if the IR generation fails, or IRTypeWorkflow.
+	IRType       string
+	Submitter    string
+	StartTime    time.Time
+	EndTime      time.Time
+	Status       string
+	Error        string
+	OutputTables []string
+	ModelName    string
+}
+
+var columns = []string{"user_id", "statement", "ir_type", "submitter", "start_time",
+	"end_time", "status", "error", "output_tables", "model_name"}
+
+// created caches the tables already created by this process.
+var created sync.Map
+
+// TableFromEnv returns the job history table specified by
+// SQLFLOW_JOB_HISTORY_TABLE, or "" if the job history is disabled.
+func TableFromEnv() string {
+	return os.Getenv(EnvTable)
+}
+
+// NewRecord returns a record of the statement started at start. It fills
+// the IR type, the output tables and the model name from stmt, which could
+// be nil if the IR generation fails.
+func NewRecord(userID, submitter, statement string, stmt ir.SQLFlowStmt, start time.Time) *Record {
+	r := &Record{
+		UserID:    userID,
+		Statement: statement,
+		Submitter: submitter,
+		StartTime: start,
+	}
+	switch s := stmt.(type) {
+	case *ir.TrainStmt:
+		r.OutputTables, r.ModelName = []string{s.Into}, s.Into
+	case *ir.PredictStmt:
+		// ResultTable is empty if the prediction result is returned to the client.
+		if s.ResultTable != "" {
+			r.OutputTables = []string{s.ResultTable}
+		}
+		r.ModelName = s.Using
+	case *ir.ExplainStmt:
+		if s.Into != "" {
+			r.OutputTables = []string{s.Into}
+		}
+		r.ModelName = s.ModelName
+	case *ir.EvaluateStmt:
+		r.OutputTables, r.ModelName = []string{s.Into}, s.ModelName
+	case *ir.OptimizeStmt:
+		r.OutputTables = []string{s.ResultTable}
+	case *ir.RunStmt:
+		if s.Into != "" {
+			r.OutputTables = strings.Split(s.Into, ",")
+		}
+	case *ir.ShowTrainStmt:
+		r.ModelName = s.ModelName
+	case *ir.ImportModelStmt:
+		r.OutputTables, r.ModelName = []string{s.Into}, s.Into
+	}
+	if stmt != nil {
+		r.IRType = strings.TrimPrefix(fmt.Sprintf("%T", stmt), "*ir.")
+	}
+	return r
+}
+
+// Finish sets the end time and the status of the record by err.
+func (r *Record) Finish(err error) {
+	r.EndTime = time.Now()
+	r.Status = StatusSucceeded
+	if err != nil {
+		r.Status, r.Error = StatusFailed, err.Error()
+	}
+}
+
+// CreateTable creates the job history table if not exists.
+func CreateTable(db *database.DB, table string) error {
+	str, text, datetime, e := database.ColumnTypes(db.DriverName)
+	if e != nil {
+		return e
+	}
+	types := []string{str, text, str, str, datetime, datetime, str, text, text, str}
+	fields := []string{}
+	for i, c := range columns {
+		fields = append(fields, fmt.Sprintf("%s %s", c, types[i]))
+	}
+	stmt := fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (%s)", table, strings.Join(fields, ", "))
+	if _, e := db.Exec(stmt); e != nil {
+		return fmt.Errorf("failed executing %s: %v", stmt, e)
+	}
+	return nil
+}
+
+// Write inserts the record into the job history table, and creates the table
+// if this process hasn't done it.
+func Write(db *database.DB, table string, r *Record) error {
+	key := db.DataSourceName + "/" + table
+	if _, ok := created.Load(key); !ok {
+		if e := CreateTable(db, table); e != nil {
+			return e
+		}
+		created.Store(key, true)
+	}
+	quote := database.QuoteString
+	timeValue := func(t time.Time) string { return database.TimeLiteral(db.DriverName, t) }
+	values := []string{
+		quote(r.UserID),
+		quote(r.Statement),
+		quote(r.IRType),
+		quote(r.Submitter),
+		timeValue(r.StartTime),
+		timeValue(r.EndTime),
+		quote(r.Status),
+		quote(r.Error),
+		quote(strings.Join(r.OutputTables, ",")),
+		quote(r.ModelName),
+	}
+	var stmt string
+	if db.DriverName == "mysql" {
+		stmt = fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)", table, strings.Join(columns, ","), strings.Join(values, ","))
+	} else {
+		stmt = fmt.Sprintf("INSERT INTO TABLE %s SELECT %s", table, strings.Join(values, ","))
+	}
+	if _, e := db.Exec(stmt); e != nil {
+		return fmt.Errorf("failed writing job history: %v", e)
+	}
+	return nil
+}
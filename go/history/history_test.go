@@ -0,0 +1,66 @@
+// Copyright 2020 The SQLFlow Authors. All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package history
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"sqlflow.org/sqlflow/go/database"
+	"sqlflow.org/sqlflow/go/ir"
+	"sqlflow.org/sqlflow/go/test"
+)
+
+func TestNewRecord(t *testing.T) {
+	a := assert.New(t)
+	r := NewRecord("alice", "default", "SELECT ...", &ir.PredictStmt{ResultTable: "iris.predict", Using: "sqlflow_models.my_dnn_model"}, time.Now())
+	a.Equal("PredictStmt", r.IRType)
+	a.Equal([]string{"iris.predict"}, r.OutputTables)
+	a.Equal("sqlflow_models.my_dnn_model", r.ModelName)
+
+	r = NewRecord("alice", "default", "SELECT ...", &ir.RunStmt{Into: "t1,t2"}, time.Now())
+	a.Equal([]string{"t1", "t2"}, r.OutputTables)
+
+	r = NewRecord("alice", "default", "SELECT ...", nil, time.Now())
+	a.Equal("", r.IRType)
+	r.Finish(fmt.Errorf("some error"))
+	a.Equal(StatusFailed, r.Status)
+	a.Equal("some error", r.Error)
+}
+
+func TestWrite(t *testing.T) {
+	if test.GetEnv("SQLFLOW_TEST_DB", "mysql") != "mysql" {
+		t.Skip("only test job history on MySQL")
+	}
+	a := assert.New(t)
+	db := database.GetTestingDBSingleton()
+	table := "iris.job_history_test"
+	_, e := db.Exec(fmt.Sprintf("DROP TABLE IF EXISTS %s", table))
+	a.NoError(e)
+	created.Delete(db.DataSourceName + "/" + table)
+
+	r := NewRecord("alice", "default", "SELECT * FROM iris.train TO TRAIN DNNClassifier ... INTO my_model;",
+		&ir.TrainStmt{Into: "sqlflow_models.my_model"}, time.Now())
+	r.Finish(nil)
+	a.NoError(Write(db, table, r))
+
+	var user, irType, status, model string
+	a.NoError(db.QueryRow(fmt.Sprintf("SELECT user_id, ir_type, status, model_name FROM %s", table)).Scan(&user, &irType, &status, &model))
+	a.Equal("alice", user)
+	a.Equal("TrainStmt", irType)
+	a.Equal(StatusSucceeded, status)
+	a.Equal("sqlflow_models.my_model", model)
+}
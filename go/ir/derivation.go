@@ -14,6 +14,7 @@
 package ir
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"reflect"
@@ -24,6 +25,7 @@ import (
 
 	"sqlflow.org/sqlflow/go/database"
 	"sqlflow.org/sqlflow/go/pipe"
+	"sqlflow.org/sqlflow/go/tracing"
 	"sqlflow.org/sqlflow/go/verifier"
 )
 
@@ -406,7 +408,11 @@ func fillFieldDesc(columnTypeList []*sql.ColumnType, rowdata []interface{}, fiel
 // InferFeatureColumns fill up featureColumn and columnSpec structs
 // for all fields.
 // if wr is not nil, then write
-func InferFeatureColumns(trainStmt *TrainStmt, db *database.DB) error {
+func InferFeatureColumns(ctx context.Context, trainStmt *TrainStmt, db *database.DB) (err error) {
+	_, span := tracing.Start(ctx, "ir.InferFeatureColumns")
+	span.SetAttribute("driver", db.DriverName)
+	defer func() { span.End(err) }()
+
 	fcMap := makeColumnMap(trainStmt.Features)
 	fmMap := makeFieldDescMap(trainStmt.Features)
 
@@ -14,6 +14,7 @@
 package ir
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"testing"
@@ -249,7 +250,7 @@ func TestFeatureDerivation(t *testing.T) {
 	}
 
 	trainStmt := mockTrainStmtNormal()
-	e := InferFeatureColumns(trainStmt, db)
+	e := InferFeatureColumns(context.Background(), trainStmt, db)
 	a.NoError(e)
 
 	fc1 := trainStmt.Features["feature_columns"][0]
@@ -310,7 +311,7 @@ func TestFeatureDerivation(t *testing.T) {
 	a.Equal(6, len(trainStmt.Features["feature_columns"]))
 
 	trainStmt = mockTrainStmtCross()
-	e = InferFeatureColumns(trainStmt, db)
+	e = InferFeatureColumns(context.Background(), trainStmt, db)
 	a.NoError(e)
 
 	a.Equal(5, len(trainStmt.Features["feature_columns"]))
@@ -370,7 +371,7 @@ func TestFeatureDerivationNoColumnClause(t *testing.T) {
 	}
 
 	trainStmt := mockTrainStmtIrisNoColumnClause()
-	e := InferFeatureColumns(trainStmt, db)
+	e := InferFeatureColumns(context.Background(), trainStmt, db)
 	a.NoError(e)
 
 	a.Equal(4, len(trainStmt.Features["feature_columns"]))
@@ -391,7 +392,7 @@ func TestHiveFeatureDerivation(t *testing.T) {
 		Attributes:       map[string]interface{}{},
 		Features:         map[string][]FeatureColumn{},
 		Label:            &NumericColumn{&FieldDesc{"class", Int, Int, "", "", "", []int{1}, false, nil, 0}}}
-	e := InferFeatureColumns(trainStmt, database.GetTestingDBSingleton())
+	e := InferFeatureColumns(context.Background(), trainStmt, database.GetTestingDBSingleton())
 	a.NoError(e)
 	a.Equal(4, len(trainStmt.Features["feature_columns"]))
 }
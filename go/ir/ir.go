@@ -63,6 +63,11 @@ type TrainStmt struct {
 	// see: pai_submitter.go
 	TmpTrainTable    string
 	TmpValidateTable string
+	// Inputs are the tables that Select reads, resolved by the parser.
+	Inputs []string
+	// Imported tells if the model is imported by IMPORT MODEL instead of
+	// trained by SQLFlow.
+	Imported bool
 }
 
 const (
@@ -95,6 +100,10 @@ func (stmt *TrainStmt) IsExtended() bool { return true }
 // GetOriginalSQL returns the original SQL statement used to get current IR result
 func (stmt *TrainStmt) GetOriginalSQL() string { return stmt.OriginalSQL }
 
+// DefaultPredictResultColumn is the column to store the prediction result when
+// the INTO clause is omitted and the label of the model is unknown.
+const DefaultPredictResultColumn = "prediction"
+
 // PredictStmt is the intermediate representation for code generation of a prediction job
 //
 // Please be aware the PredictStmt IR contains the result table name, so the
@@ -105,7 +114,9 @@ type PredictStmt struct {
 	OriginalSQL string
 	// Select specifies the query for fetching the prediction data. For example, "select * from iris.test;".
 	Select string
-	// ResultTable specifies the table to store the prediction result.
+	// ResultTable specifies the table to store the prediction result. It is
+	// empty if the INTO clause is omitted, in which case the prediction result
+	// is returned to the client.
 	ResultTable string
 	// ResultColumn is the column to store predict result in ResultTable
 	ResultColumn string
@@ -120,6 +131,11 @@ type PredictStmt struct {
 	// When SQLFLOW_submitter == "pai", tmp tables will be created for predicting task
 	// see: pai_submitter.go
 	TmpPredictTable string
+	// OutputTable is the table to accumulate the prediction result when predict.mode
+	// is "append" or "partition". In such cases, the executor points ResultTable to a
+	// staging table and moves the rows into OutputTable after the prediction job.
+	// see: executor/pre_exec.go
+	OutputTable string
 }
 
 // SetOriginalSQL sets the original sql string
@@ -174,6 +190,9 @@ type EvaluateStmt struct {
 	Into             string
 	TmpEvaluateTable string
 	TrainStmt        *TrainStmt
+	// OutputTable is the evaluation result table when Into points to the staging
+	// table of the metrics, see executor.createEvaluationResultTable.
+	OutputTable string
 }
 
 // SetOriginalSQL sets the original sql string
@@ -214,6 +233,32 @@ func (stmt *ShowTrainStmt) IsExtended() bool { return true }
 // GetOriginalSQL returns the original SQL statement used to get current IR result
 func (stmt *ShowTrainStmt) GetOriginalSQL() string { return stmt.OriginalSQL }
 
+// ImportModelStmt imports an externally trained model, e.g. an XGBoost model
+// file or a TensorFlow SavedModel, as if it were trained by SQLFlow.
+type ImportModelStmt struct {
+	// OriginalSQL is the IMPORT MODEL stmt itself
+	OriginalSQL string
+	// ModelPath is the model to import, e.g. file:///path/model.json
+	ModelPath string
+	// Into is where the imported model is saved.
+	Into string
+	// TrainStmt describes the imported model, its Select is empty since the
+	// model was not trained by SQLFlow.
+	TrainStmt *TrainStmt
+}
+
+// SetOriginalSQL sets the original sql string
+func (stmt *ImportModelStmt) SetOriginalSQL(sql string) {
+	stmt.OriginalSQL = sql
+	stmt.TrainStmt.OriginalSQL = sql
+}
+
+// IsExtended returns whether a SQLFlowStmt is an extended SQL statement
+func (stmt *ImportModelStmt) IsExtended() bool { return true }
+
+// GetOriginalSQL returns the original SQL statement used to get current IR result
+func (stmt *ImportModelStmt) GetOriginalSQL() string { return stmt.OriginalSQL }
+
 // OptimizeExpr is the intermediate code for generating target solver expressions.
 type OptimizeExpr struct {
 	// Objective expression or constraint expression string tokens prepared for generate target code.
@@ -14,10 +14,12 @@
 package ir
 
 import (
+	"context"
 	"fmt"
 	"strconv"
 	"strings"
 
+	"sqlflow.org/sqlflow/go/attribute"
 	"sqlflow.org/sqlflow/go/database"
 	"sqlflow.org/sqlflow/go/model"
 	"sqlflow.org/sqlflow/go/parser"
@@ -42,7 +44,7 @@ const (
 )
 
 // GenerateTrainStmtWithInferredColumns generates a `TrainStmt` with inferred feature columns
-func GenerateTrainStmtWithInferredColumns(slct *parser.SQLFlowSelectStmt, connStr string, cwd string, loadPreTrainedModel bool, verifyLabel bool) (*TrainStmt, error) {
+func GenerateTrainStmtWithInferredColumns(ctx context.Context, slct *parser.SQLFlowSelectStmt, connStr string, cwd string, loadPreTrainedModel bool, verifyLabel bool) (*TrainStmt, error) {
 	trainStmt, err := GenerateTrainStmt(slct)
 	if err != nil {
 		return nil, err
@@ -54,7 +56,7 @@ func GenerateTrainStmtWithInferredColumns(slct *parser.SQLFlowSelectStmt, connSt
 	}
 	defer db.Close()
 
-	if err := InferFeatureColumns(trainStmt, db); err != nil {
+	if err := InferFeatureColumns(ctx, trainStmt, db); err != nil {
 		return nil, err
 	}
 
@@ -64,7 +66,7 @@ func GenerateTrainStmtWithInferredColumns(slct *parser.SQLFlowSelectStmt, connSt
 	}
 
 	if loadPreTrainedModel && slct.TrainUsing != "" {
-		_, _, err = loadModelMeta(slct, db, cwd, slct.TrainUsing)
+		_, _, err = loadModelMeta(ctx, slct, db, cwd, slct.TrainUsing)
 		if err != nil {
 			return nil, err
 		}
@@ -135,8 +137,8 @@ func GenerateTrainStmt(slct *parser.SQLFlowSelectStmt) (*TrainStmt, error) {
 	return trainStmt, nil
 }
 
-func loadModelMeta(pr *parser.SQLFlowSelectStmt, db *database.DB, cwd, modelName string) (*parser.SQLFlowSelectStmt, *parser.SQLFlowSelectStmt, error) {
-	m, e := model.Load(modelName, cwd, db)
+func loadModelMeta(ctx context.Context, pr *parser.SQLFlowSelectStmt, db *database.DB, cwd, modelName string) (*parser.SQLFlowSelectStmt, *parser.SQLFlowSelectStmt, error) {
+	m, e := model.LoadContext(ctx, modelName, cwd, db)
 	if e != nil {
 		return nil, nil, fmt.Errorf("load %v", e)
 	}
@@ -147,6 +149,21 @@ func loadModelMeta(pr *parser.SQLFlowSelectStmt, db *database.DB, cwd, modelName
 		return nil, nil, fmt.Errorf("parse: TrainSelect %v raise %v", m.TrainSelect, e)
 	}
 
+	if tr.ImportModel {
+		tc, e := importedTrainClause(&tr.ImportModelClause)
+		if e != nil {
+			return nil, nil, e
+		}
+		// NOTE: an imported model has no training data, use the data of pr instead.
+		return pr, &parser.SQLFlowSelectStmt{
+			Extended:       true,
+			Train:          true,
+			ImportModel:    true,
+			StandardSelect: pr.StandardSelect,
+			TrainClause:    tc,
+		}, nil
+	}
+
 	if e := verifier.VerifyColumnNameAndType(tr.SQLFlowSelectStmt, pr, db); e != nil {
 		return nil, nil, fmt.Errorf("VerifyColumnNameAndType: %v", e)
 	}
@@ -155,20 +172,23 @@ func loadModelMeta(pr *parser.SQLFlowSelectStmt, db *database.DB, cwd, modelName
 }
 
 // GenerateTrainStmtByModel generates a `TrainStmt` from a trained model
-func GenerateTrainStmtByModel(slct *parser.SQLFlowSelectStmt, connStr, cwd, model string) (*TrainStmt, error) {
+func GenerateTrainStmtByModel(ctx context.Context, slct *parser.SQLFlowSelectStmt, connStr, cwd, model string) (*TrainStmt, error) {
 	db, err := database.OpenDB(connStr)
 	if err != nil {
 		return nil, err
 	}
 	defer db.Close()
 
-	_, trainSlct, err := loadModelMeta(slct, db, cwd, model)
+	_, trainSlct, err := loadModelMeta(ctx, slct, db, cwd, model)
 	if err != nil {
 		return nil, err
 	}
 
 	slct.TrainClause = trainSlct.TrainClause
-	return GenerateTrainStmtWithInferredColumns(trainSlct, connStr, "", false, false)
+	if trainSlct.ImportModel {
+		return generateImportedTrainStmt(ctx, trainSlct, connStr)
+	}
+	return GenerateTrainStmtWithInferredColumns(ctx, trainSlct, connStr, "", false, false)
 }
 
 func verifyTrainStmt(trainStmt *TrainStmt, db *database.DB, verifyLabel bool) error {
@@ -252,7 +272,7 @@ func verifyIRWithTrainStmt(sqlir SQLFlowStmt, db *database.DB) error {
 }
 
 // GeneratePredictStmt generates a `PredictStmt` from the parsed result `slct`
-func GeneratePredictStmt(slct *parser.SQLFlowSelectStmt, connStr string, cwd string, getTrainStmtFromModel bool) (*PredictStmt, error) {
+func GeneratePredictStmt(ctx context.Context, slct *parser.SQLFlowSelectStmt, connStr string, cwd string, getTrainStmtFromModel bool) (*PredictStmt, error) {
 	attrMap, err := generateAttributeIR(&slct.PredAttrs)
 	if err != nil {
 		return nil, err
@@ -260,15 +280,26 @@ func GeneratePredictStmt(slct *parser.SQLFlowSelectStmt, connStr string, cwd str
 
 	var trainStmt *TrainStmt
 	if getTrainStmtFromModel {
-		trainStmt, err = GenerateTrainStmtByModel(slct, connStr, cwd, slct.Model)
+		trainStmt, err = GenerateTrainStmtByModel(ctx, slct, connStr, cwd, slct.Model)
 		if err != nil {
 			return nil, err
 		}
 	}
 
-	resultTable, resultCol, err := parseResultTable(slct.Into)
-	if err != nil {
-		return nil, err
+	// NOTE: without the INTO clause, the executor writes the prediction
+	// result into a temporary table and returns the rows to the client.
+	resultTable, resultCol := "", DefaultPredictResultColumn
+	if trainStmt != nil && trainStmt.Label != nil {
+		// label-less models, e.g. the clustering models, have an empty label
+		if fds := trainStmt.Label.GetFieldDesc(); len(fds) > 0 && fds[0].Name != "" {
+			resultCol = fds[0].Name
+		}
+	}
+	if slct.Into != "" {
+		resultTable, resultCol, err = parseResultTable(slct.Into)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	predStmt := &PredictStmt{
@@ -296,7 +327,7 @@ func GeneratePredictStmt(slct *parser.SQLFlowSelectStmt, connStr string, cwd str
 }
 
 // GenerateExplainStmt generates a `ExplainStmt` from the parsed result `slct`
-func GenerateExplainStmt(slct *parser.SQLFlowSelectStmt, connStr, cwd string, getTrainStmtFromModel bool) (*ExplainStmt, error) {
+func GenerateExplainStmt(ctx context.Context, slct *parser.SQLFlowSelectStmt, connStr, cwd string, getTrainStmtFromModel bool) (*ExplainStmt, error) {
 	attrs, err := generateAttributeIR(&slct.ExplainAttrs)
 	if err != nil {
 		return nil, err
@@ -304,10 +335,13 @@ func GenerateExplainStmt(slct *parser.SQLFlowSelectStmt, connStr, cwd string, ge
 
 	var trainStmt *TrainStmt
 	if getTrainStmtFromModel {
-		trainStmt, err = GenerateTrainStmtByModel(slct, connStr, cwd, slct.TrainedModel)
+		trainStmt, err = GenerateTrainStmtByModel(ctx, slct, connStr, cwd, slct.TrainedModel)
 		if err != nil {
 			return nil, err
 		}
+		if trainStmt.Imported {
+			return nil, fmt.Errorf("TO EXPLAIN doesn't support the imported model %s", slct.TrainedModel)
+		}
 	}
 
 	explainStmt := &ExplainStmt{
@@ -335,7 +369,7 @@ func GenerateExplainStmt(slct *parser.SQLFlowSelectStmt, connStr, cwd string, ge
 }
 
 // GenerateEvaluateStmt generates a `EvaluateStmt` from the parsed result `slct`
-func GenerateEvaluateStmt(slct *parser.SQLFlowSelectStmt, connStr string, cwd string, getTrainStmtFromModel bool) (*EvaluateStmt, error) {
+func GenerateEvaluateStmt(ctx context.Context, slct *parser.SQLFlowSelectStmt, connStr string, cwd string, getTrainStmtFromModel bool) (*EvaluateStmt, error) {
 	attrMap, err := generateAttributeIR(&slct.EvaluateAttrs)
 	if err != nil {
 		return nil, err
@@ -343,7 +377,7 @@ func GenerateEvaluateStmt(slct *parser.SQLFlowSelectStmt, connStr string, cwd st
 
 	var trainStmt *TrainStmt
 	if getTrainStmtFromModel {
-		trainStmt, err = GenerateTrainStmtByModel(slct, connStr, cwd, slct.ModelToEvaluate)
+		trainStmt, err = GenerateTrainStmtByModel(ctx, slct, connStr, cwd, slct.ModelToEvaluate)
 		if err != nil {
 			return nil, err
 		}
@@ -1042,6 +1076,92 @@ func GenerateShowTrainStmt(showTrain *parser.SQLFlowSelectStmt) (*ShowTrainStmt,
 	}, nil
 }
 
+// GenerateImportModelStmt generates an `ImportModelStmt` from the parsed result `slct`
+func GenerateImportModelStmt(slct *parser.SQLFlowSelectStmt) (*ImportModelStmt, error) {
+	tc, err := importedTrainClause(&slct.ImportModelClause)
+	if err != nil {
+		return nil, err
+	}
+	trainStmt, err := GenerateTrainStmt(&parser.SQLFlowSelectStmt{TrainClause: tc})
+	if err != nil {
+		return nil, err
+	}
+	// NOTE: the Python runtime predicts by a Keras model by loading its
+	// weights instead of a SavedModel.
+	if _, ok := attribute.PremadeModelParamsDocs[trainStmt.Estimator]; trainStmt.GetModelKind() == TensorFlow && !ok {
+		return nil, fmt.Errorf("IMPORT MODEL supports XGBoost models and SavedModels of TensorFlow pre-made estimators only, got estimator %s", trainStmt.Estimator)
+	}
+	trainStmt.Imported = true
+	return &ImportModelStmt{
+		ModelPath: slct.ModelPath,
+		Into:      slct.ImportInto,
+		TrainStmt: trainStmt,
+	}, nil
+}
+
+// importedTrainClause describes a model imported by `IMPORT MODEL ... WITH
+// estimator="xgboost.gbtree", label="class", columns=[c1, c2], ...` as if it
+// were trained by `TO TRAIN xgboost.gbtree WITH ... COLUMN c1, c2 LABEL class`.
+func importedTrainClause(ic *parser.ImportModelClause) (parser.TrainClause, error) {
+	tc := parser.TrainClause{TrainAttrs: parser.Attributes{}, Save: ic.ImportInto}
+	for k, v := range ic.ImportAttrs {
+		switch k {
+		case "estimator", "label":
+			s, ok := inferStringValue(v.Value).(string)
+			if v.Type == 0 || !ok {
+				return tc, fmt.Errorf("IMPORT MODEL expects the attribute %s to be a string, got %s", k, v)
+			}
+			if k == "estimator" {
+				tc.Estimator = s
+			} else {
+				tc.Label = s
+			}
+		case "columns":
+			if v.Type != 0 || len(v.Sexp) < 2 || v.Sexp[0].Type != '[' {
+				return tc, fmt.Errorf("IMPORT MODEL expects the attribute columns to be a list like [c1, c2], got %s", v)
+			}
+			columns := parser.ExprList{}
+			for _, c := range v.Sexp[1:] {
+				if c.Type == parser.STRING {
+					// columns=["c1", "c2"] is the same as columns=[c1, c2]
+					c = &parser.Expr{Type: parser.IDENT, Value: c.Value[1 : len(c.Value)-1]}
+				}
+				columns = append(columns, c)
+			}
+			tc.Columns = map[string]parser.ExprList{"feature_columns": columns}
+		default:
+			tc.TrainAttrs[k] = v
+		}
+	}
+	if tc.Estimator == "" {
+		return tc, fmt.Errorf("IMPORT MODEL requires the attribute estimator")
+	}
+	if len(tc.Columns) == 0 {
+		return tc, fmt.Errorf("IMPORT MODEL requires the attribute columns")
+	}
+	return tc, nil
+}
+
+// generateImportedTrainStmt generates the `TrainStmt` of an imported model.
+// The imported model has no training data, so its feature columns are derived
+// from slct.StandardSelect, the data to predict, which may not contain the label.
+func generateImportedTrainStmt(ctx context.Context, slct *parser.SQLFlowSelectStmt, connStr string) (*TrainStmt, error) {
+	label := slct.Label
+	slct.Label = ""
+	trainStmt, err := GenerateTrainStmtWithInferredColumns(ctx, slct, connStr, "", false, false)
+	if err != nil {
+		return nil, err
+	}
+	trainStmt.Label = &NumericColumn{
+		FieldDesc: &FieldDesc{
+			Name:  label,
+			Shape: []int{},
+			DType: Float,
+		}}
+	trainStmt.Imported = true
+	return trainStmt, nil
+}
+
 func getOptimizeVariablesAndResultValueName(optimizeStmt *parser.SQLFlowSelectStmt) ([]string, string, error) {
 	varsExpr, ok := optimizeStmt.OptimizeAttrs[variables]
 	if !ok {
@@ -14,6 +14,7 @@
 package ir
 
 import (
+	"context"
 	"fmt"
 	"io/ioutil"
 	"os"
@@ -427,7 +428,7 @@ WITH model.n_classes=3, model.hidden_units=[10,20]
 LABEL class
 INTO sqlflow_models.mymodel;`, "sqlflow_models.mymodel"))
 
-	predStmt, err := GeneratePredictStmt(r.SQLFlowSelectStmt, database.GetTestingDBSingleton().URL(), cwd, true)
+	predStmt, err := GeneratePredictStmt(context.Background(), r.SQLFlowSelectStmt, database.GetTestingDBSingleton().URL(), cwd, true)
 	a.NoError(err)
 
 	a.Equal("iris.predict", predStmt.ResultTable)
@@ -439,6 +440,79 @@ INTO sqlflow_models.mymodel;`, "sqlflow_models.mymodel"))
 	a.Equal("sqlflow_models.mymodel", predStmt.Using)
 }
 
+func TestGenerateImportModelStmt(t *testing.T) {
+	a := assert.New(t)
+	importSQL := `IMPORT MODEL 'file:///tmp/model.json' AS sqlflow_models.my_xgb_model
+WITH estimator="xgboost.gbtree", objective="multi:softprob", num_class=3,
+	label="class", columns=[sepal_length, "sepal_width", DENSE(petal_length, 1)];`
+	r, e := parser.ParseStatement("mysql", importSQL)
+	a.NoError(e)
+	importStmt, e := GenerateImportModelStmt(r.SQLFlowSelectStmt)
+	a.NoError(e)
+	a.Equal("file:///tmp/model.json", importStmt.ModelPath)
+	a.Equal("sqlflow_models.my_xgb_model", importStmt.Into)
+	a.Equal("sqlflow_models.my_xgb_model", importStmt.TrainStmt.Into)
+	a.Equal("xgboost.gbtree", importStmt.TrainStmt.Estimator)
+	a.Equal(XGBoost, importStmt.TrainStmt.GetModelKind())
+	a.Equal(map[string]interface{}{"objective": "multi:softprob", "num_class": 3}, importStmt.TrainStmt.Attributes)
+	a.Equal("class", importStmt.TrainStmt.Label.GetFieldDesc()[0].Name)
+	fcs := importStmt.TrainStmt.Features["feature_columns"]
+	a.Equal(3, len(fcs))
+	a.Equal("sepal_width", fcs[1].GetFieldDesc()[0].Name)
+	a.Equal("petal_length", fcs[2].GetFieldDesc()[0].Name)
+	a.True(importStmt.TrainStmt.Imported)
+
+	r, e = parser.ParseStatement("mysql", `IMPORT MODEL 'file:///tmp/saved_model' AS my_model WITH estimator="DNNClassifier", label="class", columns=[c1];`)
+	a.NoError(e)
+	importStmt, e = GenerateImportModelStmt(r.SQLFlowSelectStmt)
+	a.NoError(e)
+	a.Equal(TensorFlow, importStmt.TrainStmt.GetModelKind())
+
+	for _, sql := range []string{
+		`IMPORT MODEL 'file:///tmp/model.json' AS my_model WITH label="class", columns=[c1];`,
+		`IMPORT MODEL 'file:///tmp/saved_model' AS my_model WITH estimator="sqlflow_models.DNNClassifier", label="class", columns=[c1];`,
+		`IMPORT MODEL 'file:///tmp/model.json' AS my_model WITH estimator="xgboost.gbtree", label="class";`,
+		`IMPORT MODEL 'file:///tmp/model.json' AS my_model WITH estimator="xgboost.gbtree", columns=c1;`,
+	} {
+		r, e := parser.ParseStatement("mysql", sql)
+		a.NoError(e)
+		_, e = GenerateImportModelStmt(r.SQLFlowSelectStmt)
+		a.Error(e)
+	}
+}
+
+func TestGeneratePredictStmtWithImportedModel(t *testing.T) {
+	if test.GetEnv("SQLFLOW_TEST_DB", "mysql") == "hive" {
+		t.Skip(fmt.Sprintf("%s: skip Hive test", test.GetEnv("SQLFLOW_TEST_DB", "mysql")))
+	}
+	a := assert.New(t)
+	cwd, e := ioutil.TempDir("/tmp", "sqlflow_models")
+	a.Nil(e)
+	defer os.RemoveAll(cwd)
+	a.NoError(model.MockInDB(cwd, `IMPORT MODEL 'file:///tmp/model.json' AS sqlflow_models.my_imported_model
+WITH estimator="xgboost.gbtree", objective="multi:softprob", label="class", columns=[sepal_length, sepal_width];`, "sqlflow_models.my_imported_model"))
+
+	// the data to predict does not contain the label
+	r, e := parser.ParseStatement("mysql", `SELECT sepal_length, sepal_width FROM iris.test
+TO PREDICT iris.predict.class
+USING sqlflow_models.my_imported_model;`)
+	a.NoError(e)
+	predStmt, e := GeneratePredictStmt(context.Background(), r.SQLFlowSelectStmt, database.GetTestingDBSingleton().URL(), cwd, true)
+	a.NoError(e)
+	a.Equal("xgboost.gbtree", predStmt.TrainStmt.Estimator)
+	a.Equal("class", predStmt.TrainStmt.Label.GetFieldDesc()[0].Name)
+	a.Equal(2, len(predStmt.TrainStmt.Features["feature_columns"]))
+	a.Equal("multi:softprob", predStmt.TrainStmt.Attributes["objective"])
+	a.True(predStmt.TrainStmt.Imported)
+
+	r, e = parser.ParseStatement("mysql", `SELECT sepal_length, sepal_width FROM iris.test
+TO EXPLAIN sqlflow_models.my_imported_model;`)
+	a.NoError(e)
+	_, e = GenerateExplainStmt(context.Background(), r.SQLFlowSelectStmt, database.GetTestingDBSingleton().URL(), cwd, true)
+	a.Error(e)
+	a.Contains(e.Error(), "imported model")
+}
+
 func TestGenerateExplainStmt(t *testing.T) {
 	if test.GetEnv("SQLFLOW_TEST_DB", "mysql") != "mysql" {
 		t.Skip(fmt.Sprintf("%s: skip test", test.GetEnv("SQLFLOW_TEST_DB", "mysql")))
@@ -472,7 +546,7 @@ INTO sqlflow_models.my_xgboost_model;`, "sqlflow_models.my_xgboost_model"))
 	`)
 	a.NoError(e)
 
-	ExplainStmt, e := GenerateExplainStmt(pr.SQLFlowSelectStmt, connStr, cwd, true)
+	ExplainStmt, e := GenerateExplainStmt(context.Background(), pr.SQLFlowSelectStmt, connStr, cwd, true)
 	a.NoError(e)
 	a.Equal(ExplainStmt.Explainer, "TreeExplainer")
 	a.Equal(len(ExplainStmt.Attributes), 3)
@@ -497,7 +571,7 @@ INTO sqlflow_models.my_xgboost_model;`, "sqlflow_models.my_xgboost_model"))
 	`)
 	a.NoError(e)
 
-	ExplainIntoStmt, e := GenerateExplainStmt(pr.SQLFlowSelectStmt, connStr, cwd, true)
+	ExplainIntoStmt, e := GenerateExplainStmt(context.Background(), pr.SQLFlowSelectStmt, connStr, cwd, true)
 	a.NoError(e)
 	a.Equal(ExplainIntoStmt.Explainer, "TreeExplainer")
 	a.Equal(len(ExplainIntoStmt.Attributes), 3)
@@ -505,7 +579,7 @@ INTO sqlflow_models.my_xgboost_model;`, "sqlflow_models.my_xgboost_model"))
 
 	pr, e = parser.ParseStatement("mysql", `SELECT * FROM iris.train TO EXPLAIN sqlflow_models.my_xgboost_model;`)
 	a.NoError(e)
-	shortExplainStmt, e := GenerateExplainStmt(pr.SQLFlowSelectStmt, connStr, cwd, true)
+	shortExplainStmt, e := GenerateExplainStmt(context.Background(), pr.SQLFlowSelectStmt, connStr, cwd, true)
 	a.NoError(e)
 	a.Equal(shortExplainStmt.Explainer, "")
 	a.Equal(len(shortExplainStmt.Attributes), 0)
@@ -0,0 +1,141 @@
+// Copyright 2020 The SQLFlow Authors. All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package metrics defines the Prometheus metrics of the SQLFlow server.
+package metrics
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// The values of the label status.
+const (
+	StatusSucceeded = "succeeded"
+	StatusFailed    = "failed"
+)
+
+var (
+	// Registry is the registry of the SQLFlow metrics, and the Go runtime
+	// and process metrics.
+	Registry = prometheus.NewRegistry()
+
+	statements = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "sqlflow_statements_total",
+		Help: "The number of the executed statements by the IR type and the status.",
+	}, []string{"type", "status"})
+	statementDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name: "sqlflow_statement_duration_seconds",
+		Help: "The execution time of the statements by the IR type.",
+		// from 100ms to about 7 hours for the training statements
+		Buckets: prometheus.ExponentialBuckets(0.1, 4, 10),
+	}, []string{"type"})
+
+	// ActiveJobs is the number of the running requests.
+	ActiveJobs = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "sqlflow_active_jobs",
+		Help: "The number of the running requests.",
+	})
+	// QueuedJobs is the number of the requests waiting in the queue.
+	QueuedJobs = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "sqlflow_queued_jobs",
+		Help: "The number of the requests waiting in the queue.",
+	})
+	// SubprocessFailures counts the failed subprocesses by the program name,
+	// e.g., python.
+	SubprocessFailures = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "sqlflow_subprocess_failures_total",
+		Help: "The number of the failed subprocesses by the program.",
+	}, []string{"program"})
+	// ParseErrors counts the SQL programs failed to parse by the dialect.
+	ParseErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "sqlflow_parse_errors_total",
+		Help: "The number of the SQL programs failed to parse by the dialect.",
+	}, []string{"dialect"})
+
+	argoDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name: "sqlflow_argo_request_duration_seconds",
+		Help: "The latency of the requests to Argo by the operation and the status.",
+	}, []string{"operation", "status"})
+
+	modelDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "sqlflow_model_duration_seconds",
+		Help:    "The time to save or load a model by the operation, the storage and the status.",
+		Buckets: prometheus.ExponentialBuckets(0.01, 4, 10),
+	}, []string{"operation", "storage", "status"})
+	modelBytes = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "sqlflow_model_bytes_total",
+		Help: "The size of the saved model tarballs by the storage.",
+	}, []string{"storage"})
+
+	// SQLFSBytes counts the bytes read from or written to sqlfs by the
+	// operation, read or write.
+	SQLFSBytes = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "sqlflow_sqlfs_bytes_total",
+		Help: "The bytes read from or written to sqlfs by the operation.",
+	}, []string{"operation"})
+)
+
+func init() {
+	Registry.MustRegister(
+		prometheus.NewGoCollector(),
+		prometheus.NewProcessCollector(prometheus.ProcessCollectorOpts{}),
+		statements, statementDuration,
+		ActiveJobs, QueuedJobs,
+		SubprocessFailures, ParseErrors,
+		argoDuration,
+		modelDuration, modelBytes,
+		SQLFSBytes)
+}
+
+// Handler returns the HTTP handler serving the metrics for Prometheus.
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{})
+}
+
+func status(e error) string {
+	if e != nil {
+		return StatusFailed
+	}
+	return StatusSucceeded
+}
+
+// ObserveStatement records a statement of the IR type irType, which started
+// at start and finished with the error e. irType is "unknown" if it is empty,
+// e.g., the statement failed before generating the IR.
+func ObserveStatement(irType string, start time.Time, e error) {
+	if irType == "" {
+		irType = "unknown"
+	}
+	statements.WithLabelValues(irType, status(e)).Inc()
+	statementDuration.WithLabelValues(irType).Observe(time.Since(start).Seconds())
+}
+
+// ObserveArgo records a request to Argo, e.g., submit or fetch, which
+// started at start and finished with the error e.
+func ObserveArgo(operation string, start time.Time, e error) {
+	argoDuration.WithLabelValues(operation, status(e)).Observe(time.Since(start).Seconds())
+}
+
+// ObserveModel records saving or loading a model in the storage, e.g., db or
+// oss, which started at start and finished with the error e. size is the
+// size of the saved model tarball, and is ignored for loading.
+func ObserveModel(operation, storage string, start time.Time, size int64, e error) {
+	modelDuration.WithLabelValues(operation, storage, status(e)).Observe(time.Since(start).Seconds())
+	if operation == "save" && e == nil && size > 0 {
+		modelBytes.WithLabelValues(storage).Add(float64(size))
+	}
+}
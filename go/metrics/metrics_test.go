@@ -0,0 +1,61 @@
+// Copyright 2020 The SQLFlow Authors. All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetrics(t *testing.T) {
+	a := assert.New(t)
+	start := time.Now()
+	ObserveStatement("TrainStmt", start, nil)
+	ObserveStatement("", start, fmt.Errorf("failed"))
+	a.Equal(1.0, testutil.ToFloat64(statements.WithLabelValues("TrainStmt", StatusSucceeded)))
+	a.Equal(1.0, testutil.ToFloat64(statements.WithLabelValues("unknown", StatusFailed)))
+
+	ObserveModel("save", "db", start, 1024, nil)
+	ObserveModel("save", "db", start, 1024, fmt.Errorf("failed"))
+	ObserveModel("load", "db", start, 0, nil)
+	a.Equal(1024.0, testutil.ToFloat64(modelBytes.WithLabelValues("db")))
+
+	ObserveArgo("submit", start, nil)
+	ParseErrors.WithLabelValues("mysql").Inc()
+
+	srv := httptest.NewServer(Handler())
+	defer srv.Close()
+	res, e := srv.Client().Get(srv.URL)
+	a.NoError(e)
+	defer res.Body.Close()
+	body, e := ioutil.ReadAll(res.Body)
+	a.NoError(e)
+	for _, m := range []string{
+		`sqlflow_statements_total{status="succeeded",type="TrainStmt"} 1`,
+		`sqlflow_statement_duration_seconds_count{type="TrainStmt"} 1`,
+		`sqlflow_model_duration_seconds_count{operation="save",status="failed",storage="db"} 1`,
+		`sqlflow_argo_request_duration_seconds_count{operation="submit",status="succeeded"} 1`,
+		`sqlflow_parse_errors_total{dialect="mysql"} 1`,
+		`sqlflow_active_jobs 0`,
+		`go_goroutines`,
+	} {
+		a.Contains(string(body), m)
+	}
+}
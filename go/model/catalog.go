@@ -0,0 +1,221 @@
+// Copyright 2020 The SQLFlow Authors. All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"sqlflow.org/sqlflow/go/database"
+)
+
+const (
+	// EnvCatalogTable is the environment variable of the model catalog
+	// table. The default is defaultCatalogTable.
+	EnvCatalogTable     = "SQLFLOW_MODEL_CATALOG_TABLE"
+	defaultCatalogTable = "sqlflow_models.model_catalog"
+)
+
+// CatalogEntry is a row of the model catalog table, which indexes the
+// trained models by the name and the version.
+type CatalogEntry struct {
+	Name        string
+	Owner       string
+	Estimator   string
+	TrainSelect string
+	// Attributes is the JSON encoded attributes in the WITH clause.
+	Attributes string
+	Label      string
+	Features   []string
+	CreatedAt  time.Time
+	// Size is the size of the model tarball in bytes.
+	Size int64
+	// Version is the model version, or 0 if the model is not versioned,
+	// e.g. saved to s3://bucket/path.
+	Version int64
+	// StorageURI is where the model is saved, e.g. the sqlfs table or
+	// s3://bucket/path.
+	StorageURI string
+	// Lineage is the JSON encoded Lineage of the model.
+	Lineage string
+}
+
+// CatalogFilter filters the models when listing the model catalog. Empty
+// fields are ignored.
+type CatalogFilter struct {
+	// Name matches the models whose names contain it.
+	Name         string
+	Owner        string
+	Estimator    string
+	Label        string
+	CreatedAfter time.Time
+	// Limit is the maximum number of returned models, 0 for no limit.
+	Limit int
+}
+
+var catalogColumns = []string{"name", "owner", "estimator", "train_select", "attributes",
+	"label", "features", "created_at", "size", "version", "storage_uri", "lineage"}
+
+// CatalogTable returns the model catalog table.
+func CatalogTable() string {
+	if t := os.Getenv(EnvCatalogTable); t != "" {
+		return t
+	}
+	return defaultCatalogTable
+}
+
+func createCatalogTable(db *database.DB, table string) error {
+	str, text, datetime, e := database.ColumnTypes(db.DriverName)
+	if e != nil {
+		return e
+	}
+	types := []string{str, str, str, text, text, str, text, datetime, "BIGINT", "BIGINT", text, text}
+	fields := []string{}
+	for i, c := range catalogColumns {
+		fields = append(fields, c+" "+types[i])
+	}
+	if db.DriverName == "mysql" {
+		fields = append(fields, "PRIMARY KEY (name, version)")
+	}
+	stmt := fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (%s)", table, strings.Join(fields, ", "))
+	if _, e := db.Exec(stmt); e != nil {
+		return fmt.Errorf("failed executing %s: %v", stmt, e)
+	}
+	return nil
+}
+
+// UpsertCatalog creates the model catalog table if not exists, and
+// replaces the row of the model version by entry.
+func UpsertCatalog(db *database.DB, table string, entry *CatalogEntry) error {
+	if e := createCatalogTable(db, table); e != nil {
+		return e
+	}
+	createdAt := database.TimeLiteral(db.DriverName, entry.CreatedAt)
+	values := strings.Join([]string{
+		database.QuoteString(entry.Name),
+		database.QuoteString(entry.Owner),
+		database.QuoteString(entry.Estimator),
+		database.QuoteString(entry.TrainSelect),
+		database.QuoteString(entry.Attributes),
+		database.QuoteString(entry.Label),
+		database.QuoteString(strings.Join(entry.Features, ",")),
+		createdAt,
+		fmt.Sprintf("%d", entry.Size),
+		fmt.Sprintf("%d", entry.Version),
+		database.QuoteString(entry.StorageURI),
+		database.QuoteString(entry.Lineage),
+	}, ", ")
+
+	var stmt string
+	if db.DriverName == "mysql" {
+		stmt = fmt.Sprintf("REPLACE INTO %s (%s) VALUES (%s)", table, strings.Join(catalogColumns, ","), values)
+	} else {
+		if e := deleteCatalog(db, table, entry.Name, []int64{entry.Version}); e != nil {
+			return e
+		}
+		stmt = fmt.Sprintf("INSERT INTO TABLE %s SELECT %s", table, values)
+	}
+	if _, e := db.Exec(stmt); e != nil {
+		return fmt.Errorf("failed executing %s: %v", stmt, e)
+	}
+	return nil
+}
+
+// DeleteCatalog removes the versions of the model name from the model
+// catalog table, or all versions if versions is nil.
+func DeleteCatalog(db *database.DB, table, name string, versions []int64) error {
+	if e := createCatalogTable(db, table); e != nil {
+		return e
+	}
+	return deleteCatalog(db, table, name, versions)
+}
+
+func deleteCatalog(db *database.DB, table, name string, versions []int64) error {
+	cond := "name=" + database.QuoteString(name)
+	if versions != nil {
+		vs := []string{}
+		for _, v := range versions {
+			vs = append(vs, fmt.Sprintf("%d", v))
+		}
+		cond += fmt.Sprintf(" AND version IN (%s)", strings.Join(vs, ","))
+	}
+	var stmt string
+	if db.DriverName == "mysql" {
+		stmt = fmt.Sprintf("DELETE FROM %s WHERE %s", table, cond)
+	} else {
+		// Hive and MaxCompute don't support DELETE.
+		stmt = fmt.Sprintf("INSERT OVERWRITE TABLE %s SELECT * FROM %s WHERE NOT (%s)", table, table, cond)
+	}
+	if _, e := db.Exec(stmt); e != nil {
+		return fmt.Errorf("failed executing %s: %v", stmt, e)
+	}
+	return nil
+}
+
+// ListCatalog returns the model versions in the model catalog table
+// matching the filter, the newest first.
+func ListCatalog(db *database.DB, table string, filter *CatalogFilter) ([]*CatalogEntry, error) {
+	conds := []string{}
+	if filter.Name != "" {
+		conds = append(conds, fmt.Sprintf("name LIKE %s", database.QuoteString("%"+filter.Name+"%")))
+	}
+	for column, value := range map[string]string{"owner": filter.Owner, "estimator": filter.Estimator, "label": filter.Label} {
+		if value != "" {
+			conds = append(conds, fmt.Sprintf("%s=%s", column, database.QuoteString(value)))
+		}
+	}
+	if !filter.CreatedAfter.IsZero() {
+		conds = append(conds, "created_at>"+database.TimeLiteral(db.DriverName, filter.CreatedAfter))
+	}
+	query := fmt.Sprintf("SELECT %s FROM %s", strings.Join(catalogColumns, ","), table)
+	if len(conds) > 0 {
+		query += " WHERE " + strings.Join(conds, " AND ")
+	}
+	query += " ORDER BY created_at DESC"
+	if filter.Limit > 0 {
+		query += fmt.Sprintf(" LIMIT %d", filter.Limit)
+	}
+
+	rows, e := db.Query(query)
+	if e != nil {
+		return nil, fmt.Errorf("failed querying model catalog: %v", e)
+	}
+	defer rows.Close()
+	entries := []*CatalogEntry{}
+	for rows.Next() {
+		entry := &CatalogEntry{}
+		var features, createdAt string
+		if e := rows.Scan(&entry.Name, &entry.Owner, &entry.Estimator, &entry.TrainSelect, &entry.Attributes,
+			&entry.Label, &features, &createdAt, &entry.Size, &entry.Version, &entry.StorageURI, &entry.Lineage); e != nil {
+			return nil, e
+		}
+		if features != "" {
+			entry.Features = strings.Split(features, ",")
+		}
+		entry.CreatedAt = parseCatalogTime(createdAt)
+		entries = append(entries, entry)
+	}
+	return entries, rows.Err()
+}
+
+func parseCatalogTime(s string) time.Time {
+	for _, layout := range []string{database.TimeLayout, time.RFC3339Nano, "2006-01-02 15:04:05.999999999"} {
+		if t, e := time.ParseInLocation(layout, s, time.Local); e == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
@@ -0,0 +1,82 @@
+// Copyright 2020 The SQLFlow Authors. All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"sqlflow.org/sqlflow/go/database"
+	"sqlflow.org/sqlflow/go/test"
+)
+
+func TestModelCatalog(t *testing.T) {
+	if test.GetEnv("SQLFLOW_TEST_DB", "mysql") != "mysql" {
+		t.Skip("only test model catalog on MySQL")
+	}
+	a := assert.New(t)
+	db := database.GetTestingDBSingleton()
+	table := "iris.model_catalog_test"
+	_, e := db.Exec(fmt.Sprintf("DROP TABLE IF EXISTS %s", table))
+	a.NoError(e)
+
+	entry := &CatalogEntry{
+		Name:        "sqlflow_models.my_dnn",
+		Owner:       "alice",
+		Estimator:   "DNNClassifier",
+		TrainSelect: "SELECT * FROM iris.train WHERE class!='2'",
+		Attributes:  `{"model.n_classes":3}`,
+		Label:       "class",
+		Features:    []string{"sepal_length", "sepal_width"},
+		CreatedAt:   time.Now().Add(-time.Hour),
+		Size:        1024,
+		Version:     1,
+		StorageURI:  "sqlflow_models.my_dnn__v1",
+		Lineage:     `{"inputs":["iris.train"]}`,
+	}
+	a.NoError(UpsertCatalog(db, table, entry))
+	entry.Version, entry.CreatedAt, entry.StorageURI = 2, time.Now(), "sqlflow_models.my_dnn__v2"
+	a.NoError(UpsertCatalog(db, table, entry))
+	// upserting the same version replaces the row
+	entry.Size = 2048
+	a.NoError(UpsertCatalog(db, table, entry))
+	a.NoError(UpsertCatalog(db, table, &CatalogEntry{Name: "sqlflow_models.my_xgb", Owner: "bob", Estimator: "xgboost.gbtree", CreatedAt: time.Now()}))
+
+	entries, e := ListCatalog(db, table, &CatalogFilter{Name: "dnn"})
+	a.NoError(e)
+	a.Equal(2, len(entries))
+	a.Equal(int64(2), entries[0].Version)
+	a.Equal(int64(2048), entries[0].Size)
+	a.Equal(entry.TrainSelect, entries[0].TrainSelect)
+	a.Equal([]string{"sepal_length", "sepal_width"}, entries[0].Features)
+	a.Equal(entry.Lineage, entries[0].Lineage)
+	a.Equal(int64(1), entries[1].Version)
+
+	a.NoError(DeleteCatalog(db, table, "sqlflow_models.my_dnn", []int64{1}))
+	entries, e = ListCatalog(db, table, &CatalogFilter{Name: "dnn"})
+	a.NoError(e)
+	a.Equal(1, len(entries))
+	a.Equal(int64(2), entries[0].Version)
+
+	entries, e = ListCatalog(db, table, &CatalogFilter{Owner: "bob"})
+	a.NoError(e)
+	a.Equal(1, len(entries))
+	a.Equal("sqlflow_models.my_xgb", entries[0].Name)
+
+	entries, e = ListCatalog(db, table, &CatalogFilter{CreatedAfter: time.Now().Add(-time.Minute), Limit: 1})
+	a.NoError(e)
+	a.Equal(1, len(entries))
+}
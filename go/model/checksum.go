@@ -0,0 +1,226 @@
+// Copyright 2020 The SQLFlow Authors. All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"sqlflow.org/sqlflow/go/database"
+	"sqlflow.org/sqlflow/go/sqlfs"
+)
+
+// EnvModelHMACKey is the environment variable of the key to sign models
+// saved to databases. If it is set, loading a model whose signature is
+// missing or doesn't match fails.
+const EnvModelHMACKey = "SQLFLOW_MODEL_HMAC_KEY"
+
+// A model saved to sqlfs starts with a header of modelHeaderMagic and a flag
+// byte telling if the model is signed. The tarball follows the header, and
+// a trailer of the tarball size as a big-endian uint64, the SHA-256 checksum
+// of the tarball, and the HMAC-SHA256 signature if signed, follows the
+// tarball, so that the model is written in one pass. Models saved before
+// the header was introduced are loaded as is.
+const (
+	modelHeaderMagic = "SQLFLOW_MODEL\x01"
+	modelHeaderLen   = len(modelHeaderMagic) + 1
+	modelFlagSigned  = 1
+)
+
+// modelTrailerLen returns the trailer length of a model signed or not.
+func modelTrailerLen(signed bool) int {
+	if signed {
+		return 8 + 2*sha256.Size
+	}
+	return 8 + sha256.Size
+}
+
+func modelHMACKey() []byte {
+	if k := os.Getenv(EnvModelHMACKey); k != "" {
+		return []byte(k)
+	}
+	return nil
+}
+
+func corruptedModelError(reason string) error {
+	return fmt.Errorf("model is corrupted: %s, it may be partially written or truncated", reason)
+}
+
+// HasModelHeader tells if the table is a sqlfs table of a model, i.e. it
+// starts with the model header, or the metadata header of the models saved
+// by SaveDBExperimental and the Python runtime.
+func HasModelHeader(db *database.DB, table string) bool {
+	sqlf, e := sqlfs.Open(db.DB, table, 1)
+	if e != nil {
+		return false
+	}
+	defer sqlf.Close()
+	header := make([]byte, len(modelHeaderMagic))
+	if _, e := io.ReadFull(sqlf, header); e != nil {
+		return false
+	}
+	if string(header) == modelHeaderMagic {
+		return true
+	}
+	// the metadata JSON follows its length in a hex string like 0x0000ffff
+	metaLength, e := strconv.ParseInt(string(header[:10]), 0, 64)
+	if e != nil || !strings.HasPrefix(string(header), "0x") || metaLength < int64(len(header)-10) {
+		return false
+	}
+	meta := make([]byte, metaLength)
+	copy(meta, header[10:])
+	if _, e := io.ReadFull(sqlf, meta[len(header)-10:]); e != nil {
+		return false
+	}
+	return json.Valid(meta)
+}
+
+// checksumWriter writes the model header, the tarball written to it, and
+// the trailer on closing.
+type checksumWriter struct {
+	w   io.Writer
+	n   int64
+	sum hash.Hash
+	mac hash.Hash // nil if the model isn't signed
+}
+
+// newChecksumWriter writes the model header to w, and returns the writer of
+// the tarball, which is signed by key if key isn't nil.
+func newChecksumWriter(w io.Writer, key []byte) (*checksumWriter, error) {
+	c := &checksumWriter{w: w, sum: sha256.New()}
+	header := []byte(modelHeaderMagic + "\x00")
+	if key != nil {
+		c.mac = hmac.New(sha256.New, key)
+		header[len(header)-1] = modelFlagSigned
+	}
+	if _, e := w.Write(header); e != nil {
+		return nil, e
+	}
+	return c, nil
+}
+
+func (c *checksumWriter) Write(p []byte) (int, error) {
+	n, e := c.w.Write(p)
+	c.n += int64(n)
+	c.sum.Write(p[:n])
+	if c.mac != nil {
+		c.mac.Write(p[:n])
+	}
+	return n, e
+}
+
+// Close writes the trailer. It doesn't close the underlying writer.
+func (c *checksumWriter) Close() error {
+	trailer := make([]byte, 8)
+	binary.BigEndian.PutUint64(trailer, uint64(c.n))
+	trailer = c.sum.Sum(trailer)
+	if c.mac != nil {
+		trailer = c.mac.Sum(trailer)
+	}
+	_, e := c.w.Write(trailer)
+	return e
+}
+
+// checksumReader reads the tarball written by checksumWriter and verifies
+// it against the trailer on reaching EOF. It holds back the bytes that may
+// be the trailer.
+type checksumReader struct {
+	r          io.Reader
+	buf        []byte
+	eof        bool
+	trailerLen int
+	n          int64
+	sum        hash.Hash
+	mac        hash.Hash // nil if the signature isn't verified
+}
+
+// newChecksumReader returns a reader of the tarball in r. It reads r as is
+// if r doesn't start with the model header and key is nil.
+func newChecksumReader(r io.Reader, key []byte) (io.Reader, error) {
+	magic := make([]byte, modelHeaderLen)
+	n, e := io.ReadFull(r, magic)
+	if e != nil && e != io.ErrUnexpectedEOF && e != io.EOF {
+		return nil, e
+	}
+	if n < len(magic) || string(magic[:len(modelHeaderMagic)]) != modelHeaderMagic {
+		if key != nil {
+			return nil, fmt.Errorf("model is not signed, but %s is set", EnvModelHMACKey)
+		}
+		return io.MultiReader(bytes.NewReader(magic[:n]), r), nil
+	}
+	signed := magic[len(magic)-1]&modelFlagSigned != 0
+	if !signed && key != nil {
+		return nil, fmt.Errorf("model is not signed, but %s is set", EnvModelHMACKey)
+	}
+	c := &checksumReader{
+		r:          r,
+		trailerLen: modelTrailerLen(signed),
+		sum:        sha256.New(),
+	}
+	c.buf = make([]byte, 0, 32*1024+c.trailerLen)
+	// NOTE: skip verifying the signature if no key is given.
+	if signed && key != nil {
+		c.mac = hmac.New(sha256.New, key)
+	}
+	return c, nil
+}
+
+func (c *checksumReader) Read(p []byte) (int, error) {
+	for !c.eof && len(c.buf) <= c.trailerLen {
+		n, e := c.r.Read(c.buf[len(c.buf):cap(c.buf)])
+		c.buf = c.buf[:len(c.buf)+n]
+		if e == io.EOF {
+			c.eof = true
+		} else if e != nil {
+			return 0, e
+		}
+	}
+	if len(c.buf) <= c.trailerLen {
+		return 0, c.verify()
+	}
+	n := copy(p, c.buf[:len(c.buf)-c.trailerLen])
+	c.n += int64(n)
+	c.sum.Write(p[:n])
+	if c.mac != nil {
+		c.mac.Write(p[:n])
+	}
+	c.buf = c.buf[:copy(c.buf, c.buf[n:])]
+	return n, nil
+}
+
+// verify checks the tarball read against the trailer in c.buf.
+func (c *checksumReader) verify() error {
+	if len(c.buf) < c.trailerLen {
+		return corruptedModelError("truncated trailer")
+	}
+	if size := int64(binary.BigEndian.Uint64(c.buf[:8])); c.n != size {
+		return corruptedModelError(fmt.Sprintf("read %d bytes of the %d bytes tarball", c.n, size))
+	}
+	if !bytes.Equal(c.sum.Sum(nil), c.buf[8:8+sha256.Size]) {
+		return corruptedModelError("SHA-256 checksum mismatch")
+	}
+	if c.mac != nil && !hmac.Equal(c.mac.Sum(nil), c.buf[8+sha256.Size:]) {
+		return fmt.Errorf("model signature mismatch, the model is not signed by the %s of this server", EnvModelHMACKey)
+	}
+	return io.EOF
+}
@@ -0,0 +1,144 @@
+// Copyright 2020 The SQLFlow Authors. All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"bytes"
+	"io/ioutil"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"sqlflow.org/sqlflow/go/tar"
+)
+
+func writeChecksummed(t *testing.T, data, key []byte) []byte {
+	var buf bytes.Buffer
+	w, e := newChecksumWriter(&buf, key)
+	assert.NoError(t, e)
+	_, e = w.Write(data)
+	assert.NoError(t, e)
+	assert.NoError(t, w.Close())
+	assert.Equal(t, int64(len(data)), w.n)
+	return buf.Bytes()
+}
+
+func readChecksummed(saved, key []byte) ([]byte, error) {
+	r, e := newChecksumReader(bytes.NewReader(saved), key)
+	if e != nil {
+		return nil, e
+	}
+	return ioutil.ReadAll(r)
+}
+
+func TestModelChecksum(t *testing.T) {
+	a := assert.New(t)
+	data := make([]byte, 100*1024+7)
+	rand.Read(data)
+
+	saved := writeChecksummed(t, data, nil)
+	// the tarball is between the header and the trailer of the checksum
+	a.Equal(modelHeaderLen+len(data)+modelTrailerLen(false), len(saved))
+	a.Equal(data, saved[modelHeaderLen:modelHeaderLen+len(data)])
+	read, e := readChecksummed(saved, nil)
+	a.NoError(e)
+	a.Equal(data, read)
+
+	// a flipped byte
+	corrupted := append([]byte{}, saved...)
+	corrupted[len(corrupted)/2] ^= 0xff
+	_, e = readChecksummed(corrupted, nil)
+	a.Error(e)
+	a.Contains(e.Error(), "model is corrupted")
+
+	// a truncated model
+	_, e = readChecksummed(saved[:len(saved)-1024], nil)
+	a.Error(e)
+	a.Contains(e.Error(), "model is corrupted")
+	_, e = readChecksummed(saved[:modelHeaderLen+10], nil)
+	a.Error(e)
+	a.Contains(e.Error(), "model is corrupted")
+	_, e = readChecksummed(append(append([]byte{}, saved...), 0), nil)
+	a.Error(e)
+	a.Contains(e.Error(), "model is corrupted")
+
+	// models saved without the header
+	read, e = readChecksummed(data, nil)
+	a.NoError(e)
+	a.Equal(data, read)
+	read, e = readChecksummed(data[:3], nil)
+	a.NoError(e)
+	a.Equal(data[:3], read)
+}
+
+func TestModelSignature(t *testing.T) {
+	a := assert.New(t)
+	data := []byte("a model tarball")
+	key := []byte("secret")
+
+	signed := writeChecksummed(t, data, key)
+	a.Equal(modelHeaderLen+len(data)+modelTrailerLen(true), len(signed))
+	a.Equal(data, signed[modelHeaderLen:modelHeaderLen+len(data)])
+	read, e := readChecksummed(signed, key)
+	a.NoError(e)
+	a.Equal(data, read)
+	// verify the checksum only without the key
+	read, e = readChecksummed(signed, nil)
+	a.NoError(e)
+	a.Equal(data, read)
+
+	_, e = readChecksummed(signed, []byte("another secret"))
+	a.Error(e)
+	a.Contains(e.Error(), "signature mismatch")
+
+	_, e = readChecksummed(writeChecksummed(t, data, nil), key)
+	a.Error(e)
+	a.Contains(e.Error(), "not signed")
+	_, e = readChecksummed(data, key)
+	a.Error(e)
+	a.Contains(e.Error(), "not signed")
+}
+
+func TestUntarTamperedModel(t *testing.T) {
+	a := assert.New(t)
+	src, e := ioutil.TempDir("", "sqlflow_model_src")
+	a.NoError(e)
+	defer os.RemoveAll(src)
+	a.NoError(ioutil.WriteFile(filepath.Join(src, modelMetaFileName), []byte("{}"), 0644))
+	var tarball bytes.Buffer
+	a.NoError(tar.Compress(src, &tarball))
+	key := []byte("secret")
+	saved := writeChecksummed(t, tarball.Bytes(), key)
+
+	dst, e := ioutil.TempDir("", "sqlflow_model_dst")
+	a.NoError(e)
+	defer os.RemoveAll(dst)
+	r, e := newChecksumReader(bytes.NewReader(saved), []byte("another secret"))
+	a.NoError(e)
+	_, e = untar(r, dst)
+	a.Error(e)
+	a.Contains(e.Error(), "signature mismatch")
+	files, e := ioutil.ReadDir(dst)
+	a.NoError(e)
+	a.Equal(0, len(files))
+
+	r, e = newChecksumReader(bytes.NewReader(saved), key)
+	a.NoError(e)
+	_, e = untar(r, dst)
+	a.NoError(e)
+	_, e = os.Stat(filepath.Join(dst, modelMetaFileName))
+	a.NoError(e)
+}
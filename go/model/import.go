@@ -0,0 +1,172 @@
+// Copyright 2020 The SQLFlow Authors. All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// EnvModelImportRoot is the environment variable of the directory on the
+// SQLFlow server that IMPORT MODEL could read models from. IMPORT MODEL is
+// disabled if it is not set.
+const EnvModelImportRoot = "SQLFLOW_MODEL_IMPORT_ROOT"
+
+const (
+	// xgboostModelFileName is the XGBoost model file loaded by the Python
+	// runtime for prediction and explanation.
+	xgboostModelFileName = "my_model"
+	// importedSavedModelDir is where an imported SavedModel is copied to,
+	// the file exported_path points to it as if the model were trained by
+	// a TensorFlow estimator.
+	importedSavedModelDir = "imported_model"
+)
+
+// Import copies the externally trained model src, an XGBoost model file if
+// kind is XGBOOST, or a TensorFlow SavedModel directory if kind is
+// TENSORFLOW, into cwd in the layout of the models trained by SQLFlow, and
+// writes meta as the model metadata. src is a path or a file:// URI on the
+// local filesystem under the directory EnvModelImportRoot. meta should contain "original_sql", which describes the
+// model as a training statement does. The returned model is to be saved by
+// Save.
+func Import(cwd, src string, kind int, meta map[string]interface{}) (*Model, error) {
+	if strings.Contains(src, "://") {
+		if !strings.HasPrefix(src, "file://") {
+			return nil, fmt.Errorf("cannot import model %s: only local files are supported", src)
+		}
+		src = strings.TrimPrefix(src, "file://")
+	}
+	src, e := resolveImportPath(src)
+	if e != nil {
+		return nil, fmt.Errorf("cannot import model: %v", e)
+	}
+	fi, e := os.Stat(src)
+	if e != nil {
+		return nil, fmt.Errorf("cannot import model: %v", e)
+	}
+	switch kind {
+	case XGBOOST:
+		if fi.IsDir() {
+			return nil, fmt.Errorf("cannot import model %s: expecting an XGBoost model file", src)
+		}
+		if e := copyFile(src, filepath.Join(cwd, xgboostModelFileName), 0644); e != nil {
+			return nil, fmt.Errorf("cannot import model: %v", e)
+		}
+	case TENSORFLOW:
+		if !isSavedModel(src) {
+			return nil, fmt.Errorf("cannot import model %s: expecting a TensorFlow SavedModel directory", src)
+		}
+		if e := copyDir(src, filepath.Join(cwd, importedSavedModelDir)); e != nil {
+			return nil, fmt.Errorf("cannot import model: %v", e)
+		}
+		if e := ioutil.WriteFile(filepath.Join(cwd, "exported_path"), []byte(importedSavedModelDir), 0644); e != nil {
+			return nil, e
+		}
+	default:
+		return nil, fmt.Errorf("cannot import model %s: unsupported model type %d", src, kind)
+	}
+
+	data, e := json.Marshal(meta)
+	if e != nil {
+		return nil, fmt.Errorf("cannot encode model metadata: %v", e)
+	}
+	if e := ioutil.WriteFile(filepath.Join(cwd, modelMetaFileName), data, 0644); e != nil {
+		return nil, e
+	}
+	m := New(cwd, "")
+	if e := decodeMeta(m, data); e != nil {
+		return nil, e
+	}
+	return m, nil
+}
+
+// resolveImportPath resolves the symbolic links in src, and returns an
+// error if src is out of the directory EnvModelImportRoot.
+func resolveImportPath(src string) (string, error) {
+	root := os.Getenv(EnvModelImportRoot)
+	if root == "" {
+		return "", fmt.Errorf("IMPORT MODEL is disabled, set %s to the directory to import models from", EnvModelImportRoot)
+	}
+	root, e := filepath.Abs(root)
+	if e != nil {
+		return "", e
+	}
+	if root, e = filepath.EvalSymlinks(root); e != nil {
+		return "", e
+	}
+	if src, e = filepath.Abs(src); e != nil {
+		return "", e
+	}
+	if src, e = filepath.EvalSymlinks(src); e != nil {
+		return "", e
+	}
+	if src != root && !strings.HasPrefix(src, root+string(os.PathSeparator)) {
+		return "", fmt.Errorf("%s is out of %s %s", src, EnvModelImportRoot, root)
+	}
+	return src, nil
+}
+
+// isSavedModel returns whether dir is a TensorFlow SavedModel directory.
+func isSavedModel(dir string) bool {
+	for _, f := range []string{"saved_model.pb", "saved_model.pbtxt"} {
+		if fi, e := os.Stat(filepath.Join(dir, f)); e == nil && !fi.IsDir() {
+			return true
+		}
+	}
+	return false
+}
+
+// copyDir copies the regular files in the directory src recursively to dst.
+func copyDir(src, dst string) error {
+	return filepath.Walk(src, func(p string, fi os.FileInfo, e error) error {
+		if e != nil {
+			return e
+		}
+		rel, e := filepath.Rel(src, p)
+		if e != nil {
+			return e
+		}
+		target := filepath.Join(dst, rel)
+		switch {
+		case fi.IsDir():
+			return os.MkdirAll(target, 0755)
+		case fi.Mode().IsRegular():
+			return copyFile(p, target, fi.Mode().Perm())
+		default:
+			return fmt.Errorf("unsupported file %s", p)
+		}
+	})
+}
+
+func copyFile(src, dst string, perm os.FileMode) error {
+	in, e := os.Open(src)
+	if e != nil {
+		return e
+	}
+	defer in.Close()
+	out, e := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, perm)
+	if e != nil {
+		return e
+	}
+	if _, e := io.Copy(out, in); e != nil {
+		out.Close()
+		return e
+	}
+	return out.Close()
+}
@@ -0,0 +1,89 @@
+// Copyright 2020 The SQLFlow Authors. All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestImport(t *testing.T) {
+	a := assert.New(t)
+	src, e := ioutil.TempDir("/tmp", "sqlflow_import")
+	a.NoError(e)
+	defer os.RemoveAll(src)
+	a.NoError(ioutil.WriteFile(filepath.Join(src, "model.json"), []byte(`{"learner": {}}`), 0644))
+	savedModel := filepath.Join(src, "saved_model")
+	a.NoError(os.MkdirAll(filepath.Join(savedModel, "variables"), 0755))
+	a.NoError(ioutil.WriteFile(filepath.Join(savedModel, "saved_model.pb"), []byte("pb"), 0644))
+	a.NoError(ioutil.WriteFile(filepath.Join(savedModel, "variables", "variables.index"), []byte("index"), 0644))
+	os.Setenv(EnvModelImportRoot, src)
+	defer os.Unsetenv(EnvModelImportRoot)
+
+	importSQL := `IMPORT MODEL 'file:///tmp/model.json' AS my_model WITH estimator="xgboost.gbtree";`
+	meta := map[string]interface{}{"original_sql": importSQL, "class_name": "xgboost.gbtree"}
+	{
+		cwd, e := ioutil.TempDir("/tmp", "sqlflow_models")
+		a.NoError(e)
+		defer os.RemoveAll(cwd)
+		m, e := Import(cwd, "file://"+filepath.Join(src, "model.json"), XGBOOST, meta)
+		a.NoError(e)
+		a.Equal(importSQL, m.TrainSelect)
+		a.Equal("xgboost.gbtree", m.GetMetaAsString("class_name"))
+		b, e := ioutil.ReadFile(filepath.Join(cwd, xgboostModelFileName))
+		a.NoError(e)
+		a.Equal(`{"learner": {}}`, string(b))
+		loaded, e := loadMeta(filepath.Join(cwd, modelMetaFileName))
+		a.NoError(e)
+		a.Equal(importSQL, loaded.TrainSelect)
+
+		_, e = Import(cwd, savedModel, XGBOOST, meta)
+		a.Error(e)
+	}
+	{
+		cwd, e := ioutil.TempDir("/tmp", "sqlflow_models")
+		a.NoError(e)
+		defer os.RemoveAll(cwd)
+		_, e = Import(cwd, savedModel, TENSORFLOW, meta)
+		a.NoError(e)
+		b, e := ioutil.ReadFile(filepath.Join(cwd, "exported_path"))
+		a.NoError(e)
+		a.Equal(importedSavedModelDir, string(b))
+		b, e = ioutil.ReadFile(filepath.Join(cwd, importedSavedModelDir, "variables", "variables.index"))
+		a.NoError(e)
+		a.Equal("index", string(b))
+
+		_, e = Import(cwd, filepath.Join(src, "model.json"), TENSORFLOW, meta)
+		a.Error(e)
+	}
+	_, e = Import(src, "s3://bucket/model.json", XGBOOST, meta)
+	a.Error(e)
+	_, e = Import(src, filepath.Join(src, "no_such_model"), XGBOOST, meta)
+	a.Error(e)
+
+	// models out of the import root
+	a.NoError(os.Symlink("/etc/passwd", filepath.Join(src, "passwd")))
+	for _, p := range []string{"/etc/passwd", filepath.Join(src, "..", filepath.Base(src)+"_other"), filepath.Join(src, "passwd")} {
+		_, e = Import(src, p, XGBOOST, meta)
+		a.Error(e)
+	}
+	os.Unsetenv(EnvModelImportRoot)
+	_, e = Import(src, filepath.Join(src, "model.json"), XGBOOST, meta)
+	a.Error(e)
+	a.Contains(e.Error(), "disabled")
+}
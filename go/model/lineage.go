@@ -0,0 +1,184 @@
+// Copyright 2020 The SQLFlow Authors. All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"crypto/sha256"
+	"database/sql"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/bitly/go-simplejson"
+	"sqlflow.org/sqlflow/go/database"
+)
+
+// SQLFlowVersion is the SQLFlow version recorded in the model lineage. It
+// could be set by go build -ldflags "-X sqlflow.org/sqlflow/go/model.SQLFlowVersion=v0.1".
+var SQLFlowVersion = "develop"
+
+// EnvLineageFingerprint is the environment variable to enable computing
+// the fingerprints of the training data if set to "true", which scans the
+// training data once more. Otherwise, only the row counts are recorded.
+const EnvLineageFingerprint = "SQLFLOW_LINEAGE_FINGERPRINT"
+
+const lineageMetaKey = "lineage"
+
+// Lineage records the data and the program that trained a model.
+type Lineage struct {
+	// Inputs are the tables that the training SELECT reads.
+	Inputs                []string `json:"inputs"`
+	TrainRows             int64    `json:"train_rows,omitempty"`
+	TrainFingerprint      string   `json:"train_fingerprint,omitempty"`
+	ValidationRows        int64    `json:"validation_rows,omitempty"`
+	ValidationFingerprint string   `json:"validation_fingerprint,omitempty"`
+	SQLFlowVersion        string   `json:"sqlflow_version"`
+	CodeGenerator         string   `json:"code_generator"`
+}
+
+// NewLineage returns the lineage of a model trained by the data from
+// trainSelect and validationSelect, which could be "", by codeGenerator.
+func NewLineage(db *database.DB, inputs []string, trainSelect, validationSelect, codeGenerator string) (*Lineage, error) {
+	l := &Lineage{Inputs: inputs, SQLFlowVersion: SQLFlowVersion, CodeGenerator: codeGenerator}
+	var e error
+	if l.TrainRows, l.TrainFingerprint, e = fingerprint(db, trainSelect); e != nil {
+		return nil, e
+	}
+	if validationSelect != "" {
+		if l.ValidationRows, l.ValidationFingerprint, e = fingerprint(db, validationSelect); e != nil {
+			return nil, e
+		}
+	}
+	return l, nil
+}
+
+// QualifyInputs qualifies the input tables by the database dbName.
+func QualifyInputs(inputs []string, dbName string) []string {
+	qualified := []string{}
+	for _, table := range inputs {
+		if !strings.Contains(table, ".") && dbName != "" {
+			table = dbName + "." + table
+		}
+		qualified = append(qualified, table)
+	}
+	return qualified
+}
+
+// fingerprint returns the row count and the fingerprint of the result of
+// query. The fingerprint is the lane-wise sum of the SHA-256 digests of
+// the rows, so it doesn't depend on the order of the rows.
+func fingerprint(db *database.DB, query string) (int64, string, error) {
+	query = strings.TrimSuffix(strings.TrimSpace(query), ";")
+	if os.Getenv(EnvLineageFingerprint) != "true" {
+		var n int64
+		if e := db.QueryRow(fmt.Sprintf("SELECT COUNT(*) FROM (%s) AS t", query)).Scan(&n); e != nil {
+			return 0, "", fmt.Errorf("cannot count rows of %s: %v", query, e)
+		}
+		return n, "", nil
+	}
+	rows, e := db.Query(query)
+	if e != nil {
+		return 0, "", fmt.Errorf("cannot fingerprint %s: %v", query, e)
+	}
+	defer rows.Close()
+	columns, e := rows.Columns()
+	if e != nil {
+		return 0, "", e
+	}
+	values := make([]sql.RawBytes, len(columns))
+	dest := make([]interface{}, len(columns))
+	for i := range values {
+		dest[i] = &values[i]
+	}
+	var n int64
+	var sum [4]uint64
+	for rows.Next() {
+		if e := rows.Scan(dest...); e != nil {
+			return 0, "", e
+		}
+		h := sha256.New()
+		for _, v := range values {
+			// NOTE: prefix values by the lengths to tell ("ab", "c") from
+			// ("a", "bc"), and NULL from "".
+			if v == nil {
+				binary.Write(h, binary.BigEndian, int64(-1))
+			} else {
+				binary.Write(h, binary.BigEndian, int64(len(v)))
+				h.Write(v)
+			}
+		}
+		digest := h.Sum(nil)
+		for i := range sum {
+			sum[i] += binary.BigEndian.Uint64(digest[i*8:])
+		}
+		n++
+	}
+	if e := rows.Err(); e != nil {
+		return 0, "", e
+	}
+	fp := make([]byte, 32)
+	for i := range sum {
+		binary.BigEndian.PutUint64(fp[i*8:], sum[i])
+	}
+	return n, hex.EncodeToString(fp), nil
+}
+
+// SetLineage records the lineage in the model metadata file in the
+// working directory of the model.
+func (m *Model) SetLineage(l *Lineage) error {
+	metaPath := path.Join(m.workDir, modelMetaFileName)
+	data, e := ioutil.ReadFile(metaPath)
+	if e != nil {
+		return fmt.Errorf("cannot read model metadata: %v", e)
+	}
+	meta, e := simplejson.NewJson(data)
+	if e != nil {
+		return fmt.Errorf("model meta json parse error: %v", e)
+	}
+	meta.Set(lineageMetaKey, l)
+	if data, e = meta.Encode(); e != nil {
+		return e
+	}
+	if e := ioutil.WriteFile(metaPath, data, 0644); e != nil {
+		return e
+	}
+	m.Meta = meta
+	return nil
+}
+
+// Lineage returns the lineage recorded in the model metadata, or nil if
+// not recorded.
+func (m *Model) Lineage() *Lineage {
+	if m.Meta == nil {
+		return nil
+	}
+	j, ok := m.Meta.CheckGet(lineageMetaKey)
+	if !ok {
+		return nil
+	}
+	data, e := j.Encode()
+	if e != nil {
+		return nil
+	}
+	l := &Lineage{}
+	if e := json.Unmarshal(data, l); e != nil {
+		return nil
+	}
+	return l
+}
@@ -0,0 +1,82 @@
+// Copyright 2020 The SQLFlow Authors. All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"sqlflow.org/sqlflow/go/database"
+	"sqlflow.org/sqlflow/go/test"
+)
+
+func TestModelLineage(t *testing.T) {
+	a := assert.New(t)
+	cwd, e := ioutil.TempDir("/tmp", "sqlflow_models")
+	a.NoError(e)
+	defer os.RemoveAll(cwd)
+
+	m := New(cwd, "SELECT * FROM iris.train TO TRAIN DNNClassifier LABEL class INTO my_dnn")
+	a.Nil(m.Lineage())
+	a.Error(m.SetLineage(&Lineage{}))
+
+	a.NoError(ioutil.WriteFile(path.Join(cwd, modelMetaFileName), []byte(`{"original_sql": "SELECT 1"}`), 0644))
+	l := &Lineage{Inputs: []string{"iris.train"}, TrainRows: 110, TrainFingerprint: "abc", SQLFlowVersion: "develop", CodeGenerator: "tensorflow"}
+	a.NoError(m.SetLineage(l))
+	a.Equal(l, m.Lineage())
+
+	loaded, e := loadMeta(path.Join(cwd, modelMetaFileName))
+	a.NoError(e)
+	a.Equal("SELECT 1", loaded.TrainSelect)
+	a.Equal(l, loaded.Lineage())
+}
+
+func TestQualifyInputs(t *testing.T) {
+	a := assert.New(t)
+	a.Equal([]string{"iris.train", "db.t"}, QualifyInputs([]string{"train", "db.t"}, "iris"))
+	a.Equal([]string{"train"}, QualifyInputs([]string{"train"}, ""))
+}
+
+func TestFingerprint(t *testing.T) {
+	if test.GetEnv("SQLFLOW_TEST_DB", "mysql") != "mysql" {
+		t.Skip("only test fingerprint on MySQL")
+	}
+	a := assert.New(t)
+	db := database.GetTestingDBSingleton()
+
+	os.Setenv(EnvLineageFingerprint, "true")
+	n, fp, e := fingerprint(db, "SELECT * FROM iris.train ORDER BY sepal_length, sepal_width, petal_length, petal_width")
+	a.NoError(e)
+	a.Equal(int64(110), n)
+	a.Equal(64, len(fp))
+	n2, fp2, e := fingerprint(db, "SELECT * FROM iris.train ORDER BY sepal_length DESC, sepal_width DESC, petal_length DESC, petal_width DESC;")
+	a.NoError(e)
+	a.Equal(n, n2)
+	a.Equal(fp, fp2)
+	_, fp3, e := fingerprint(db, "SELECT * FROM iris.train LIMIT 109")
+	a.NoError(e)
+	a.NotEqual(fp, fp3)
+
+	// only count the rows by default
+	os.Unsetenv(EnvLineageFingerprint)
+	l, e := NewLineage(db, []string{"iris.train"}, "SELECT * FROM iris.train", "SELECT * FROM iris.test", "xgboost")
+	a.NoError(e)
+	a.Equal(int64(110), l.TrainRows)
+	a.Equal("", l.TrainFingerprint)
+	a.True(l.ValidationRows > 0)
+	a.Equal("xgboost", l.CodeGenerator)
+}
@@ -14,31 +14,36 @@
 package model
 
 import (
-	"bytes"
 	"context"
 	"fmt"
 	"github.com/aliyun/aliyun-oss-go-sdk/oss"
 	"io"
 	"io/ioutil"
 	"os"
-	"os/exec"
 	"path"
 	"path/filepath"
+	"regexp"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/bitly/go-simplejson"
 	"google.golang.org/grpc"
 	"sqlflow.org/sqlflow/go/database"
+	"sqlflow.org/sqlflow/go/metrics"
 
 	pb "sqlflow.org/sqlflow/go/proto"
 	"sqlflow.org/sqlflow/go/sqlfs"
+	"sqlflow.org/sqlflow/go/tar"
+	"sqlflow.org/sqlflow/go/tracing"
 )
 
 const (
 	// BucketName is the OSS bucket to save trained models
 	BucketName        = "sqlflow-models"
 	modelMetaFileName = "model_meta.json"
+	// modelTarName is the tarball name in a model directory saved to file://
+	modelTarName = "model"
 )
 
 const (
@@ -55,6 +60,9 @@ type Model struct {
 	workDir     string           // We don't expose and gob workDir; instead we tar it.
 	TrainSelect string           // TrainSelect is gob-encoded during I/O.
 	Meta        *simplejson.Json // Meta json object
+	Version     int64            // Version is set after saving to a database, see saveDBVersion.
+	Size        int64            // Size of the model tarball, set after saving.
+	StorageURI  string           // StorageURI is where the model is saved, set after saving.
 }
 
 // New an empty model.
@@ -74,32 +82,89 @@ func (m *Model) GetMetaAsString(key string) string {
 
 // Save all files in workDir as a tarball to a filesystem or sqlfs.
 func (m *Model) Save(modelURI string, session *pb.Session) error {
+	_, span := tracing.StartWithTraceparent(session.GetTraceparent(), "model.Save")
+	span.SetAttribute("storage", storageOf(modelURI))
+	start := time.Now()
+	e := m.save(modelURI, session)
+	metrics.ObserveModel("save", storageOf(modelURI), start, m.Size, e)
+	span.SetAttribute("bytes", m.Size)
+	span.End(e)
+	return e
+}
+
+func (m *Model) save(modelURI string, session *pb.Session) error {
 	if strings.Contains(modelURI, "://") {
+		m.StorageURI = modelURI
 		uriParts := strings.Split(modelURI, "://")
-		if len(uriParts) == 2 && uriParts[0] == "oss" {
-			return fmt.Errorf("save model to oss is not supported now")
+		if len(uriParts) == 2 && uriParts[0] == "file" {
+			return m.saveDir(uriParts[1])
+		}
+		scheme, bucket, key, e := splitStorageURI(modelURI)
+		if e != nil {
+			return e
 		}
-		return fmt.Errorf("error modelURI format: %s", modelURI)
+		s, e := NewStorage(scheme, bucket)
+		if e != nil {
+			return e
+		}
+		return m.saveStorage(s, key)
 	}
 
-	return m.saveDB(session.DbConnStr, modelURI, session)
+	return m.saveDBVersion(session.DbConnStr, modelURI, session)
 }
 
 // Load unzip a saved model to a directory on the local filesystem.
 // When dst=="", we do not unzip model data, just extract the model meta
 func Load(modelURI, dst string, db *database.DB) (*Model, error) {
+	return LoadContext(context.Background(), modelURI, dst, db)
+}
+
+// LoadContext is Load in the trace of ctx.
+func LoadContext(ctx context.Context, modelURI, dst string, db *database.DB) (*Model, error) {
+	_, span := tracing.Start(ctx, "model.Load")
+	span.SetAttribute("storage", storageOf(modelURI))
+	start := time.Now()
+	m, e := load(modelURI, dst, db)
+	metrics.ObserveModel("load", storageOf(modelURI), start, 0, e)
+	span.End(e)
+	return m, e
+}
+
+// storageOf returns the storage of modelURI in the metrics, which is the
+// scheme like file or oss, zoo for the model zoo, or db.
+func storageOf(modelURI string) string {
+	if i := strings.Index(modelURI, "://"); i >= 0 {
+		return modelURI[:i]
+	}
+	if strings.Contains(modelURI, "/") {
+		return "zoo"
+	}
+	return "db"
+}
+
+func load(modelURI, dst string, db *database.DB) (*Model, error) {
 	// FIXME(typhoonzero): unify arguments with save, use session,
 	// so that can pass oss credentials too.
 	if strings.Contains(modelURI, "://") {
 		uriParts := strings.Split(modelURI, "://")
 		if len(uriParts) == 2 {
-			// oss:// or file://
+			// file://, s3:// or oss://
 			if uriParts[0] == "file" {
+				if fi, e := os.Stat(uriParts[1]); e == nil && fi.IsDir() {
+					return loadDir(uriParts[1], dst)
+				}
 				dir, file := path.Split(uriParts[1])
 				return loadTar(dir, file, dst)
-			} else if uriParts[0] == "oss" {
-				return nil, fmt.Errorf("load model from oss is not supported now")
 			}
+			scheme, bucket, key, e := splitStorageURI(modelURI)
+			if e != nil {
+				return nil, e
+			}
+			s, e := NewStorage(scheme, bucket)
+			if e != nil {
+				return nil, e
+			}
+			return loadStorage(s, key, dst)
 		} else {
 			return nil, fmt.Errorf("error modelURI format: %s", modelURI)
 		}
@@ -178,17 +243,6 @@ func loadModelFromZoo(modelURI, dst string) (*Model, error) {
 	return loadTar(path.Dir(tmpFile.Name()), path.Base(tmpFile.Name()), dst)
 }
 
-// untarBuf extracts a tar.gz buffer from stdin and write the extracted files to dst
-func untarBuf(buf bytes.Buffer, dst string) error {
-	cmd := exec.Command("tar", "xzf", "-", "-C", dst)
-	cmd.Stdin = &buf
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		return fmt.Errorf("tar %v", string(output))
-	}
-	return nil
-}
-
 // saveDB creates a sqlfs table if it doesn't yet exist, and writes the
 // train select statement into the table, followed by the tar-gzipped
 // SQLFlow working directory, which contains the TensorFlow working
@@ -206,19 +260,22 @@ func (m *Model) saveDB(connStr, table string, session *pb.Session) (e error) {
 	}
 	defer sqlf.Close()
 
-	// model and its metadata are both zipped into a tarball
-	cmd := exec.Command("tar", "czf", "-", "-C", m.workDir, ".")
-	cmd.Stdout = sqlf
-	var errBuf bytes.Buffer
-	cmd.Stderr = &errBuf
-
-	if e := cmd.Run(); e != nil {
-		return fmt.Errorf("tar stderr: %v\ntar cmd %v", errBuf.String(), e)
+	// model and its metadata are both zipped into a tarball, which is
+	// checksummed while it is written
+	w, e := newChecksumWriter(sqlf, modelHMACKey())
+	if e != nil {
+		return fmt.Errorf("cannot save model to %s: %v", table, e)
+	}
+	if e := tar.Compress(m.workDir, w); e != nil {
+		return fmt.Errorf("cannot save model to %s: %v", table, e)
+	}
+	if e := w.Close(); e != nil {
+		return fmt.Errorf("cannot save model to %s: %v", table, e)
 	}
-
 	if e := sqlf.Close(); e != nil {
 		return fmt.Errorf("close sqlfs error: %v", e)
 	}
+	m.Size, m.StorageURI = w.n, table
 	return nil
 }
 
@@ -246,13 +303,8 @@ func (m *Model) SaveDBExperimental(connStr, table string, session *pb.Session) (
 	sqlf.Write([]byte(metaJSONStr))
 
 	// model and its metadata are both zipped into a tarball
-	cmd := exec.Command("tar", "czf", "-", "-C", m.workDir, ".")
-	cmd.Stdout = sqlf
-	var errBuf bytes.Buffer
-	cmd.Stderr = &errBuf
-
-	if e := cmd.Run(); e != nil {
-		return fmt.Errorf("tar stderr: %v\ntar cmd %v", errBuf.String(), e)
+	if e := tar.Compress(m.workDir, sqlf); e != nil {
+		return fmt.Errorf("cannot save model to %s: %v", table, e)
 	}
 
 	if e := sqlf.Close(); e != nil {
@@ -264,60 +316,159 @@ func (m *Model) SaveDBExperimental(connStr, table string, session *pb.Session) (
 func (m *Model) saveTar(modelDir, save string) (string, error) {
 	save = strings.TrimSuffix(save, ".tar.gz")
 	modelFile := filepath.Join(modelDir, save+".tar.gz")
-	cmd := exec.Command("tar", "czf", modelFile, "-C", m.workDir, ".")
-	if err := cmd.Run(); err != nil {
+	f, err := os.Create(modelFile)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	if err := tar.Compress(m.workDir, f); err != nil {
 		return "", err
 	}
-	return modelFile, nil
+	return modelFile, f.Close()
+}
+
+// saveDir writes the model tarball and the model metadata to the directory
+// dir on the local filesystem.
+func (m *Model) saveDir(dir string) error {
+	if e := os.MkdirAll(dir, 0755); e != nil {
+		return fmt.Errorf("cannot create model directory %s: %v", dir, e)
+	}
+	tarball, e := m.saveTar(dir, modelTarName)
+	if e != nil {
+		return fmt.Errorf("cannot save model to %s: %v", dir, e)
+	}
+	if fi, e := os.Stat(tarball); e == nil {
+		m.Size = fi.Size()
+	}
+	// NOTE: keep a copy of the metadata out of the tarball, so that loading
+	// the metadata doesn't need to unzip the model.
+	meta, e := ioutil.ReadFile(path.Join(m.workDir, modelMetaFileName))
+	if os.IsNotExist(e) {
+		return nil
+	}
+	if e != nil {
+		return e
+	}
+	return ioutil.WriteFile(path.Join(dir, modelMetaFileName), meta, 0644)
+}
+
+// loadDir loads a model saved by saveDir. It only loads the model metadata
+// if dst is "".
+func loadDir(dir, dst string) (*Model, error) {
+	if dst != "" {
+		return loadTar(dir, modelTarName, dst)
+	}
+	metaPath := path.Join(dir, modelMetaFileName)
+	if _, e := os.Stat(metaPath); e == nil {
+		return loadMeta(metaPath)
+	}
+	return ExtractMetaFromTarball(filepath.Join(dir, modelTarName+".tar.gz"))
 }
 
 func loadTar(modelDir, save, dst string) (*Model, error) {
 	save = strings.TrimSuffix(save, ".tar.gz")
 	tarFile := filepath.Join(modelDir, save+".tar.gz")
-	cmd := exec.Command("tar", "zxf", tarFile, "-C", dst)
-	if e := cmd.Run(); e != nil {
-		return nil, fmt.Errorf("unzip tar file %s failed", modelDir)
+	f, e := os.Open(tarFile)
+	if e != nil {
+		return nil, e
+	}
+	defer f.Close()
+	return untar(f, dst)
+}
+
+// untar extracts the model tarball in r to dst and loads the model metadata.
+// The tarball is extracted to a staging directory in dst first, and moved
+// to dst only if the checksum of a model saved in sqlfs is verified, so
+// that a corrupted or tampered model is never extracted.
+func untar(r io.Reader, dst string) (*Model, error) {
+	if e := os.MkdirAll(dst, 0755); e != nil {
+		return nil, e
+	}
+	staging, e := ioutil.TempDir(dst, ".sqlflow_untar")
+	if e != nil {
+		return nil, e
+	}
+	defer os.RemoveAll(staging)
+	e = tar.Uncompress(r, staging)
+	// NOTE: read r to EOF to verify the checksum of a model saved in
+	// sqlfs, which tells a corrupted model more clearly than unzipping.
+	if _, ve := io.Copy(ioutil.Discard, r); ve != nil {
+		return nil, ve
+	}
+	if e != nil {
+		return nil, fmt.Errorf("cannot unzip model: %v", e)
+	}
+	files, e := ioutil.ReadDir(staging)
+	if e != nil {
+		return nil, e
+	}
+	for _, f := range files {
+		target := filepath.Join(dst, f.Name())
+		if e := os.RemoveAll(target); e != nil {
+			return nil, e
+		}
+		if e := os.Rename(filepath.Join(staging, f.Name()), target); e != nil {
+			return nil, e
+		}
 	}
 	return loadMeta(path.Join(dst, modelMetaFileName))
 }
 
-// loadModelFromDB reads from the given sqlfs table for the train select
-// statement, and unzip the SQLFlow working directory, which contains
+// loadModelFromDB reads from the sqlfs table of the given model, which could
+// be a version like my_model@3, for the train select statement, and unzip the SQLFlow working directory, which contains
 // the TensorFlow model, into directory cwd if cwd is not "".
-func loadModelFromDB(db *database.DB, table, cwd string) (*Model, error) {
-	unzipModel := cwd != ""
+func loadModelFromDB(db *database.DB, modelName, cwd string) (*Model, error) {
+	r, err := openDBModel(db, modelName)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
 	if cwd == "" {
-		var err error
-		if cwd, err = ioutil.TempDir("/tmp", "sqlflow_models"); err != nil {
-			return nil, err
-		}
-		defer os.RemoveAll(cwd)
+		return extractMeta(r)
 	}
-	tarFile, err := DumpDBModel(db, table, cwd)
+	return untar(r, cwd)
+}
+
+// openDBModel opens the sqlfs table of the given model for reading the
+// model tarball, which is verified by the checksum on reaching EOF.
+func openDBModel(db *database.DB, modelName string) (io.ReadCloser, error) {
+	table, err := ResolveModelTable(db, modelName)
 	if err != nil {
 		return nil, err
 	}
-	if !unzipModel {
-		return ExtractMetaFromTarball(tarFile, cwd)
+	sqlf, err := sqlfs.Open(db.DB, table, 32)
+	if err != nil {
+		return nil, fmt.Errorf("Can't open sqlfs %s, %v", table, err)
+	}
+	r, err := newChecksumReader(sqlf, modelHMACKey())
+	if err != nil {
+		sqlf.Close()
+		return nil, fmt.Errorf("Can't load model %s: %v", modelName, err)
 	}
-	return loadTar(path.Dir(tarFile), path.Base(tarFile), cwd)
+	return struct {
+		io.Reader
+		io.Closer
+	}{r, sqlf}, nil
 }
 
 // DumpDBModel dumps a model tarball from database to local
-// file system and return the file name
-func DumpDBModel(db *database.DB, table, cwd string) (string, error) {
-	sqlf, err := sqlfs.Open(db.DB, table, 32)
+// file system and return the file name. modelName could be like
+// my_model@3 to dump a specific version.
+func DumpDBModel(db *database.DB, modelName, cwd string) (string, error) {
+	r, err := openDBModel(db, modelName)
 	if err != nil {
-		return "", fmt.Errorf("Can't open sqlfs %s, %v", table, err)
+		return "", err
 	}
-	defer sqlf.Close()
+	defer r.Close()
 	fileName := filepath.Join(cwd, "model_dump.tar.gz")
 	file, err := os.Create(fileName)
 	if err != nil {
 		return "", fmt.Errorf("Can't careate model file: %v", err)
 	}
-	if _, err = io.Copy(file, sqlf); err != nil {
-		return "", fmt.Errorf("Can't dump model to local file")
+	defer file.Close()
+	if _, err = io.Copy(file, r); err != nil {
+		os.Remove(fileName)
+		return "", fmt.Errorf("Can't dump model %s to local file: %v", modelName, err)
 	}
 	return fileName, nil
 }
@@ -325,22 +476,37 @@ func DumpDBModel(db *database.DB, table, cwd string) (string, error) {
 // ExtractMetaFromTarball extract metadata from given tarball
 // and return the metadata json string. This function do not
 // unzip the whole model tarball
-func ExtractMetaFromTarball(tarballName, cwd string) (*Model, error) {
+func ExtractMetaFromTarball(tarballName string) (*Model, error) {
 	if !strings.HasSuffix(tarballName, ".tar.gz") {
 		return nil, fmt.Errorf("given file should be a .tar.gz file")
 	}
-	cmd := exec.Command("tar", "xpf", tarballName, "-C", cwd, "./"+modelMetaFileName)
-	out, err := cmd.CombinedOutput()
+	f, err := os.Open(tarballName)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return extractMeta(f)
+}
+
+// extractMeta reads the model metadata from the model tarball in r.
+func extractMeta(r io.Reader) (*Model, error) {
+	meta, err := tar.ExtractFile(r, modelMetaFileName)
 	if err != nil {
-		return nil, fmt.Errorf("can't unzip model tarball %s: %s, %v", tarballName, out, err)
+		return nil, fmt.Errorf("can't read model metadata from tarball: %v", err)
+	}
+	model := &Model{}
+	if err = decodeMeta(model, meta); err != nil {
+		return nil, err
 	}
-	metaPath := path.Join(cwd, modelMetaFileName)
-	defer os.Remove(metaPath)
-	return loadMeta(metaPath)
+	return model, nil
 }
 
 // DumpDBModelExperimental returns the dumped model tar file name and model meta (JSON serialized).
 func DumpDBModelExperimental(db *database.DB, table, cwd string) (string, *Model, error) {
+	table, err := ResolveModelTable(db, table)
+	if err != nil {
+		return "", nil, err
+	}
 	sqlf, err := sqlfs.Open(db.DB, table, 32)
 	if err != nil {
 		return "", nil, fmt.Errorf("Can't open sqlfs %s, %v", table, err)
@@ -443,5 +609,18 @@ func GetOSSModelPath(modelName string, session *pb.Session) (string, error) {
 	if userID == "" {
 		userID = "unknown"
 	}
-	return strings.Join([]string{projectName, userID, modelName}, "/"), nil
+	return strings.Join([]string{projectName, userID, OSSModelName(modelName)}, "/"), nil
+}
+
+var reNotOSSModelName = regexp.MustCompile(`[^a-zA-Z0-9_.-]+`)
+
+// OSSModelName returns the name of the directory on OSS where PAI jobs save
+// and load the model modelName. For model URIs like s3://bucket/path, whose
+// models are staged on OSS, it replaces the characters other than letters,
+// digits, '_', '.' and '-' with '_', e.g. s3_bucket_path.
+func OSSModelName(modelName string) string {
+	if !strings.Contains(modelName, "://") {
+		return modelName
+	}
+	return reNotOSSModelName.ReplaceAllString(modelName, "_")
 }
@@ -103,6 +103,32 @@ func TestModelDBStore(t *testing.T) {
 	a.Equal("SELECT * FROM iris.train where class!=2", model.GetMetaAsString("select"))
 }
 
+func TestModelDirStore(t *testing.T) {
+	a := assert.New(t)
+	ws, dst := mockModelDir(a)
+	defer os.RemoveAll(ws)
+	defer os.RemoveAll(dst)
+	dir, err := ioutil.TempDir("/tmp", "model_dir")
+	a.NoError(err)
+	defer os.RemoveAll(dir)
+
+	modelURI := "file://" + path.Join(dir, "my_boost_tree_model")
+	model := &Model{workDir: ws}
+	a.NoError(model.Save(modelURI, nil))
+	a.True(file.Exists(path.Join(dir, "my_boost_tree_model", modelTarName+".tar.gz")))
+	a.True(file.Exists(path.Join(dir, "my_boost_tree_model", modelMetaFileName)))
+
+	model, err = Load(modelURI, dst, nil)
+	a.NoError(err)
+	a.True(file.Exists(path.Join(dst, "model.txt")))
+	a.Equal("tf.estimator.BoostedTreesClassifier", model.GetMetaAsString("estimator"))
+
+	// only load meta
+	model, err = Load(modelURI, "", nil)
+	a.NoError(err)
+	a.Equal("SELECT * FROM iris.train where class!=2", model.GetMetaAsString("select"))
+}
+
 func TestDumpDBModelExperimental(t *testing.T) {
 	a := assert.New(t)
 	metaLen := len(modelMeta)
@@ -137,3 +163,9 @@ func TestDumpDBModelExperimental(t *testing.T) {
 	a.Equal(ws+"/model_dump.tar.gz", fn)
 	a.Equal("tf.estimator.BoostedTreesClassifier", model.GetMetaAsString("estimator"))
 }
+
+func TestOSSModelName(t *testing.T) {
+	a := assert.New(t)
+	a.Equal("my_dnn_model", OSSModelName("my_dnn_model"))
+	a.Equal("s3_models_team_my_dnn.v1", OSSModelName("s3://models/team/my_dnn.v1"))
+}
@@ -0,0 +1,246 @@
+// Copyright 2020 The SQLFlow Authors. All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// EnvS3Endpoint is the environment variable of the S3 endpoint, e.g.
+	// "https://s3.us-east-1.amazonaws.com" or "http://127.0.0.1:9000" for
+	// a local MinIO.
+	EnvS3Endpoint = "SQLFLOW_S3_ENDPOINT"
+	// EnvS3AccessKey is the environment variable of the S3 access key.
+	EnvS3AccessKey = "SQLFLOW_S3_ACCESS_KEY"
+	// EnvS3SecretKey is the environment variable of the S3 secret key.
+	EnvS3SecretKey = "SQLFLOW_S3_SECRET_KEY"
+	// EnvS3Region is the environment variable of the S3 region, the
+	// default is "us-east-1".
+	EnvS3Region = "SQLFLOW_S3_REGION"
+
+	s3UnsignedPayload = "UNSIGNED-PAYLOAD"
+	s3TimeFormat      = "20060102T150405Z"
+	s3DateFormat      = "20060102"
+)
+
+// S3Storage is the Storage of a bucket of S3 or an S3-compatible service
+// like MinIO. It accesses objects by the path-style URL
+// <endpoint>/<bucket>/<key> and signs requests with AWS Signature V4.
+type S3Storage struct {
+	Endpoint  string
+	Bucket    string
+	AccessKey string
+	SecretKey string
+	Region    string
+	client    *http.Client
+}
+
+// NewS3Storage returns the S3Storage of the bucket.
+func NewS3Storage(endpoint, bucket, accessKey, secretKey, region string) *S3Storage {
+	if region == "" {
+		region = "us-east-1"
+	}
+	return &S3Storage{
+		Endpoint:  strings.TrimSuffix(endpoint, "/"),
+		Bucket:    bucket,
+		AccessKey: accessKey,
+		SecretKey: secretKey,
+		Region:    region,
+		client:    &http.Client{},
+	}
+}
+
+// NewS3StorageFromEnv returns the S3Storage of the bucket configured by the
+// environment variables SQLFLOW_S3_*.
+func NewS3StorageFromEnv(bucket string) (*S3Storage, error) {
+	ep := os.Getenv(EnvS3Endpoint)
+	ak := os.Getenv(EnvS3AccessKey)
+	sk := os.Getenv(EnvS3SecretKey)
+	if ep == "" || ak == "" || sk == "" {
+		return nil, fmt.Errorf("should define %s, %s, %s to save models to S3", EnvS3Endpoint, EnvS3AccessKey, EnvS3SecretKey)
+	}
+	return NewS3Storage(ep, bucket, ak, sk, os.Getenv(EnvS3Region)), nil
+}
+
+// Put implements Storage.
+func (s *S3Storage) Put(key string, r io.Reader, size int64) error {
+	req, e := http.NewRequest(http.MethodPut, s.objectURL(key), r)
+	if e != nil {
+		return e
+	}
+	req.ContentLength = size
+	resp, e := s.do(req)
+	if e != nil {
+		return fmt.Errorf("cannot put s3://%s/%s: %v", s.Bucket, key, e)
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// Get implements Storage.
+func (s *S3Storage) Get(key string) (io.ReadCloser, error) {
+	req, e := http.NewRequest(http.MethodGet, s.objectURL(key), nil)
+	if e != nil {
+		return nil, e
+	}
+	resp, e := s.do(req)
+	if e != nil {
+		return nil, fmt.Errorf("cannot get s3://%s/%s: %v", s.Bucket, key, e)
+	}
+	return resp.Body, nil
+}
+
+func (s *S3Storage) objectURL(key string) string {
+	return fmt.Sprintf("%s/%s/%s", s.Endpoint, s3Escape(s.Bucket), s3Escape(key))
+}
+
+func (s *S3Storage) do(req *http.Request) (*http.Response, error) {
+	s.sign(req, time.Now().UTC())
+	resp, e := s.client.Do(req)
+	if e != nil {
+		return nil, e
+	}
+	if resp.StatusCode/100 != 2 {
+		defer resp.Body.Close()
+		body, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("%s: %s", resp.Status, body)
+	}
+	return resp, nil
+}
+
+// sign adds the AWS Signature V4 of req to its header. The payload is not
+// signed so that we could stream large models.
+func (s *S3Storage) sign(req *http.Request, t time.Time) {
+	req.Header.Set("x-amz-content-sha256", s3UnsignedPayload)
+	signV4(req, t, s3UnsignedPayload, s.AccessKey, s.SecretKey, s.Region, "s3")
+}
+
+// signV4 sets the x-amz-date and the Authorization header of req by AWS
+// Signature V4, which signs the host and all headers in req.Header, see
+// https://docs.aws.amazon.com/general/latest/gr/sigv4_signing.html
+func signV4(req *http.Request, t time.Time, payloadHash, accessKey, secretKey, region, service string) {
+	amzDate := t.Format(s3TimeFormat)
+	req.Header.Set("x-amz-date", amzDate)
+
+	host := req.Host
+	if host == "" {
+		host = req.URL.Host
+	}
+	headers := map[string]string{"host": host}
+	for k, v := range req.Header {
+		k = strings.ToLower(k)
+		if k == "authorization" {
+			continue
+		}
+		values := []string{}
+		for _, value := range v {
+			values = append(values, strings.Join(strings.Fields(value), " "))
+		}
+		headers[k] = strings.Join(values, ",")
+	}
+	names := []string{}
+	for k := range headers {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	canonicalHeaders := ""
+	for _, k := range names {
+		canonicalHeaders += k + ":" + headers[k] + "\n"
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	uri := req.URL.EscapedPath()
+	if uri == "" {
+		uri = "/"
+	}
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		uri,
+		canonicalQuery(req.URL.Query()),
+		canonicalHeaders,
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+	scope := strings.Join([]string{t.Format(s3DateFormat), region, service, "aws4_request"}, "/")
+	h := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := strings.Join([]string{"AWS4-HMAC-SHA256", amzDate, scope, hex.EncodeToString(h[:])}, "\n")
+	key := s3SigningKey(secretKey, t.Format(s3DateFormat), region, service)
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		accessKey, scope, signedHeaders, signature))
+}
+
+// canonicalQuery returns the query string sorted by the names and then the
+// values, which are escaped by s3Escape with '/' escaped too.
+func canonicalQuery(query url.Values) string {
+	escape := func(s string) string { return strings.ReplaceAll(s3Escape(s), "/", "%2F") }
+	params := [][2]string{}
+	for k, vs := range query {
+		for _, v := range vs {
+			params = append(params, [2]string{escape(k), escape(v)})
+		}
+	}
+	sort.Slice(params, func(i, j int) bool {
+		if params[i][0] != params[j][0] {
+			return params[i][0] < params[j][0]
+		}
+		return params[i][1] < params[j][1]
+	})
+	pairs := []string{}
+	for _, p := range params {
+		pairs = append(pairs, p[0]+"="+p[1])
+	}
+	return strings.Join(pairs, "&")
+}
+
+func s3SigningKey(secretKey, date, region, service string) []byte {
+	k := hmacSHA256([]byte("AWS4"+secretKey), date)
+	k = hmacSHA256(k, region)
+	k = hmacSHA256(k, service)
+	return hmacSHA256(k, "aws4_request")
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+// s3Escape escapes s as S3 requires, i.e., all bytes but the unreserved
+// characters and '/'.
+func s3Escape(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if 'A' <= c && c <= 'Z' || 'a' <= c && c <= 'z' || '0' <= c && c <= '9' ||
+			c == '-' || c == '_' || c == '.' || c == '~' || c == '/' {
+			b.WriteByte(c)
+		} else {
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,186 @@
+// Copyright 2020 The SQLFlow Authors. All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/argoproj/pkg/file"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeS3 is an in-memory S3 service accepting path-style PUT and GET.
+type fakeS3 struct {
+	m       sync.Mutex
+	objects map[string][]byte
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !verifyS3Signature(r, "ak", "sk", "us-east-1") {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+	f.m.Lock()
+	defer f.m.Unlock()
+	switch r.Method {
+	case http.MethodPut:
+		b, _ := ioutil.ReadAll(r.Body)
+		f.objects[r.URL.Path] = b
+	case http.MethodGet:
+		b, ok := f.objects[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write(b)
+	}
+}
+
+// verifyS3Signature signs the headers listed in the Authorization header of r
+// as the server does, and compares the signatures.
+func verifyS3Signature(r *http.Request, accessKey, secretKey, region string) bool {
+	auth := r.Header.Get("Authorization")
+	m := regexp.MustCompile(`SignedHeaders=([^,]+),`).FindStringSubmatch(auth)
+	if m == nil {
+		return false
+	}
+	signed, e := http.NewRequest(r.Method, r.URL.String(), nil)
+	if e != nil {
+		return false
+	}
+	signed.Host = r.Host
+	for _, h := range strings.Split(m[1], ";") {
+		if h != "host" {
+			signed.Header.Set(h, r.Header.Get(h))
+		}
+	}
+	t, e := time.Parse(s3TimeFormat, r.Header.Get("x-amz-date"))
+	if e != nil {
+		return false
+	}
+	signV4(signed, t, r.Header.Get("x-amz-content-sha256"), accessKey, secretKey, region, "s3")
+	return signed.Header.Get("Authorization") == auth
+}
+
+func TestSignV4(t *testing.T) {
+	a := assert.New(t)
+	emptySHA256 := "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+	cases := []struct {
+		url, service, secretKey string
+		t                       time.Time
+		header                  map[string]string
+		payloadHash             string
+		auth                    string
+	}{{
+		// get-vanilla in https://docs.aws.amazon.com/general/latest/gr/signature-v4-test-suite.html
+		"https://example.amazonaws.com/", "service", "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY",
+		time.Date(2015, 8, 30, 12, 36, 0, 0, time.UTC),
+		map[string]string{},
+		emptySHA256,
+		"AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, SignedHeaders=host;x-amz-date, " +
+			"Signature=5fa00fa31553b73ebf1942676e86291e8372ff2a2260956d9b8aae1d763fbf31",
+	}, {
+		// GET Object in https://docs.aws.amazon.com/AmazonS3/latest/API/sig-v4-header-based-auth.html
+		"https://examplebucket.s3.amazonaws.com/test.txt", "s3", "wJalrXUtnFEMI/K7MDENG/bPxRfiCYEXAMPLEKEY",
+		time.Date(2013, 5, 24, 0, 0, 0, 0, time.UTC),
+		map[string]string{"Range": "bytes=0-9", "x-amz-content-sha256": emptySHA256},
+		emptySHA256,
+		"AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20130524/us-east-1/s3/aws4_request, SignedHeaders=host;range;x-amz-content-sha256;x-amz-date, " +
+			"Signature=f0e8bdb87c964420e857bd35b5d6ed310bd44f0170aba48dd91039c6036bdb41",
+	}, {
+		// GET Bucket (List Objects) in the same document
+		"https://examplebucket.s3.amazonaws.com/?prefix=J&max-keys=2", "s3", "wJalrXUtnFEMI/K7MDENG/bPxRfiCYEXAMPLEKEY",
+		time.Date(2013, 5, 24, 0, 0, 0, 0, time.UTC),
+		map[string]string{"x-amz-content-sha256": emptySHA256},
+		emptySHA256,
+		"AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20130524/us-east-1/s3/aws4_request, SignedHeaders=host;x-amz-content-sha256;x-amz-date, " +
+			"Signature=34b48302e7b5fa45bde8084f4b7868a86f0a534bc59db6670ed5711ef69dc6f7",
+	}}
+	for _, c := range cases {
+		req, e := http.NewRequest(http.MethodGet, c.url, nil)
+		a.NoError(e)
+		for k, v := range c.header {
+			req.Header.Set(k, v)
+		}
+		signV4(req, c.t, c.payloadHash, "AKIDEXAMPLE", c.secretKey, "us-east-1", c.service)
+		a.Equal(c.auth, req.Header.Get("Authorization"))
+	}
+}
+
+func TestS3SigningKey(t *testing.T) {
+	a := assert.New(t)
+	// The example in https://docs.aws.amazon.com/general/latest/gr/signature-v4-examples.html
+	k := s3SigningKey("wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY", "20120215", "us-east-1", "iam")
+	a.Equal("f4780e2d9f65fa895f9c67b32ce1baf0b0d8a43505a000a1a9e090d414db404d", hex.EncodeToString(k))
+}
+
+func TestSplitStorageURI(t *testing.T) {
+	a := assert.New(t)
+	scheme, bucket, key, e := splitStorageURI("s3://models/team/my_dnn/")
+	a.NoError(e)
+	a.Equal("s3", scheme)
+	a.Equal("models", bucket)
+	a.Equal("team/my_dnn", key)
+	_, _, _, e = splitStorageURI("s3://models")
+	a.Error(e)
+	a.Equal("a%20b/c%2Bd.tar.gz", s3Escape("a b/c+d.tar.gz"))
+}
+
+func TestModelS3Store(t *testing.T) {
+	a := assert.New(t)
+	ws, dst := mockModelDir(a)
+	defer os.RemoveAll(ws)
+	defer os.RemoveAll(dst)
+
+	// A local MinIO could be used by setting SQLFLOW_S3_ENDPOINT etc.
+	if os.Getenv(EnvS3Endpoint) == "" {
+		srv := httptest.NewServer(&fakeS3{objects: map[string][]byte{}})
+		defer srv.Close()
+		os.Setenv(EnvS3Endpoint, srv.URL)
+		os.Setenv(EnvS3AccessKey, "ak")
+		os.Setenv(EnvS3SecretKey, "sk")
+		defer func() {
+			for _, env := range []string{EnvS3Endpoint, EnvS3AccessKey, EnvS3SecretKey} {
+				os.Unsetenv(env)
+			}
+		}()
+	}
+
+	modelURI := fmt.Sprintf("s3://sqlflow-models/%s/my_boost_tree_model", path.Base(ws))
+	model := &Model{workDir: ws}
+	a.NoError(model.Save(modelURI, nil))
+
+	model, err := Load(modelURI, dst, nil)
+	a.NoError(err)
+	a.True(file.Exists(path.Join(dst, "model.txt")))
+	a.Equal("tf.estimator.BoostedTreesClassifier", model.GetMetaAsString("estimator"))
+
+	model, err = Load(modelURI, "", nil)
+	a.NoError(err)
+	a.Equal("SELECT * FROM iris.train where class!=2", model.GetMetaAsString("select"))
+
+	_, err = Load(modelURI+"_not_exist", "", nil)
+	a.Error(err)
+}
@@ -0,0 +1,156 @@
+// Copyright 2020 The SQLFlow Authors. All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/aliyun/aliyun-oss-go-sdk/oss"
+)
+
+// Storage is an object storage to save models, like S3 and OSS.
+type Storage interface {
+	// Put writes size bytes read from r to the object key.
+	Put(key string, r io.Reader, size int64) error
+	// Get returns the content of the object key.
+	Get(key string) (io.ReadCloser, error)
+}
+
+// NewStorage returns the Storage of the bucket by the scheme of a model URI,
+// e.g. "s3" for s3://bucket/path.
+func NewStorage(scheme, bucket string) (Storage, error) {
+	switch scheme {
+	case "s3":
+		return NewS3StorageFromEnv(bucket)
+	case "oss":
+		return newOSSStorageFromEnv(bucket)
+	default:
+		return nil, fmt.Errorf("unsupported model storage %s", scheme)
+	}
+}
+
+// splitStorageURI splits a URI like s3://bucket/path into the scheme, the
+// bucket and the path.
+func splitStorageURI(uri string) (scheme, bucket, key string, e error) {
+	parts := strings.SplitN(uri, "://", 2)
+	if len(parts) != 2 {
+		return "", "", "", fmt.Errorf("error modelURI format: %s", uri)
+	}
+	bucketAndKey := strings.SplitN(parts[1], "/", 2)
+	if len(bucketAndKey) != 2 || bucketAndKey[0] == "" || strings.Trim(bucketAndKey[1], "/") == "" {
+		return "", "", "", fmt.Errorf("model URI should be like %s://bucket/path, got %s", parts[0], uri)
+	}
+	return parts[0], bucketAndKey[0], strings.Trim(bucketAndKey[1], "/"), nil
+}
+
+// saveStorage writes the model tarball and the model metadata under the
+// directory key of the storage, with the same layout as saveDir.
+func (m *Model) saveStorage(s Storage, key string) error {
+	dir, e := ioutil.TempDir("/tmp", "sqlflow_models")
+	if e != nil {
+		return e
+	}
+	defer os.RemoveAll(dir)
+	tarball, e := m.saveTar(dir, modelTarName)
+	if e != nil {
+		return fmt.Errorf("cannot pack model: %v", e)
+	}
+	if fi, e := os.Stat(tarball); e == nil {
+		m.Size = fi.Size()
+	}
+	if e := putFile(s, path.Join(key, modelTarName+".tar.gz"), tarball); e != nil {
+		return e
+	}
+	meta := filepath.Join(m.workDir, modelMetaFileName)
+	if _, e := os.Stat(meta); os.IsNotExist(e) {
+		return nil
+	}
+	return putFile(s, path.Join(key, modelMetaFileName), meta)
+}
+
+// loadStorage loads a model saved by saveStorage. It only loads the model
+// metadata if dst is "".
+func loadStorage(s Storage, key, dst string) (*Model, error) {
+	if dst == "" {
+		r, e := s.Get(path.Join(key, modelMetaFileName))
+		if e != nil {
+			return nil, e
+		}
+		defer r.Close()
+		meta, e := ioutil.ReadAll(r)
+		if e != nil {
+			return nil, fmt.Errorf("cannot read model metadata: %v", e)
+		}
+		m := &Model{}
+		if e := decodeMeta(m, meta); e != nil {
+			return nil, e
+		}
+		return m, nil
+	}
+	r, e := s.Get(path.Join(key, modelTarName+".tar.gz"))
+	if e != nil {
+		return nil, e
+	}
+	defer r.Close()
+	return untar(r, dst)
+}
+
+func putFile(s Storage, key, fn string) error {
+	f, e := os.Open(fn)
+	if e != nil {
+		return e
+	}
+	defer f.Close()
+	fi, e := f.Stat()
+	if e != nil {
+		return e
+	}
+	return s.Put(key, f, fi.Size())
+}
+
+type ossStorage struct {
+	bucket *oss.Bucket
+}
+
+func newOSSStorageFromEnv(bucket string) (*ossStorage, error) {
+	ak := os.Getenv("SQLFLOW_OSS_AK")
+	sk := os.Getenv("SQLFLOW_OSS_SK")
+	ep := os.Getenv("SQLFLOW_OSS_MODEL_ENDPOINT")
+	if ak == "" || sk == "" || ep == "" {
+		return nil, fmt.Errorf("should define SQLFLOW_OSS_MODEL_ENDPOINT, SQLFLOW_OSS_AK, SQLFLOW_OSS_SK to save models to OSS")
+	}
+	cli, e := oss.New(ep, ak, sk)
+	if e != nil {
+		return nil, e
+	}
+	b, e := cli.Bucket(bucket)
+	if e != nil {
+		return nil, e
+	}
+	return &ossStorage{bucket: b}, nil
+}
+
+func (s *ossStorage) Put(key string, r io.Reader, size int64) error {
+	return s.bucket.PutObject(key, r, oss.ContentLength(size))
+}
+
+func (s *ossStorage) Get(key string) (io.ReadCloser, error) {
+	return s.bucket.GetObject(key)
+}
@@ -0,0 +1,329 @@
+// Copyright 2020 The SQLFlow Authors. All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"sqlflow.org/sqlflow/go/database"
+	pb "sqlflow.org/sqlflow/go/proto"
+)
+
+const (
+	// EnvVersionRetention is the environment variable specifying how many
+	// versions of each model are kept. The default 0 keeps all versions.
+	EnvVersionRetention = "SQLFLOW_MODEL_VERSION_RETENTION"
+	// LatestVersion refers to the latest version of a model, e.g. my_model@latest.
+	LatestVersion = "latest"
+
+	// VersionRegistryTable records the versions of the models in a database.
+	VersionRegistryTable = "sqlflow_model_versions"
+
+	maxReserveAttempts  = 10
+	mysqlDuplicateEntry = 1062
+)
+
+// ParseVersion splits a model name like "my_model@3" into the name and the
+// version. The version is "" if not specified.
+func ParseVersion(modelName string) (name, version string, e error) {
+	idx := strings.LastIndex(modelName, "@")
+	if idx < 0 {
+		return modelName, "", nil
+	}
+	name, version = modelName[:idx], modelName[idx+1:]
+	if version == LatestVersion {
+		return name, version, nil
+	}
+	if v, e := strconv.ParseInt(version, 10, 64); e != nil || v <= 0 {
+		return "", "", fmt.Errorf("model version should be a positive integer or %s, got %s", LatestVersion, modelName)
+	}
+	return name, version, nil
+}
+
+// versionTable returns the sqlfs table of the version v of the model.
+func versionTable(name string, v int64) string {
+	return fmt.Sprintf("%s__v%d", name, v)
+}
+
+// versionRegistry returns the version registry table in the database of
+// the model.
+func versionRegistry(name string) string {
+	if idx := strings.LastIndex(name, "."); idx >= 0 {
+		return name[:idx+1] + VersionRegistryTable
+	}
+	return VersionRegistryTable
+}
+
+// createVersionRegistry creates the version registry, whose rows are unique
+// by the model name and the version on MySQL. A version with an empty table
+// name is reserved by a saving in progress, see reserveVersion.
+func createVersionRegistry(db *database.DB, registry string) error {
+	str, _, datetime, e := database.ColumnTypes(db.DriverName)
+	if e != nil {
+		return e
+	}
+	key := ""
+	if db.DriverName == "mysql" {
+		key = ", PRIMARY KEY (model_name, version)"
+	}
+	stmt := fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (model_name %s, version BIGINT, table_name %s, created_at %s%s)",
+		registry, str, str, datetime, key)
+	if _, e := db.Exec(stmt); e != nil {
+		return fmt.Errorf("failed executing %s: %v", stmt, e)
+	}
+	return nil
+}
+
+// ListVersions returns the saved versions of the model in ascending order.
+func ListVersions(db *database.DB, name string) ([]int64, error) {
+	rows, e := db.Query(fmt.Sprintf("SELECT DISTINCT version FROM %s WHERE model_name=%s AND table_name<>''",
+		versionRegistry(name), database.QuoteString(name)))
+	if e != nil {
+		return nil, e
+	}
+	defer rows.Close()
+	versions := []int64{}
+	for rows.Next() {
+		var v int64
+		if e := rows.Scan(&v); e != nil {
+			return nil, e
+		}
+		versions = append(versions, v)
+	}
+	sort.Slice(versions, func(i, j int) bool { return versions[i] < versions[j] })
+	return versions, rows.Err()
+}
+
+// reserveVersion allocates the next version of the model by inserting it
+// into the version registry before writing the model. Concurrent savings
+// of the same model get different versions on MySQL, where the insertion
+// fails if another saving has reserved the version. Hive and MaxCompute
+// have no unique constraints, so the reservation only narrows the race to
+// the insertion.
+func reserveVersion(db *database.DB, name string) (int64, error) {
+	registry := versionRegistry(name)
+	for i := 0; i < maxReserveAttempts; i++ {
+		var latest sql.NullInt64
+		query := fmt.Sprintf("SELECT MAX(version) FROM %s WHERE model_name=%s", registry, database.QuoteString(name))
+		if e := db.QueryRow(query).Scan(&latest); e != nil {
+			return 0, fmt.Errorf("failed executing %s: %v", query, e)
+		}
+		v := latest.Int64 + 1
+		e := insertVersion(db, name, v, "")
+		if e == nil {
+			return v, nil
+		}
+		if me, ok := e.(*mysql.MySQLError); !ok || me.Number != mysqlDuplicateEntry {
+			return 0, fmt.Errorf("cannot reserve version %d of model %s: %v", v, name, e)
+		}
+	}
+	return 0, fmt.Errorf("cannot reserve a version of model %s after %d attempts", name, maxReserveAttempts)
+}
+
+// commitVersion records the sqlfs table of the reserved version v.
+func commitVersion(db *database.DB, name string, v int64, table string) error {
+	if db.DriverName != "mysql" {
+		// Hive and MaxCompute don't support UPDATE, ListVersions ignores
+		// the reservation.
+		return insertVersion(db, name, v, table)
+	}
+	stmt := fmt.Sprintf("UPDATE %s SET table_name=%s, created_at=%s WHERE model_name=%s AND version=%d",
+		versionRegistry(name), database.QuoteString(table), database.TimeLiteral(db.DriverName, time.Now()),
+		database.QuoteString(name), v)
+	if _, e := db.Exec(stmt); e != nil {
+		return fmt.Errorf("failed executing %s: %v", stmt, e)
+	}
+	return nil
+}
+
+func insertVersion(db *database.DB, name string, v int64, table string) error {
+	values := fmt.Sprintf("%s, %d, %s, %s", database.QuoteString(name), v, database.QuoteString(table),
+		database.TimeLiteral(db.DriverName, time.Now()))
+	var stmt string
+	if db.DriverName == "mysql" {
+		stmt = fmt.Sprintf("INSERT INTO %s VALUES (%s)", versionRegistry(name), values)
+	} else {
+		stmt = fmt.Sprintf("INSERT INTO TABLE %s SELECT %s", versionRegistry(name), values)
+	}
+	_, e := db.Exec(stmt)
+	return e
+}
+
+// ResolveModelTable returns the sqlfs table of a model name like
+// "my_model", "my_model@latest" or "my_model@3". A model name without a
+// version refers to the latest version, or the table of the same name if
+// the model is saved before versioning.
+func ResolveModelTable(db *database.DB, modelName string) (string, error) {
+	name, version, e := ParseVersion(modelName)
+	if e != nil {
+		return "", e
+	}
+	versions, e := ListVersions(db, name)
+	if version == "" && (e != nil || len(versions) == 0) {
+		return name, nil
+	}
+	if e != nil {
+		return "", fmt.Errorf("cannot list versions of model %s: %v", name, e)
+	}
+	if len(versions) == 0 {
+		return "", fmt.Errorf("model %s has no versions", name)
+	}
+	if version == "" || version == LatestVersion {
+		return versionTable(name, versions[len(versions)-1]), nil
+	}
+	v, _ := strconv.ParseInt(version, 10, 64)
+	for _, x := range versions {
+		if x == v {
+			return versionTable(name, v), nil
+		}
+	}
+	return "", fmt.Errorf("model %s has no version %d, available versions: %v", name, v, versions)
+}
+
+// saveDBVersion saves the model as a new version of the model name, and
+// removes the old versions exceeding SQLFLOW_MODEL_VERSION_RETENTION.
+func (m *Model) saveDBVersion(connStr, name string, session *pb.Session) error {
+	if _, version, e := ParseVersion(name); e != nil || version != "" {
+		return fmt.Errorf("cannot save model to %s, please specify the model name without a version", name)
+	}
+	db, e := database.OpenAndConnectDB(connStr)
+	if e != nil {
+		return e
+	}
+	defer db.Close()
+	if e := createVersionRegistry(db, versionRegistry(name)); e != nil {
+		return e
+	}
+	v, e := reserveVersion(db, name)
+	if e != nil {
+		return e
+	}
+	table := versionTable(name, v)
+	if e := m.saveDB(connStr, table, session); e != nil {
+		// release the reservation, so that the version is not left pending
+		RemoveVersions(db, name, []int64{v})
+		return e
+	}
+	if e := commitVersion(db, name, v, table); e != nil {
+		return e
+	}
+	m.Version = v
+
+	retention, e := versionRetention()
+	if e != nil {
+		return e
+	}
+	versions, e := ListVersions(db, name)
+	if e != nil {
+		return e
+	}
+	return removeOldVersions(db, name, versions, retention)
+}
+
+func versionRetention() (int, error) {
+	s := os.Getenv(EnvVersionRetention)
+	if s == "" {
+		return 0, nil
+	}
+	n, e := strconv.Atoi(s)
+	if e != nil || n < 0 {
+		return 0, fmt.Errorf("%s should be a non-negative integer, got %q", EnvVersionRetention, s)
+	}
+	return n, nil
+}
+
+// removeOldVersions drops the versions but the latest retention ones, and
+// removes them from the model catalog.
+func removeOldVersions(db *database.DB, name string, versions []int64, retention int) error {
+	if retention == 0 || len(versions) <= retention {
+		return nil
+	}
+	old := versions[:len(versions)-retention]
+	if e := RemoveVersions(db, name, old); e != nil {
+		return e
+	}
+	// NOTE: the model catalog is an index of the models, failing to update
+	// it should not fail the saving.
+	DeleteCatalog(db, CatalogTable(), name, old)
+	return nil
+}
+
+// RemoveVersions drops the sqlfs tables of the versions of the model and
+// removes them from the version registry.
+func RemoveVersions(db *database.DB, name string, versions []int64) error {
+	if len(versions) == 0 {
+		return nil
+	}
+	vs := []string{}
+	for _, v := range versions {
+		stmt := fmt.Sprintf("DROP TABLE IF EXISTS %s", versionTable(name, v))
+		if _, e := db.Exec(stmt); e != nil {
+			return fmt.Errorf("failed executing %s: %v", stmt, e)
+		}
+		vs = append(vs, strconv.FormatInt(v, 10))
+	}
+	registry := versionRegistry(name)
+	cond := fmt.Sprintf("model_name=%s AND version IN (%s)", database.QuoteString(name), strings.Join(vs, ","))
+	var stmt string
+	if db.DriverName == "mysql" {
+		stmt = fmt.Sprintf("DELETE FROM %s WHERE %s", registry, cond)
+	} else {
+		// Hive and MaxCompute don't support DELETE.
+		stmt = fmt.Sprintf("INSERT OVERWRITE TABLE %s SELECT * FROM %s WHERE NOT (%s)", registry, registry, cond)
+	}
+	if _, e := db.Exec(stmt); e != nil {
+		return fmt.Errorf("failed executing %s: %v", stmt, e)
+	}
+	return nil
+}
+
+// VersionRecord is a model version in the version registry.
+type VersionRecord struct {
+	Name      string
+	Version   int64
+	Table     string
+	CreatedAt time.Time
+}
+
+// ListVersionRecords returns the saved model versions in the version
+// registry of the database dbName.
+func ListVersionRecords(db *database.DB, dbName string) ([]*VersionRecord, error) {
+	registry := VersionRegistryTable
+	if dbName != "" {
+		registry = dbName + "." + registry
+	}
+	rows, e := db.Query(fmt.Sprintf("SELECT DISTINCT model_name, version, table_name, created_at FROM %s WHERE table_name<>''", registry))
+	if e != nil {
+		return nil, e
+	}
+	defer rows.Close()
+	records := []*VersionRecord{}
+	for rows.Next() {
+		r := &VersionRecord{}
+		var createdAt string
+		if e := rows.Scan(&r.Name, &r.Version, &r.Table, &createdAt); e != nil {
+			return nil, e
+		}
+		r.CreatedAt = parseCatalogTime(createdAt)
+		records = append(records, r)
+	}
+	return records, rows.Err()
+}
@@ -0,0 +1,145 @@
+// Copyright 2020 The SQLFlow Authors. All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"sqlflow.org/sqlflow/go/database"
+	"sqlflow.org/sqlflow/go/test"
+)
+
+func TestParseVersion(t *testing.T) {
+	a := assert.New(t)
+	name, version, e := ParseVersion("sqlflow_models.my_dnn@3")
+	a.NoError(e)
+	a.Equal("sqlflow_models.my_dnn", name)
+	a.Equal("3", version)
+
+	name, version, e = ParseVersion("sqlflow_models.my_dnn")
+	a.NoError(e)
+	a.Equal("sqlflow_models.my_dnn", name)
+	a.Equal("", version)
+
+	_, version, e = ParseVersion("my_dnn@latest")
+	a.NoError(e)
+	a.Equal(LatestVersion, version)
+
+	_, _, e = ParseVersion("my_dnn@0")
+	a.Error(e)
+	_, _, e = ParseVersion("my_dnn@v1")
+	a.Error(e)
+
+	a.Equal("sqlflow_models.sqlflow_model_versions", versionRegistry("sqlflow_models.my_dnn"))
+	a.Equal("sqlflow_model_versions", versionRegistry("my_dnn"))
+	a.Equal("sqlflow_models.my_dnn__v2", versionTable("sqlflow_models.my_dnn", 2))
+}
+
+func TestModelVersions(t *testing.T) {
+	if test.GetEnv("SQLFLOW_TEST_DB", "mysql") != "mysql" {
+		t.Skip("only test model versions on MySQL")
+	}
+	a := assert.New(t)
+	ws, dst := mockModelDir(a)
+	defer os.RemoveAll(ws)
+	defer os.RemoveAll(dst)
+	session := database.GetSessionFromTestingDB()
+	db := database.GetTestingDBSingleton()
+	name := "iris.my_versioned_model"
+	_, e := db.Exec(fmt.Sprintf("DELETE FROM %s WHERE model_name='%s'", versionRegistry(name), name))
+	if e != nil {
+		a.NoError(createVersionRegistry(db, versionRegistry(name)))
+	}
+
+	os.Setenv(EnvVersionRetention, "2")
+	defer os.Unsetenv(EnvVersionRetention)
+	for i := 1; i <= 3; i++ {
+		a.NoError(ioutil.WriteFile(path.Join(ws, "model.txt"), []byte(fmt.Sprintf("version %d", i)), 0644))
+		m := &Model{workDir: ws}
+		a.NoError(m.Save(name, session))
+		a.Equal(int64(i), m.Version)
+	}
+
+	versions, e := ListVersions(db, name)
+	a.NoError(e)
+	a.Equal([]int64{2, 3}, versions)
+
+	for modelName, content := range map[string]string{
+		name:             "version 3",
+		name + "@latest": "version 3",
+		name + "@2":      "version 2",
+	} {
+		dir, e := ioutil.TempDir("/tmp", "dst")
+		a.NoError(e)
+		defer os.RemoveAll(dir)
+		_, e = Load(modelName, dir, db)
+		a.NoError(e)
+		b, e := ioutil.ReadFile(path.Join(dir, "model.txt"))
+		a.NoError(e)
+		a.Equal(content, string(b))
+	}
+
+	// version 1 is removed by the retention
+	_, e = Load(name+"@1", dst, db)
+	a.Error(e)
+	// cannot save to a version
+	a.Error(New(ws, "").Save(name+"@2", session))
+}
+
+func TestReserveVersionConcurrently(t *testing.T) {
+	if test.GetEnv("SQLFLOW_TEST_DB", "mysql") != "mysql" {
+		t.Skip("only test model versions on MySQL")
+	}
+	a := assert.New(t)
+	db := database.GetTestingDBSingleton()
+	name := "iris.my_concurrently_saved_model"
+	a.NoError(createVersionRegistry(db, versionRegistry(name)))
+	_, e := db.Exec(fmt.Sprintf("DELETE FROM %s WHERE model_name='%s'", versionRegistry(name), name))
+	a.NoError(e)
+
+	const n = 5
+	versions := make(chan int64, n)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			v, e := reserveVersion(db, name)
+			a.NoError(e)
+			versions <- v
+		}()
+	}
+	wg.Wait()
+	close(versions)
+	reserved := map[int64]bool{}
+	for v := range versions {
+		reserved[v] = true
+	}
+	a.Equal(n, len(reserved))
+
+	// the reserved versions are not listed until committed
+	saved, e := ListVersions(db, name)
+	a.NoError(e)
+	a.Empty(saved)
+	a.NoError(commitVersion(db, name, 3, versionTable(name, 3)))
+	saved, e = ListVersions(db, name)
+	a.NoError(e)
+	a.Equal([]int64{3}, saved)
+}
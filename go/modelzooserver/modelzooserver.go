@@ -27,6 +27,7 @@ import (
 
 	"google.golang.org/grpc"
 	"sqlflow.org/sqlflow/go/database"
+	"sqlflow.org/sqlflow/go/health"
 	"sqlflow.org/sqlflow/go/log"
 	"sqlflow.org/sqlflow/go/model"
 	pb "sqlflow.org/sqlflow/go/proto"
@@ -100,9 +101,31 @@ func StartModelZooServer(port int, dbConnStr string) {
 	}
 
 	pb.RegisterModelZooServerServer(grpcServer, &modelZooServer{DB: mysqlConn})
+	gracePeriod, err := health.GracePeriodFromEnv()
+	if err != nil {
+		logger.Fatalf("%v", err)
+	}
+	checker := health.NewChecker("proto.ModelZooServer")
+	checker.AddCheck("database", func(ctx context.Context) error {
+		return mysqlConn.PingContext(ctx)
+	})
+	checker.Register(grpcServer)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go checker.Run(ctx, health.DefaultInterval)
 
 	logger.Infof("SQLFlow Model Zoo started at: %d", port)
-	grpcServer.Serve(lis)
+	go func() {
+		if err := grpcServer.Serve(lis); err != nil {
+			logger.Fatalf("failed to serve: %v", err)
+		}
+	}()
+	sig := health.WaitForSignal()
+	logger.Infof("received %v, shutting down in %v", sig, gracePeriod)
+	checker.Drain()
+	// the model zoo requests don't run subprocesses, so waiting for the
+	// pending RPCs is enough.
+	health.StopServer(grpcServer, gracePeriod)
 }
 
 // modelZooServer is the gRPC model zoo server implementation
@@ -372,7 +395,11 @@ func (s *modelZooServer) ReleaseModel(ctx context.Context, req *pb.ReleaseModelR
 		}
 		modelMeta.TrainSelect = modelMeta.GetMetaAsString("original_sql")
 
-		sendFile, err = sqlfs.Open(db.DB, req.Name, 32)
+		table, err := model.ResolveModelTable(db, req.Name)
+		if err != nil {
+			return nil, err
+		}
+		sendFile, err = sqlfs.Open(db.DB, table, 32)
 		if err != nil {
 			return nil, err
 		}
@@ -381,7 +408,7 @@ func (s *modelZooServer) ReleaseModel(ctx context.Context, req *pb.ReleaseModelR
 		if err != nil {
 			return nil, err
 		}
-		modelMeta, err = model.ExtractMetaFromTarball(tarFile, dir)
+		modelMeta, err = model.ExtractMetaFromTarball(tarFile)
 		if err != nil {
 			return nil, err
 		}
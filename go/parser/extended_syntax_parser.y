@@ -71,6 +71,7 @@ type SQLFlowSelectStmt struct {
 	Run       bool
 	Optimize  bool
 	ShowTrain bool
+	ImportModel bool
 
 	StandardSelect
 	TrainClause
@@ -79,6 +80,7 @@ type SQLFlowSelectStmt struct {
 	EvaluateClause
 	OptimizeClause
 	ShowTrainClause
+	ImportModelClause
 	RunClause
 }
 
@@ -142,6 +144,13 @@ type ShowTrainClause struct {
 	ModelName string
 }
 
+type ImportModelClause struct {
+	// ModelPath is the externally trained model to import.
+	ModelPath   string
+	ImportAttrs Attributes
+	ImportInto  string
+}
+
 func attrsUnion(as1, as2 Attributes) Attributes {
 	for k, v := range as2 {
 		if _, ok := as1[k]; ok {
@@ -174,11 +183,13 @@ func attrsUnion(as1, as2 Attributes) Attributes {
   runc  RunClause
   optim OptimizeClause
   shwtran ShowTrainClause
+  imprt ImportModelClause
 }
 
 %type  <eslt> sqlflow_select_stmt
 %type  <tran> train_clause
 %type  <shwtran> show_train_clause
+%type  <imprt> import_model_clause
 %type  <colc> column_clause
 %type  <labc> label_clause
 %type  <infr> predict_clause
@@ -186,7 +197,7 @@ func attrsUnion(as1, as2 Attributes) Attributes {
 %type  <evalt> evaluate_clause
 %type  <runc> run_clause
 %type  <optim> optimize_clause
-%type  <val> optional_using
+%type  <val> optional_using model_ref
 %type  <expr> expr funcall column
 %type  <expl> ExprList pythonlist columns
 %type  <ctexp> constraint
@@ -196,7 +207,7 @@ func attrsUnion(as1, as2 Attributes) Attributes {
 %type  <atrs> attrs
 %type  <tbls> stringlist, identlist
 
-%token <val> SELECT FROM WHERE LIMIT TRAIN PREDICT EXPLAIN EVALUATE RUN MAXIMIZE MINIMIZE CONSTRAINT WITH COLUMN LABEL USING INTO FOR AS TO SHOW GROUP BY CMD
+%token <val> SELECT FROM WHERE LIMIT TRAIN PREDICT EXPLAIN EVALUATE RUN MAXIMIZE MINIMIZE CONSTRAINT WITH COLUMN LABEL USING INTO FOR AS TO SHOW GROUP BY CMD IMPORT MODEL
 %token <val> IDENT NUMBER STRING
 
 %left <val> AND OR
@@ -259,6 +270,13 @@ sqlflow_select_stmt
 		ShowTrainClause: $1}
 	extendedSyntaxlex.(*lexer).result = $$
 }
+| import_model_clause end_of_stmt {
+	$$ = &SQLFlowSelectStmt{
+		Extended: true,
+		ImportModel: true,
+		ImportModelClause: $1}
+	extendedSyntaxlex.(*lexer).result = $$
+}
 ;
 
 end_of_stmt
@@ -266,7 +284,7 @@ end_of_stmt
 ;
 
 train_clause
-: TO TRAIN IDENT WITH attrs column_clause label_clause optional_using INTO IDENT {
+: TO TRAIN IDENT WITH attrs column_clause label_clause optional_using INTO model_ref {
 	$$.Estimator = $3
 	$$.TrainAttrs = $5
 	$$.Columns = $6
@@ -274,27 +292,27 @@ train_clause
 	$$.TrainUsing = $8
 	$$.Save = $10
   }
-| TO TRAIN IDENT WITH attrs column_clause optional_using INTO IDENT {
+| TO TRAIN IDENT WITH attrs column_clause optional_using INTO model_ref {
 	$$.Estimator = $3
 	$$.TrainAttrs = $5
 	$$.Columns = $6
 	$$.TrainUsing = $7
 	$$.Save = $9
 }
-| TO TRAIN IDENT WITH attrs label_clause optional_using INTO IDENT {
+| TO TRAIN IDENT WITH attrs label_clause optional_using INTO model_ref {
 	$$.Estimator = $3
 	$$.TrainAttrs = $5
 	$$.Label = $6
 	$$.TrainUsing = $7
 	$$.Save = $9
 }
-| TO TRAIN IDENT label_clause optional_using INTO IDENT {
+| TO TRAIN IDENT label_clause optional_using INTO model_ref {
 	$$.Estimator = $3
 	$$.Label = $4
 	$$.TrainUsing = $5
 	$$.Save = $7
 }
-| TO TRAIN IDENT WITH attrs optional_using INTO IDENT {
+| TO TRAIN IDENT WITH attrs optional_using INTO model_ref {
 	$$.Estimator = $3
 	$$.TrainAttrs = $5
 	$$.TrainUsing = $6
@@ -303,20 +321,22 @@ train_clause
 ;
 
 predict_clause
-: TO PREDICT IDENT USING IDENT { $$.Into = $3; $$.Model = $5 }
-| TO PREDICT IDENT WITH attrs USING IDENT { $$.Into = $3; $$.PredAttrs = $5; $$.Model = $7 }
+: TO PREDICT IDENT USING model_ref { $$.Into = $3; $$.Model = $5 }
+| TO PREDICT IDENT WITH attrs USING model_ref { $$.Into = $3; $$.PredAttrs = $5; $$.Model = $7 }
+| TO PREDICT USING model_ref { $$.Model = $4 }
+| TO PREDICT WITH attrs USING model_ref { $$.PredAttrs = $4; $$.Model = $6 }
 ;
 
 explain_clause
-: TO EXPLAIN IDENT optional_using { $$.TrainedModel = $3; $$.Explainer = $4 }
-| TO EXPLAIN IDENT optional_using INTO IDENT { $$.TrainedModel = $3; $$.Explainer = $4; $$.ExplainInto = $6 }
-| TO EXPLAIN IDENT WITH attrs optional_using { $$.TrainedModel = $3; $$.ExplainAttrs = $5; $$.Explainer = $6 }
-| TO EXPLAIN IDENT WITH attrs optional_using INTO IDENT { $$.TrainedModel = $3; $$.ExplainAttrs = $5; $$.Explainer = $6; $$.ExplainInto = $8 }
+: TO EXPLAIN model_ref optional_using { $$.TrainedModel = $3; $$.Explainer = $4 }
+| TO EXPLAIN model_ref optional_using INTO IDENT { $$.TrainedModel = $3; $$.Explainer = $4; $$.ExplainInto = $6 }
+| TO EXPLAIN model_ref WITH attrs optional_using { $$.TrainedModel = $3; $$.ExplainAttrs = $5; $$.Explainer = $6 }
+| TO EXPLAIN model_ref WITH attrs optional_using INTO IDENT { $$.TrainedModel = $3; $$.ExplainAttrs = $5; $$.Explainer = $6; $$.ExplainInto = $8 }
 ;
 
 evaluate_clause
-: TO EVALUATE IDENT WITH attrs label_clause INTO IDENT { $$.ModelToEvaluate = $3; $$.EvaluateAttrs = $5; $$.EvaluateLabel = $6; $$.EvaluateInto = $8 }
-| TO EVALUATE IDENT label_clause INTO IDENT { $$.ModelToEvaluate = $3; $$.EvaluateLabel = $4; $$.EvaluateInto = $6 }
+: TO EVALUATE model_ref WITH attrs label_clause INTO IDENT { $$.ModelToEvaluate = $3; $$.EvaluateAttrs = $5; $$.EvaluateLabel = $6; $$.EvaluateInto = $8 }
+| TO EVALUATE model_ref label_clause INTO IDENT { $$.ModelToEvaluate = $3; $$.EvaluateLabel = $4; $$.EvaluateInto = $6 }
 ;
 
 run_clause
@@ -363,7 +383,11 @@ optimize_clause
 };
 
 show_train_clause
-: SHOW TRAIN IDENT { $$.ModelName = $3; }
+: SHOW TRAIN model_ref { $$.ModelName = $3; }
+;
+
+import_model_clause
+: IMPORT MODEL model_ref AS model_ref WITH attrs { $$.ModelPath = $3; $$.ImportInto = $5; $$.ImportAttrs = $7 }
 ;
 
 optional_using
@@ -371,6 +395,12 @@ optional_using
 | USING IDENT  { $$ = $2 }
 ;
 
+/* a model could be a table or a quoted URI like 'file:///models/my_dnn' */
+model_ref
+: IDENT  { $$ = $1 }
+| STRING { $$ = $1[1:len($1)-1] }
+;
+
 column_clause
 : COLUMN columns 				{ $$ = map[string]ExprList{"feature_columns" : $2} }
 | COLUMN columns FOR IDENT 			{ $$ = map[string]ExprList{$4 : $2} }
@@ -395,6 +425,7 @@ label_clause
 
 attr
 : IDENT '=' expr    { $$ = Attributes{$1 : $3} }
+| LABEL '=' expr    { $$ = Attributes{"label" : $3} } /* e.g. IMPORT MODEL ... WITH label="class" */
 ;
 
 attrs
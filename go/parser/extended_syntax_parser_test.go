@@ -127,6 +127,36 @@ func TestExtendedSyntaxParseToPredict(t *testing.T) {
 	a.False(r.Train)
 	a.Equal("sqlflow_models.my_dnn_model", r.Model)
 	a.Equal("db.table.field", r.Into)
+
+	s := `TO PREDICT WITH predict.batch_size=10 USING sqlflow_models.my_dnn_model;`
+	r, idx, e = parseSQLFlowStmt(s)
+	a.NoError(e)
+	a.Equal(len(s), idx)
+	a.Equal("sqlflow_models.my_dnn_model", r.Model)
+	a.Equal("", r.Into)
+	a.Equal("10", r.PredAttrs["predict.batch_size"].String())
+
+	s = `TO PREDICT db.table.field USING 'file:///models/my_dnn';`
+	r, idx, e = parseSQLFlowStmt(s)
+	a.NoError(e)
+	a.Equal(len(s), idx)
+	a.Equal("file:///models/my_dnn", r.Model)
+
+	s = `TO PREDICT db.table.field USING sqlflow_models.my_dnn@3;`
+	r, idx, e = parseSQLFlowStmt(s)
+	a.NoError(e)
+	a.Equal(len(s), idx)
+	a.Equal("sqlflow_models.my_dnn@3", r.Model)
+}
+
+func TestExtendedSyntaxParseToTrainIntoURI(t *testing.T) {
+	a := assert.New(t)
+	s := `TO TRAIN DNNClassifier LABEL class INTO 'file:///models/my_dnn';`
+	r, idx, e := parseSQLFlowStmt(s)
+	a.NoError(e)
+	a.Equal(len(s), idx)
+	a.True(r.Train)
+	a.Equal("file:///models/my_dnn", r.Save)
 }
 
 func TestExtendedSyntaxParseToExplain(t *testing.T) {
@@ -292,6 +322,36 @@ func TestExtendedShowTrainStmt(t *testing.T) {
 	}
 }
 
+func TestExtendedImportModelStmt(t *testing.T) {
+	a := assert.New(t)
+	{
+		testImport := `IMPORT MODEL 'file:///tmp/model.json' AS sqlflow_models.my_xgb WITH
+estimator="xgboost.gbtree", objective="multi:softprob", label="class", columns=[sepal_length, sepal_width];`
+		r, idx, e := parseSQLFlowStmt(testImport)
+		a.NoError(e)
+		a.True(r.Extended)
+		a.True(r.ImportModel)
+		a.Equal("file:///tmp/model.json", r.ModelPath)
+		a.Equal("sqlflow_models.my_xgb", r.ImportInto)
+		a.Equal(4, len(r.ImportAttrs))
+		a.Equal(`"xgboost.gbtree"`, r.ImportAttrs["estimator"].String())
+		a.Equal(`[sepal_length, sepal_width]`, r.ImportAttrs["columns"].String())
+		a.Equal(len(testImport), idx)
+	}
+	{
+		testImport := `IMPORT MODEL '/tmp/saved_model' AS 'file:///models/my_dnn' WITH estimator=DNNClassifier;`
+		r, _, e := parseSQLFlowStmt(testImport)
+		a.NoError(e)
+		a.Equal("/tmp/saved_model", r.ModelPath)
+		a.Equal("file:///models/my_dnn", r.ImportInto)
+	}
+	{
+		// the attribute estimator is required
+		_, _, e := parseSQLFlowStmt(`IMPORT MODEL 'file:///tmp/model.json' AS my_model;`)
+		a.Error(e)
+	}
+}
+
 func TestExtendedSyntaxParseToRun(t *testing.T) {
 	a := assert.New(t)
 	{
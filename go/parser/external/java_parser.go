@@ -17,7 +17,9 @@ import (
 	"context"
 	"fmt"
 
+	"google.golang.org/grpc/metadata"
 	"sqlflow.org/sqlflow/go/proto"
+	"sqlflow.org/sqlflow/go/tracing"
 )
 
 type javaParser struct {
@@ -30,12 +32,23 @@ func newJavaParser(typ string) *javaParser {
 }
 
 func (p *javaParser) Parse(program string) ([]*Statement, int, error) {
+	return p.ParseContext(context.Background(), program)
+}
+
+func (p *javaParser) ParseContext(ctx context.Context, program string) (stmts []*Statement, idx int, err error) {
+	ctx, span := tracing.Start(ctx, "external.javaParser.Parse")
+	span.SetAttribute("dialect", p.typ)
+	defer func() { span.End(err) }()
+
 	c, err := connectToServer()
 	if err != nil {
 		return nil, -1, err
 	}
 
-	r, err := proto.NewParserClient(c).Parse(context.Background(), &proto.ParserRequest{Dialect: p.typ, SqlProgram: program})
+	if tp := tracing.Traceparent(ctx); tp != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, "traceparent", tp)
+	}
+	r, err := proto.NewParserClient(c).Parse(ctx, &proto.ParserRequest{Dialect: p.typ, SqlProgram: program})
 	if err != nil {
 		return nil, -1, err
 	}
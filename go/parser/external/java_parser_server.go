@@ -28,8 +28,25 @@ import (
 var (
 	mu         sync.Mutex
 	clientConn *grpc.ClientConn
+
+	// started tells if the parser server has been up for parsing, so that
+	// CheckServer probes it.
+	startedMu sync.Mutex
+	started   bool
 )
 
+func setStarted() {
+	startedMu.Lock()
+	defer startedMu.Unlock()
+	started = true
+}
+
+func isStarted() bool {
+	startedMu.Lock()
+	defer startedMu.Unlock()
+	return started
+}
+
 func getServerPort() string {
 	port := os.Getenv("SQLFLOW_PARSER_SERVER_PORT")
 	if port == "" {
@@ -61,6 +78,7 @@ func isServerUp(address string) bool {
 
 func startServerIfNotUp() error {
 	if isServerUp(fmt.Sprintf(":%s", getServerPort())) {
+		setStarted()
 		return nil
 	}
 
@@ -76,6 +94,7 @@ func startServerIfNotUp() error {
 	for i := 0; i < 3; i++ {
 		time.Sleep(time.Second)
 		if isServerUp(fmt.Sprintf(":%s", getServerPort())) {
+			setStarted()
 			return nil
 		}
 	}
@@ -99,3 +118,19 @@ func connectToServer() (*grpc.ClientConn, error) {
 	clientConn, err := grpc.Dial(dialAddress, grpc.WithInsecure())
 	return clientConn, err
 }
+
+// CheckServer returns an error if the Java parser server is down. It only
+// probes the port and never starts the server, which is left to the first
+// parsing by a Java parser. It returns nil if SQLFLOW_PARSER_SERVER_PORT is
+// unset, i.e., the server doesn't use the Java parsers, or if the parser
+// server hasn't been started yet.
+func CheckServer() error {
+	port := os.Getenv("SQLFLOW_PARSER_SERVER_PORT")
+	if port == "" || !isStarted() {
+		return nil
+	}
+	if !isServerUp(fmt.Sprintf(":%s", port)) {
+		return fmt.Errorf("the parser server on port %s is down", port)
+	}
+	return nil
+}
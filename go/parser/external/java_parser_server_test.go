@@ -17,6 +17,7 @@ import (
 	"fmt"
 	"github.com/stretchr/testify/assert"
 	"net"
+	"os"
 	"os/exec"
 	"path/filepath"
 	"strconv"
@@ -64,3 +65,19 @@ func TestIsServerUp(t *testing.T) {
 	err = cmd.Process.Kill()
 	a.NoError(err)
 }
+
+func TestCheckServer(t *testing.T) {
+	a := assert.New(t)
+	port, err := getFreePort()
+	a.NoError(err)
+	defer os.Setenv("SQLFLOW_PARSER_SERVER_PORT", os.Getenv("SQLFLOW_PARSER_SERVER_PORT"))
+	os.Setenv("SQLFLOW_PARSER_SERVER_PORT", strconv.Itoa(port))
+
+	prev := isStarted()
+	defer func() { started = prev }()
+	// the parser server isn't checked until it has been started
+	started = false
+	a.NoError(CheckServer())
+	setStarted()
+	a.Error(CheckServer())
+}
@@ -13,7 +13,10 @@
 
 package external
 
-import "fmt"
+import (
+	"context"
+	"fmt"
+)
 
 // Parser abstract a parser of a SQL engine, for example, Hive, MySQL,
 // TiDB, MaxCompute.
@@ -21,6 +24,13 @@ type Parser interface {
 	Parse(program string) ([]*Statement, int, error)
 }
 
+// ContextParser is a Parser calling a remote server, which accepts the
+// context to cancel the call and propagate the trace.
+type ContextParser interface {
+	Parser
+	ParseContext(ctx context.Context, program string) ([]*Statement, int, error)
+}
+
 // Statement a parsed SQL statement string and it's input tables and output tables.
 type Statement struct {
 	String             string
@@ -37,6 +37,7 @@ type lexer struct {
 	width    int    // width of last rune read from input
 	err      error  // the parser could return the error
 	previous int    // previous start, recorded for error position
+	last     int    // type of the last emitted token
 	// parse result
 	result *SQLFlowSelectStmt
 }
@@ -57,6 +58,7 @@ func (l *lexer) emit(lval *extendedSyntaxSymType, typ int) int {
 	lval.val = l.input[l.start:l.pos]
 	l.previous = l.start
 	l.start = l.pos
+	l.last = typ
 	return typ
 }
 
@@ -187,7 +189,8 @@ func (l *lexer) lexIdentOrKeyword(lval *extendedSyntaxSymType) int {
 	r := l.next()
 	for {
 		// model IDENT may be like: some-domain.com/a_data_scientist/regressors:v0.2/MyDNNRegressor
-		for unicode.IsLetter(r) || unicode.IsNumber(r) || r == '_' || r == '/' || r == ':' || r == '-' {
+		// or a version of a model like: sqlflow_models.my_dnn_model@3
+		for unicode.IsLetter(r) || unicode.IsNumber(r) || r == '_' || r == '/' || r == ':' || r == '-' || r == '@' {
 			r = l.next()
 		}
 		if r != '.' { // The dot cannot be the last rune.
@@ -228,12 +231,19 @@ func (l *lexer) emitIdentOrKeyword(lval *extendedSyntaxSymType) int {
 		"AS":         AS,
 		"TO":         TO,
 		"SHOW":       SHOW,
+		"IMPORT":     IMPORT,
 		"GROUP":      GROUP,
 		"BY":         BY,
 	}
-	if typ, ok := keywds[strings.ToUpper(l.input[l.start:l.pos])]; ok {
+	word := strings.ToUpper(l.input[l.start:l.pos])
+	if typ, ok := keywds[word]; ok {
 		return l.emit(lval, typ)
 	}
+	// MODEL is a keyword only in IMPORT MODEL, so that it could still be a
+	// model name, e.g. TO EXPLAIN model.
+	if word == "MODEL" && l.last == IMPORT {
+		return l.emit(lval, MODEL)
+	}
 	return l.emit(lval, IDENT)
 }
 
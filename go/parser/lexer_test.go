@@ -255,6 +255,18 @@ func TestShowTrain(t *testing.T) {
 	}
 }
 
+func TestImportModel(t *testing.T) {
+	a := assert.New(t)
+	types := []int{IMPORT, MODEL, STRING, AS, IDENT, WITH, IDENT, '=', IDENT, ';', TO, EXPLAIN, IDENT, ';'}
+	vals := []string{"IMPORT", "model", "'/tmp/model.json'", "AS", "model", "WITH", "estimator", "=", "DNNClassifier", ";", "TO", "EXPLAIN", "model", ";"}
+	l := newLexer(`IMPORT model '/tmp/model.json' AS model WITH estimator=DNNClassifier; TO EXPLAIN model;`)
+	var n extendedSyntaxSymType
+	for i, t := range types {
+		a.Equal(t, l.Lex(&n))
+		a.Equal(vals[i], n.val)
+	}
+}
+
 func TestLexerUnmatchedQuotation(t *testing.T) {
 	a := assert.New(t)
 	l := newLexer(`TO TRAIN "some_thing`)
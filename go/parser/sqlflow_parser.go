@@ -14,10 +14,13 @@
 package parser
 
 import (
+	"context"
 	"fmt"
 	"strings"
 
+	"sqlflow.org/sqlflow/go/metrics"
 	"sqlflow.org/sqlflow/go/parser/external"
+	"sqlflow.org/sqlflow/go/tracing"
 )
 
 // SQLFlowStmt represents a parsed SQL statement.  The original
@@ -57,6 +60,23 @@ func ParseStatement(dialect, program string) (*SQLFlowStmt, error) {
 
 // Parse a SQL program in the given dialect into a list of SQL statements.
 func Parse(dialect, program string) ([]*SQLFlowStmt, error) {
+	return ParseContext(context.Background(), dialect, program)
+}
+
+// ParseContext is Parse in the trace of ctx.
+func ParseContext(ctx context.Context, dialect, program string) ([]*SQLFlowStmt, error) {
+	ctx, span := tracing.Start(ctx, "parser.Parse")
+	span.SetAttribute("dialect", dialect)
+	stmts, err := parse(ctx, dialect, program)
+	if err != nil {
+		metrics.ParseErrors.WithLabelValues(dialect).Inc()
+	}
+	span.SetAttribute("statements", len(stmts))
+	span.End(err)
+	return stmts, err
+}
+
+func parse(ctx context.Context, dialect, program string) ([]*SQLFlowStmt, error) {
 	//all := []*SQLFlowStmt{{Original: `SHOW create table sqlflow_models.my_dnn_model;`}}
 	all := []*SQLFlowStmt{}
 	for {
@@ -67,7 +87,11 @@ func Parse(dialect, program string) ([]*SQLFlowStmt, error) {
 		// SELECT ...; SHOW TRAIN my_model;
 		//            ^
 		//            i
-		sqls, i, err := thirdPartyParse(dialect, program)
+		// or
+		// SELECT ...; IMPORT MODEL 'file:///path/model.json' AS my_model WITH ...;
+		//            ^
+		//            i
+		sqls, i, err := thirdPartyParse(ctx, dialect, program)
 
 		if err != nil {
 			return nil, err
@@ -83,7 +107,7 @@ func Parse(dialect, program string) ([]*SQLFlowStmt, error) {
 		}
 		// SELECT ... .TO ...
 		if len(sqls) > 0 && sqls[len(sqls)-1].IsUnfinishedSelect {
-			if extended.ShowTrain {
+			if extended.ShowTrain || extended.ImportModel {
 				return nil, fmt.Errorf("select should followed by 'to train/predict/explain'")
 			}
 			left := all[len(all)-1].Original
@@ -94,7 +118,7 @@ func Parse(dialect, program string) ([]*SQLFlowStmt, error) {
 			program = program[j:]
 		} else {
 			// Purely extended sql stmt
-			if !extended.ShowTrain {
+			if !extended.ShowTrain && !extended.ImportModel {
 				return nil, fmt.Errorf("invalid 'to train/predict/explain' with no 'select'")
 			}
 			sql := &SQLFlowStmt{Original: program[:j], SQLFlowSelectStmt: extended}
@@ -125,12 +149,22 @@ func parseFirstSQLFlowStmt(program string) (*SQLFlowSelectStmt, int, error) {
 	return pr, idx, nil
 }
 
-func thirdPartyParse(dialect, program string) ([]*SQLFlowStmt, int, error) {
+func thirdPartyParse(ctx context.Context, dialect, program string) (stmts []*SQLFlowStmt, idx int, err error) {
+	ctx, span := tracing.Start(ctx, "parser.thirdPartyParse")
+	span.SetAttribute("dialect", dialect)
+	defer func() { span.End(err) }()
+
 	p, err := external.NewParser(dialect)
 	if err != nil {
 		return nil, -1, fmt.Errorf("thirdPartyParse failed: %v", err)
 	}
-	sqls, i, err := p.Parse(program)
+	var sqls []*external.Statement
+	var i int
+	if cp, ok := p.(external.ContextParser); ok {
+		sqls, i, err = cp.ParseContext(ctx, program)
+	} else {
+		sqls, i, err = p.Parse(program)
+	}
 	if err != nil {
 		return nil, -1, fmt.Errorf("thirdPartyParse failed: %v", err)
 	}
@@ -106,6 +106,20 @@ func commonTestCases(dbms string, a *assert.Assertions) {
 			a.Equal(0, len(s))
 		}
 	}
+	// two SQL statements, the first one is IMPORT MODEL
+	{
+		extendedSQL := `IMPORT MODEL 'file:///tmp/model.json' AS my_model WITH estimator="xgboost.gbtree"`
+		for _, sql := range external.SelectCases {
+			sqls := fmt.Sprintf(`%s;%s;`, extendedSQL, sql)
+			s, err := Parse(dbms, sqls)
+			a.NoError(err)
+			a.Equal(2, len(s))
+			a.True(s[0].ImportModel)
+			a.Equal("file:///tmp/model.json", s[0].ModelPath)
+			a.Equal("my_model", s[0].ImportInto)
+			a.False(s[1].IsExtendedSyntax())
+		}
+	}
 	// two SQL statements, the second one is extendedSQL
 	for _, sql := range external.SelectCases {
 		sqls := fmt.Sprintf(`%s;%s %s;`, sql, sql, extendedSQL)
@@ -15,6 +15,7 @@ package pipe
 
 import (
 	"errors"
+	"sync"
 )
 
 // ErrClosedPipe will occur when manipulating an already closed pipe
@@ -23,9 +24,13 @@ var ErrClosedPipe = errors.New("pipe: write on closed pipe")
 // pipe follows the design at https://blog.golang.org/pipelines
 // - wrCh: chan for piping data
 // - done: chan for signaling Close from Reader to Writer
+// - cancel: chan for signaling Cancel from Reader to Writer
 type pipe struct {
-	wrCh chan interface{}
-	done chan struct{}
+	wrCh       chan interface{}
+	done       chan struct{}
+	cancel     chan struct{}
+	closeOnce  sync.Once
+	cancelOnce sync.Once
 }
 
 // Reader reads real data
@@ -45,14 +50,22 @@ type Writer struct {
 // the individual calls will be gated sequentially.
 func Pipe() (*Reader, *Writer) {
 	p := &pipe{
-		wrCh: make(chan interface{}),
-		done: make(chan struct{})}
+		wrCh:   make(chan interface{}),
+		done:   make(chan struct{}),
+		cancel: make(chan struct{})}
 	return &Reader{p}, &Writer{p}
 }
 
 // Close closes the reader; subsequent writes to the
 func (r *Reader) Close() {
-	close(r.p.done)
+	r.p.closeOnce.Do(func() { close(r.p.done) })
+}
+
+// Cancel closes the reader and asks the writer to stop, e.g., to kill the
+// running programs. It is safe to call Cancel and Close more than once.
+func (r *Reader) Cancel() {
+	r.p.cancelOnce.Do(func() { close(r.p.cancel) })
+	r.Close()
 }
 
 // ReadAll returns the data chan. The caller should
@@ -67,6 +80,11 @@ func (w *Writer) Close() {
 	close(w.p.wrCh)
 }
 
+// Cancelled returns a chan which is closed when the reader cancels the pipe.
+func (w *Writer) Cancelled() <-chan struct{} {
+	return w.p.cancel
+}
+
 // Write writes the item to the underlying data stream.
 // It returns ErrClosedPipe when the data stream is closed.
 func (w *Writer) Write(item interface{}) error {
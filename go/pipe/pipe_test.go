@@ -70,3 +70,23 @@ func TestPipeReaderClose(t *testing.T) {
 		a.True(false, "time out on writer return")
 	}
 }
+
+func TestPipeReaderCancel(t *testing.T) {
+	a := assert.New(t)
+	rd, wr := Pipe()
+
+	select {
+	case <-wr.Cancelled():
+		a.True(false, "the pipe is not cancelled yet")
+	default:
+	}
+	rd.Cancel()
+	rd.Cancel()
+	rd.Close()
+	select {
+	case <-wr.Cancelled():
+	case <-time.After(time.Second):
+		a.True(false, "time out on cancel")
+	}
+	a.Equal(ErrClosedPipe, wr.Write(1))
+}
@@ -0,0 +1,151 @@
+// Copyright 2020 The SQLFlow Authors. All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package policy
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"sqlflow.org/sqlflow/go/database"
+	"sqlflow.org/sqlflow/go/ir"
+	"sqlflow.org/sqlflow/go/parser"
+)
+
+// Access is what a statement reads and writes.
+type Access struct {
+	Statement   string
+	ReadTables  []string
+	WriteTables []string
+	// Models are the models used or saved by the statement.
+	Models []string
+	// ModelPaths are the files of the models imported by the statement.
+	ModelPaths []string
+}
+
+// StatementAccess returns the access of the statement sql, whose IR is r.
+// The tables read and written by a standard SQL statement are its Inputs and
+// Outputs resolved by the parser, and the models and the tables written by an
+// extended SQL statement are the names after INTO and USING in the IR. A
+// saved model is also a written table. The tables and the models are
+// qualified by the database dbName if it isn't empty.
+func StatementAccess(dialect string, sql *parser.SQLFlowStmt, r ir.SQLFlowStmt, dbName string) (*Access, error) {
+	a := &Access{Statement: sql.Original, ReadTables: sql.Inputs}
+	write := func(tables ...string) {
+		for _, t := range tables {
+			if t = strings.TrimSpace(t); t != "" {
+				a.WriteTables = append(a.WriteTables, t)
+			}
+		}
+	}
+	model := func(models ...string) {
+		for _, m := range models {
+			if m != "" {
+				a.Models = append(a.Models, m)
+			}
+		}
+	}
+	switch s := r.(type) {
+	case *ir.NormalStmt:
+		write(sql.Outputs...)
+	case *ir.TrainStmt:
+		if s.ValidationSelect != "" {
+			validation, e := parser.Parse(dialect, s.ValidationSelect)
+			if e != nil {
+				return nil, fmt.Errorf("cannot parse the validation select: %v", e)
+			}
+			for _, v := range validation {
+				a.ReadTables = append(a.ReadTables, v.Inputs...)
+			}
+		}
+		model(s.Into, s.PreTrainedModel)
+		write(s.Into)
+	case *ir.PredictStmt:
+		model(s.Using)
+		write(s.ResultTable)
+	case *ir.ExplainStmt:
+		model(s.ModelName)
+		write(s.Into)
+	case *ir.EvaluateStmt:
+		model(s.ModelName)
+		write(s.Into)
+	case *ir.ShowTrainStmt:
+		model(s.ModelName)
+	case *ir.ImportModelStmt:
+		model(s.Into)
+		write(s.Into)
+		path, e := importPath(s.ModelPath)
+		if e != nil {
+			return nil, e
+		}
+		a.ModelPaths = append(a.ModelPaths, path)
+	case *ir.OptimizeStmt:
+		write(s.ResultTable)
+	case *ir.RunStmt:
+		write(strings.Split(s.Into, ",")...)
+	default:
+		return nil, fmt.Errorf("unrecognized IR type: %T", r)
+	}
+	a.ReadTables = qualify(a.ReadTables, dbName)
+	a.WriteTables = qualify(a.WriteTables, dbName)
+	a.Models = qualify(a.Models, dbName)
+	return a, nil
+}
+
+// importPath returns the absolute and clean path of the model file src of
+// IMPORT MODEL, which is a path or a file:// URI.
+func importPath(src string) (string, error) {
+	path, e := filepath.Abs(strings.TrimPrefix(src, "file://"))
+	if e != nil {
+		return "", fmt.Errorf("invalid model path %s: %v", src, e)
+	}
+	return path, nil
+}
+
+// ProgramAccess returns the accesses of the statements sqls, whose IRs are
+// irs, on the data source dataSource.
+func ProgramAccess(dataSource string, sqls []*parser.SQLFlowStmt, irs []ir.SQLFlowStmt) ([]*Access, error) {
+	if len(sqls) != len(irs) {
+		return nil, fmt.Errorf("got %d statements but %d IRs", len(sqls), len(irs))
+	}
+	dialect, _, e := database.ParseURL(dataSource)
+	if e != nil {
+		return nil, e
+	}
+	dbName, e := database.GetDatabaseName(dataSource)
+	if e != nil {
+		return nil, e
+	}
+	accesses := []*Access{}
+	for i, sql := range sqls {
+		a, e := StatementAccess(dialect, sql, irs[i], dbName)
+		if e != nil {
+			return nil, e
+		}
+		accesses = append(accesses, a)
+	}
+	return accesses, nil
+}
+
+func qualify(tables []string, dbName string) []string {
+	qualified := []string{}
+	for _, t := range tables {
+		t = strings.Trim(t, "`")
+		if !strings.Contains(t, ".") && dbName != "" {
+			t = dbName + "." + t
+		}
+		qualified = append(qualified, t)
+	}
+	return qualified
+}
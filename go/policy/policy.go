@@ -0,0 +1,173 @@
+// Copyright 2020 The SQLFlow Authors. All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package policy authorizes the users to access the data sources, tables and
+// models by a policy file.
+package policy
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Policy maps the users or groups to the data sources, tables and models
+// they are allowed to access. For example:
+//
+//	groups:
+//	  analysts: [alice, bob]
+//	rules:
+//	- groups: [analysts]
+//	  datasources: ["mysql://*@tcp(127.0.0.1:3306)/*"]
+//	  read_tables: ["iris.*"]
+//	  write_tables: ["analysts.*"]
+//	  models: ["sqlflow_models.*"]
+//	  model_paths: ["/models/analysts/*"]
+//
+// The patterns are case-insensitive and * matches any string. The tables
+// are qualified by the database before matching. A user is allowed to
+// access the union of the rules listing the user, one of the groups of the
+// user, or the user "*", and whose data sources match the one being used.
+// Writing a table implies reading it, and saving a model writes it as a
+// table.
+type Policy struct {
+	Groups map[string][]string `yaml:"groups"`
+	Rules  []Rule              `yaml:"rules"`
+}
+
+// Rule is a rule of a Policy.
+type Rule struct {
+	Users       []string `yaml:"users"`
+	Groups      []string `yaml:"groups"`
+	DataSources []string `yaml:"datasources"`
+	ReadTables  []string `yaml:"read_tables"`
+	WriteTables []string `yaml:"write_tables"`
+	Models      []string `yaml:"models"`
+	// ModelPaths are the model files that IMPORT MODEL could import.
+	ModelPaths []string `yaml:"model_paths"`
+}
+
+// Load loads the policy in the YAML file.
+func Load(file string) (*Policy, error) {
+	b, e := ioutil.ReadFile(file)
+	if e != nil {
+		return nil, fmt.Errorf("cannot load the policy: %v", e)
+	}
+	p := &Policy{}
+	if e := yaml.UnmarshalStrict(b, p); e != nil {
+		return nil, fmt.Errorf("cannot parse the policy %s: %v", file, e)
+	}
+	return p, nil
+}
+
+// LoadFromEnv loads the policy file SQLFLOW_POLICY_FILE. It returns nil if
+// SQLFLOW_POLICY_FILE is empty, which disables authorization. The file is
+// loaded on every call, so that the changes take effect without restarting
+// the server.
+func LoadFromEnv() (*Policy, error) {
+	file := os.Getenv("SQLFLOW_POLICY_FILE")
+	if file == "" {
+		return nil, nil
+	}
+	return Load(file)
+}
+
+// rules returns the rules applying to user on dataSource.
+func (p *Policy) rules(user, dataSource string) []Rule {
+	groups := map[string]bool{}
+	for g, members := range p.Groups {
+		if contains(members, user) {
+			groups[g] = true
+		}
+	}
+	rules := []Rule{}
+	for _, r := range p.Rules {
+		applied := contains(r.Users, "*") || (user != "" && contains(r.Users, user))
+		for _, g := range r.Groups {
+			applied = applied || groups[g]
+		}
+		if applied && matchAny(r.DataSources, dataSource) {
+			rules = append(rules, r)
+		}
+	}
+	return rules
+}
+
+// Authorize returns a permission error if user is not allowed to access
+// dataSource as described by accesses.
+func (p *Policy) Authorize(user, dataSource string, accesses []*Access) error {
+	rules := p.rules(user, dataSource)
+	if len(rules) == 0 {
+		return fmt.Errorf("permission denied: user %q is not allowed to access the data source", user)
+	}
+	allowed := func(name string, patterns func(r Rule) []string) bool {
+		for _, r := range rules {
+			if matchAny(patterns(r), name) {
+				return true
+			}
+		}
+		return false
+	}
+	readable := func(r Rule) []string { return append(append([]string{}, r.ReadTables...), r.WriteTables...) }
+	writable := func(r Rule) []string { return r.WriteTables }
+	models := func(r Rule) []string { return r.Models }
+	modelPaths := func(r Rule) []string { return r.ModelPaths }
+	for _, a := range accesses {
+		for _, t := range a.ReadTables {
+			if !allowed(t, readable) {
+				return fmt.Errorf("permission denied: user %q cannot read table %s in: %s", user, t, a.Statement)
+			}
+		}
+		for _, t := range a.WriteTables {
+			if !allowed(t, writable) {
+				return fmt.Errorf("permission denied: user %q cannot write table %s in: %s", user, t, a.Statement)
+			}
+		}
+		for _, m := range a.Models {
+			if !allowed(m, models) {
+				return fmt.Errorf("permission denied: user %q cannot access model %s in: %s", user, m, a.Statement)
+			}
+		}
+		for _, p := range a.ModelPaths {
+			if !allowed(p, modelPaths) {
+				return fmt.Errorf("permission denied: user %q cannot import model file %s in: %s", user, p, a.Statement)
+			}
+		}
+	}
+	return nil
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// matchAny returns true if name matches any of the patterns, in which *
+// matches any string.
+func matchAny(patterns []string, name string) bool {
+	for _, p := range patterns {
+		re := "^" + strings.Replace(regexp.QuoteMeta(p), `\*`, ".*", -1) + "$"
+		if ok, _ := regexp.MatchString("(?is)"+re, name); ok {
+			return true
+		}
+	}
+	return false
+}
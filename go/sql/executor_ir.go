@@ -14,12 +14,17 @@
 package sql
 
 import (
+	"context"
 	"fmt"
 	"io/ioutil"
 	"os"
 	"strings"
 	"time"
 
+	"github.com/golang/protobuf/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"sqlflow.org/sqlflow/go/artifact"
 	"sqlflow.org/sqlflow/go/codegen/experimental"
 
 	"sqlflow.org/sqlflow/go/codegen/optimize"
@@ -28,11 +33,15 @@ import (
 	"sqlflow.org/sqlflow/go/codegen/xgboost"
 	"sqlflow.org/sqlflow/go/database"
 	"sqlflow.org/sqlflow/go/executor"
+	"sqlflow.org/sqlflow/go/history"
 	"sqlflow.org/sqlflow/go/ir"
 	"sqlflow.org/sqlflow/go/log"
+	"sqlflow.org/sqlflow/go/metrics"
 	"sqlflow.org/sqlflow/go/parser"
 	"sqlflow.org/sqlflow/go/pipe"
+	"sqlflow.org/sqlflow/go/policy"
 	pb "sqlflow.org/sqlflow/go/proto"
+	"sqlflow.org/sqlflow/go/tracing"
 )
 
 // EndOfExecution will push to the pipe when one SQL statement execution is finished.
@@ -52,15 +61,17 @@ func RunSQLProgram(sqlProgram string, session *pb.Session) *pipe.Reader {
 		var db *database.DB
 		var err error
 		defer wr.Close()
+		ctx, span := tracing.StartWithTraceparent(session.Traceparent, "sql.RunSQLProgram")
+		defer func() { span.End(err) }()
 		if db, err = database.OpenAndConnectDB(session.DbConnStr); err != nil {
 			wr.Write(fmt.Errorf("create DB failed: %v", err))
 			return
 		}
 		defer db.Close()
-		err = runSQLProgram(wr, sqlProgram, db, session)
+		err = runSQLProgram(ctx, wr, sqlProgram, db, session)
 		if err != nil {
 			if e := wr.Write(fmt.Errorf("runSQLProgram error: %v", err)); e != nil {
-				log.GetDefaultLogger().Errorf("runSQLProgram error(piping): %v", e)
+				tracing.Logger(ctx, nil).Errorf("runSQLProgram error(piping): %v", e)
 			}
 		}
 	}()
@@ -95,16 +106,19 @@ func ResolveSQLProgram(sqlStmts []*parser.SQLFlowStmt, logger *log.Logger) ([]ir
 			} else if sql.ShowTrain {
 				logger.Info("resolveSQL:showTrain")
 				r, err = ir.GenerateShowTrainStmt(sql.SQLFlowSelectStmt)
+			} else if sql.ImportModel {
+				logger.Info("resolveSQL:importModel")
+				r, err = ir.GenerateImportModelStmt(sql.SQLFlowSelectStmt)
 			} else if sql.Explain {
 				logger.Info("resolveSQL:explain")
 				// since getTrainStmtFromModel is false, use empty cwd is fine.
-				r, err = ir.GenerateExplainStmt(sql.SQLFlowSelectStmt, "", "", false)
+				r, err = ir.GenerateExplainStmt(context.Background(), sql.SQLFlowSelectStmt, "", "", false)
 			} else if sql.Predict {
 				logger.Info("resolveSQL:predict")
-				r, err = ir.GeneratePredictStmt(sql.SQLFlowSelectStmt, "", "", false)
+				r, err = ir.GeneratePredictStmt(context.Background(), sql.SQLFlowSelectStmt, "", "", false)
 			} else if sql.Evaluate {
 				logger.Info("resolveSQL:evaluate")
-				r, err = ir.GenerateEvaluateStmt(sql.SQLFlowSelectStmt, "", "", false)
+				r, err = ir.GenerateEvaluateStmt(context.Background(), sql.SQLFlowSelectStmt, "", "", false)
 			} else if sql.Optimize {
 				logger.Info("resolveSQL:optimize")
 				r, err = ir.GenerateOptimizeStmt(sql.SQLFlowSelectStmt)
@@ -133,14 +147,19 @@ func ResolveSQLProgram(sqlStmts []*parser.SQLFlowStmt, logger *log.Logger) ([]ir
 	return spIRs, nil
 }
 
-func runSQLProgram(wr *pipe.Writer, sqlProgram string, db *database.DB, session *pb.Session) error {
+func runSQLProgram(ctx context.Context, wr *pipe.Writer, sqlProgram string, db *database.DB, session *pb.Session) error {
+	startTime := time.Now()
 	sqlProgram, err := parser.RemoveCommentInSQLStatement(sqlProgram)
 	if err != nil {
 		return err
 	}
 
-	stmts, err := parser.Parse(db.DriverName, sqlProgram)
+	stmts, err := parser.ParseContext(ctx, db.DriverName, sqlProgram)
 	if err != nil {
+		// record the whole program since it couldn't be split into statements
+		record := history.NewRecord(session.UserId, session.Submitter, sqlProgram, nil, startTime)
+		record.Finish(err)
+		writeHistory(ctx, db, record)
 		return err
 	}
 	// NOTE(tony): We generate IR and execute its translated program one-by-one since IR generation may depend on the execution
@@ -152,15 +171,138 @@ func runSQLProgram(wr *pipe.Writer, sqlProgram string, db *database.DB, session
 	// The IR generation on the second statement would fail since it requires inspection the schema of some_table,
 	// which depends on the execution of create table some_table as (select ...);.
 	sqls := RewriteStatementsWithHints(stmts, db.DriverName)
-	for _, sql := range sqls {
-		if err := runSingleSQLFlowStatement(wr, sql, db, session); err != nil {
+	if err := Authorize(sqls, nil, session); err != nil {
+		recordFailure(ctx, db, sqls, session, startTime, err)
+		return err
+	}
+
+	artifacts, err := artifact.NewRequestFromEnv(log.UUID())
+	if err != nil {
+		recordFailure(ctx, db, sqls, session, startTime, err)
+		return err
+	}
+	if artifacts != nil {
+		if err := wr.Write(fmt.Sprintf("Artifacts of this request are written to %s", artifacts.Dir)); err != nil {
+			return err
+		}
+	}
+	for idx, sql := range sqls {
+		select {
+		case <-wr.Cancelled():
+			return fmt.Errorf("cancelled before running: %s", sql.Original)
+		default:
+		}
+		stmtArtifacts, err := artifacts.Statement(idx, sql.Original)
+		if err != nil {
+			recordFailure(ctx, db, sqls[idx:idx+1], session, time.Now(), err)
+			return err
+		}
+		err = runSingleSQLFlowStatement(ctx, wr, sql, db, session, stmtArtifacts)
+		if e := stmtArtifacts.Close(err); e != nil {
+			tracing.Logger(ctx, nil).Errorf("failed to write artifacts: %v", e)
+		}
+		if err != nil {
+			if artifacts != nil {
+				return fmt.Errorf("%v\nartifacts are written to %s", err, stmtArtifacts.Dir)
+			}
 			return err
 		}
 	}
 	return nil
 }
 
-func runSingleSQLFlowStatement(wr *pipe.Writer, sql *parser.SQLFlowStmt, db *database.DB, session *pb.Session) (e error) {
+// Authorize checks if session.UserId is allowed to run the statements sqls,
+// whose IRs are irs, by the policy file SQLFLOW_POLICY_FILE. If irs is nil,
+// it resolves the IRs of sqls. It does nothing if there is no policy file,
+// and returns PermissionDenied if the statements are not allowed.
+func Authorize(sqls []*parser.SQLFlowStmt, irs []ir.SQLFlowStmt, session *pb.Session) error {
+	p, err := policy.LoadFromEnv()
+	if err != nil || p == nil {
+		return err
+	}
+	if irs == nil {
+		ctx := tracing.ContextWithTraceparent(context.Background(), session.Traceparent)
+		if irs, err = ResolveSQLProgram(sqls, tracing.Logger(ctx, nil)); err != nil {
+			return err
+		}
+	}
+	accesses, err := policy.ProgramAccess(session.DbConnStr, sqls, irs)
+	if err != nil {
+		return err
+	}
+	if err := p.Authorize(session.UserId, session.DbConnStr, accesses); err != nil {
+		return status.Errorf(codes.PermissionDenied, "%v", err)
+	}
+	return nil
+}
+
+// writeHistory writes record into the job history table if it is enabled.
+func writeHistory(ctx context.Context, db *database.DB, record *history.Record) {
+	table := history.TableFromEnv()
+	if table == "" {
+		return
+	}
+	if err := history.Write(db, table, record); err != nil {
+		tracing.Logger(ctx, nil).Errorf("failed to write job history: %v", err)
+	}
+}
+
+// recordFailure writes a failed record of each statement in sqls into the
+// job history table, since they don't run because of err, e.g., they are
+// denied by the policy.
+func recordFailure(ctx context.Context, db *database.DB, sqls []*parser.SQLFlowStmt, session *pb.Session, startTime time.Time, err error) {
+	for _, sql := range sqls {
+		record := history.NewRecord(session.UserId, session.Submitter, sql.Original, nil, startTime)
+		record.Finish(err)
+		writeHistory(ctx, db, record)
+	}
+}
+
+// RecordSubmission writes the record of sqlProgram submitted as a workflow
+// into the job history table of the database of session, failed if err isn't
+// nil. It does nothing if the job history is disabled.
+func RecordSubmission(ctx context.Context, sqlProgram string, session *pb.Session, startTime time.Time, err error) {
+	if history.TableFromEnv() == "" {
+		return
+	}
+	db, e := database.OpenAndConnectDB(session.DbConnStr)
+	if e != nil {
+		tracing.Logger(ctx, nil).Errorf("failed to write job history: %v", e)
+		return
+	}
+	defer db.Close()
+	record := history.NewRecord(session.UserId, session.Submitter, sqlProgram, nil, startTime)
+	record.IRType = history.IRTypeWorkflow
+	record.Finish(err)
+	writeHistory(ctx, db, record)
+}
+
+// withTraceparent returns a copy of session in the trace of ctx, so that the
+// spans of the executor, e.g., the subprocesses, are children of the span in
+// ctx.
+func withTraceparent(ctx context.Context, session *pb.Session) *pb.Session {
+	tp := tracing.Traceparent(ctx)
+	if tp == "" {
+		return session
+	}
+	s := proto.Clone(session).(*pb.Session)
+	s.Traceparent = tp
+	return s
+}
+
+func runSingleSQLFlowStatement(ctx context.Context, wr *pipe.Writer, sql *parser.SQLFlowStmt, db *database.DB, session *pb.Session, artifacts *artifact.Statement) (e error) {
+	ctx, span := tracing.Start(ctx, "sql.RunStatement")
+	defer func() { span.End(e) }()
+	session = withTraceparent(ctx, session)
+
+	var r ir.SQLFlowStmt
+	defer func(startTime time.Time) {
+		record := history.NewRecord(session.UserId, session.Submitter, sql.Original, r, startTime)
+		record.Finish(e)
+		metrics.ObserveStatement(record.IRType, startTime, e)
+		span.SetAttribute("ir.type", record.IRType)
+		writeHistory(ctx, db, record)
+	}(time.Now())
 	defer func(startTime int64) {
 		// NOTE(tony): EndOfExecution indicates a successful run,
 		// so we only writes it when e != nil
@@ -186,17 +328,17 @@ func runSingleSQLFlowStatement(wr *pipe.Writer, sql *parser.SQLFlowStmt, db *dat
 
 	exec := executor.New(session.Submitter)
 	exec.Setup(wr, db, cwd, session)
+	exec.GetPythonExecutor().Artifacts = artifacts
 
 	useExperimentalExecutor, err := executor.UseExperimentalExecutor(session.DbConnStr)
 	if err != nil {
 		return err
 	}
 
-	var r ir.SQLFlowStmt
 	if useExperimentalExecutor {
 		r, err = experimental.GenerateIRStatement(sql, session)
 	} else {
-		r, err = legacyGenerateIRStatement(sql, session, cwd)
+		r, err = legacyGenerateIRStatement(ctx, sql, session, cwd)
 	}
 	if err != nil {
 		return err
@@ -205,27 +347,35 @@ func runSingleSQLFlowStatement(wr *pipe.Writer, sql *parser.SQLFlowStmt, db *dat
 		return err
 	}
 	r.SetOriginalSQL(sql.Original)
+	if ts, ok := r.(*ir.TrainStmt); ok {
+		ts.Inputs = sql.Inputs
+	}
+	if err = artifacts.WriteIR(r); err != nil {
+		return err
+	}
 	// TODO(typhoonzero): can run feature.LogDerivationResult(wr, trainStmt) here to send
 	// feature derivation logs to client, yet we disable it for now so that it's less annoying.
 	return executor.Run(exec, r)
 }
 
-func legacyGenerateIRStatement(sql *parser.SQLFlowStmt, session *pb.Session, cwd string) (ir.SQLFlowStmt, error) {
-	var r ir.SQLFlowStmt
-	var err error
+func legacyGenerateIRStatement(ctx context.Context, sql *parser.SQLFlowStmt, session *pb.Session, cwd string) (r ir.SQLFlowStmt, err error) {
+	ctx, span := tracing.Start(ctx, "ir.Generate")
+	defer func() { span.End(err) }()
 	if sql.IsExtendedSyntax() {
 		generateTrainStmtFromModel := executor.New(session.Submitter).GetTrainStmtFromModel()
 		if sql.Train {
 			// generateTrainStmtFromModel refers to if a pre-trained model
-			r, err = ir.GenerateTrainStmtWithInferredColumns(sql.SQLFlowSelectStmt, session.DbConnStr, cwd, generateTrainStmtFromModel, true)
+			r, err = ir.GenerateTrainStmtWithInferredColumns(ctx, sql.SQLFlowSelectStmt, session.DbConnStr, cwd, generateTrainStmtFromModel, true)
 		} else if sql.ShowTrain {
 			r, err = ir.GenerateShowTrainStmt(sql.SQLFlowSelectStmt)
+		} else if sql.ImportModel {
+			r, err = ir.GenerateImportModelStmt(sql.SQLFlowSelectStmt)
 		} else if sql.Explain {
-			r, err = ir.GenerateExplainStmt(sql.SQLFlowSelectStmt, session.DbConnStr, cwd, generateTrainStmtFromModel)
+			r, err = ir.GenerateExplainStmt(ctx, sql.SQLFlowSelectStmt, session.DbConnStr, cwd, generateTrainStmtFromModel)
 		} else if sql.Predict {
-			r, err = ir.GeneratePredictStmt(sql.SQLFlowSelectStmt, session.DbConnStr, cwd, generateTrainStmtFromModel)
+			r, err = ir.GeneratePredictStmt(ctx, sql.SQLFlowSelectStmt, session.DbConnStr, cwd, generateTrainStmtFromModel)
 		} else if sql.Evaluate {
-			r, err = ir.GenerateEvaluateStmt(sql.SQLFlowSelectStmt, session.DbConnStr, cwd, generateTrainStmtFromModel)
+			r, err = ir.GenerateEvaluateStmt(ctx, sql.SQLFlowSelectStmt, session.DbConnStr, cwd, generateTrainStmtFromModel)
 		} else if sql.Optimize {
 			r, err = ir.GenerateOptimizeStmt(sql.SQLFlowSelectStmt)
 		} else if sql.Run {
@@ -29,7 +29,8 @@ func MakeSessionFromEnv() *pb.Session {
 		DbConnStr:    os.Getenv("SQLFLOW_DATASOURCE"),
 		ExitOnSubmit: strings.ToLower(os.Getenv("SQLFLOW_EXIT_ON_SUBMIT")) == "true",
 		UserId:       os.Getenv("SQLFLOW_USER_ID"),
-		Submitter:    os.Getenv("SQLFLOW_submitter")}
+		Submitter:    os.Getenv("SQLFLOW_submitter"),
+		Traceparent:  os.Getenv("TRACEPARENT")}
 
 	// User should specify hive params in uri,
 	// to stay compatible with historical logic,
@@ -0,0 +1,159 @@
+// Copyright 2020 The SQLFlow Authors. All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"sync"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"sqlflow.org/sqlflow/go/auth"
+	"sqlflow.org/sqlflow/go/pipe"
+	pb "sqlflow.org/sqlflow/go/proto"
+	"sqlflow.org/sqlflow/go/workflow/argo"
+)
+
+// localJob is a request running in the local mode.
+type localJob struct {
+	// owner is the user who started the job.
+	owner     string
+	mu        sync.Mutex
+	rd        *pipe.Reader
+	cancelled bool
+	done      chan struct{} // closed when Run returns
+	// abort cancels the context returned by withCancel, e.g., to stop
+	// waiting in the queue of the scheduler.
+	abort context.CancelFunc
+}
+
+// withCancel returns a context derived from ctx, which is done when the job
+// is cancelled.
+func (j *localJob) withCancel(ctx context.Context) context.Context {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	ctx, j.abort = context.WithCancel(ctx)
+	if j.cancelled {
+		j.abort()
+	}
+	return ctx
+}
+
+func (j *localJob) setReader(rd *pipe.Reader) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.rd = rd
+	if j.cancelled {
+		rd.Cancel()
+	}
+}
+
+func (j *localJob) cancel() {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.cancelled = true
+	if j.abort != nil {
+		j.abort()
+	}
+	if j.rd != nil {
+		j.rd.Cancel()
+	}
+}
+
+func (j *localJob) isCancelled() bool {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.cancelled
+}
+
+// checkOwner returns PermissionDenied if the authenticated user of ctx
+// doesn't own the job id.
+func (j *localJob) checkOwner(ctx context.Context, id string) error {
+	if user := auth.FromContext(ctx); user != nil && user.UserID != j.owner {
+		return status.Errorf(codes.PermissionDenied, "job %s is not owned by %s", id, user.UserID)
+	}
+	return nil
+}
+
+// startLocalJob registers the request id of owner running in the local mode,
+// so that it could be cancelled.
+func (s *Server) startLocalJob(id, owner string) (*localJob, error) {
+	if s.jobs.get(id) != nil {
+		return nil, status.Errorf(codes.AlreadyExists, "job %s already exists", id)
+	}
+	job := &localJob{owner: owner, done: make(chan struct{})}
+	if _, loaded := s.localJobs.LoadOrStore(id, job); loaded {
+		return nil, status.Errorf(codes.AlreadyExists, "request %s is already running", id)
+	}
+	return job, nil
+}
+
+func (s *Server) finishLocalJob(id string, job *localJob) {
+	s.localJobs.Delete(id)
+	close(job.done)
+}
+
+// Cancel implements `rpc Cancel (Job) returns (CancelResponse)`. An
+// authenticated user cancels the own jobs and workflows only.
+func (s *Server) Cancel(ctx context.Context, job *pb.Job) (*pb.CancelResponse, error) {
+	if job.Id == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "job id is required to cancel")
+	}
+	if v, ok := s.localJobs.Load(job.Id); ok {
+		lj := v.(*localJob)
+		if e := lj.checkOwner(ctx, job.Id); e != nil {
+			return nil, e
+		}
+		lj.cancel()
+		select {
+		case <-lj.done:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		return &pb.CancelResponse{Job: job, Status: argo.StatusCancelled}, nil
+	}
+	if j := s.jobs.get(job.Id); j != nil {
+		if e := j.checkOwner(ctx, job.Id); e != nil {
+			return nil, e
+		}
+		return cancelSubmittedJob(ctx, job, j)
+	}
+	if e := s.checkWorkflow(ctx, job); e != nil {
+		return nil, e
+	}
+	// FIXME(tony): to make function cancel easily to mock, we should decouple
+	// server package with argo package by introducing s.cancel
+	return argo.Cancel(job)
+}
+
+func cancelSubmittedJob(ctx context.Context, job *pb.Job, j *submittedJob) (*pb.CancelResponse, error) {
+	j.cancel()
+	select {
+	case <-j.done:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	info := j.jobInfo()
+	if info.Status == JobSubmitted {
+		j.mu.Lock()
+		wf := j.workflow
+		j.mu.Unlock()
+		res, e := argo.Cancel(wf)
+		if res != nil {
+			res.Job = job
+		}
+		return res, e
+	}
+	return &pb.CancelResponse{Job: job, Status: info.Status}, nil
+}
@@ -0,0 +1,69 @@
+// Copyright 2020 The SQLFlow Authors. All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"sqlflow.org/sqlflow/go/database"
+	"sqlflow.org/sqlflow/go/model"
+	pb "sqlflow.org/sqlflow/go/proto"
+)
+
+// ListModels implements `rpc ListModels (ListModelsRequest) returns (ListModelsResponse)`
+func (s *Server) ListModels(ctx context.Context, req *pb.ListModelsRequest) (*pb.ListModelsResponse, error) {
+	if req.Session == nil {
+		return nil, fmt.Errorf("session is required to list models")
+	}
+	db, e := database.OpenAndConnectDB(req.Session.DbConnStr)
+	if e != nil {
+		return nil, e
+	}
+	defer db.Close()
+
+	filter := &model.CatalogFilter{
+		Name:      req.Name,
+		Owner:     req.Owner,
+		Estimator: req.Estimator,
+		Label:     req.Label,
+		Limit:     int(req.Limit),
+	}
+	if req.CreatedAfter > 0 {
+		filter.CreatedAfter = time.Unix(req.CreatedAfter, 0)
+	}
+	entries, e := model.ListCatalog(db, model.CatalogTable(), filter)
+	if e != nil {
+		return nil, e
+	}
+	res := &pb.ListModelsResponse{}
+	for _, entry := range entries {
+		res.Models = append(res.Models, &pb.ModelInfo{
+			Name:        entry.Name,
+			Owner:       entry.Owner,
+			Estimator:   entry.Estimator,
+			TrainSelect: entry.TrainSelect,
+			Attributes:  entry.Attributes,
+			Label:       entry.Label,
+			Features:    entry.Features,
+			CreatedAt:   entry.CreatedAt.Unix(),
+			Size:        entry.Size,
+			Version:     entry.Version,
+			StorageUri:  entry.StorageURI,
+			Lineage:     entry.Lineage,
+		})
+	}
+	return res, nil
+}
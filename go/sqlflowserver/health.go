@@ -0,0 +1,69 @@
+// Copyright 2020 The SQLFlow Authors. All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+
+	"sqlflow.org/sqlflow/go/database"
+)
+
+// dbChecker pings the database SQLFLOW_DATASOURCE.
+type dbChecker struct {
+	mu  sync.Mutex
+	url string // the data source of db
+	db  *database.DB
+}
+
+// open returns the database to check, reopening it if SQLFLOW_DATASOURCE
+// changes. It returns nil if SQLFLOW_DATASOURCE is unset.
+func (c *dbChecker) open() (*database.DB, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	url := os.Getenv("SQLFLOW_DATASOURCE")
+	if url == "" {
+		return nil, nil
+	}
+	if c.db != nil && c.url == url {
+		return c.db, nil
+	}
+	if c.db != nil {
+		c.db.Close()
+		c.db = nil
+	}
+	db, e := database.OpenDB(url)
+	if e != nil {
+		return nil, fmt.Errorf("failed to open database: %v", e)
+	}
+	c.url, c.db = url, db
+	return db, nil
+}
+
+// CheckDatabase returns an error if the database SQLFLOW_DATASOURCE doesn't
+// respond to pings. It returns nil if SQLFLOW_DATASOURCE is unset. The data
+// sources of the requests are never checked, so that a client couldn't fail
+// the health checking.
+func (s *Server) CheckDatabase(ctx context.Context) error {
+	db, e := s.db.open()
+	if e != nil || db == nil {
+		return e
+	}
+	if e := db.PingContext(ctx); e != nil {
+		return fmt.Errorf("failed to ping database: %v", e)
+	}
+	return nil
+}
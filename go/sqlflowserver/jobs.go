@@ -0,0 +1,339 @@
+// Copyright 2020 The SQLFlow Authors. All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"sqlflow.org/sqlflow/go/auth"
+	"sqlflow.org/sqlflow/go/log"
+	pb "sqlflow.org/sqlflow/go/proto"
+	"sqlflow.org/sqlflow/go/workflow/argo"
+	wfrsp "sqlflow.org/sqlflow/go/workflow/response"
+)
+
+// The status of the submitted jobs.
+const (
+	// JobQueued means the job is waiting in the queue of the scheduler.
+	JobQueued    = "Queued"
+	JobRunning   = "Running"
+	JobSucceeded = "Succeeded"
+	JobFailed    = "Failed"
+	// JobSubmitted means the job has submitted an Argo workflow, whose
+	// status is fetched from the cluster.
+	JobSubmitted = "Submitted"
+)
+
+// defaultJobTTL is how long a finished job is kept in the job store.
+const defaultJobTTL = 24 * time.Hour
+
+// defaultJobMaxResponses is how many responses a submitted job buffers,
+// the older ones are dropped.
+const defaultJobMaxResponses = 10000
+
+// submittedJob is a job started by Submit. It buffers the responses, so
+// that the client fetches them in a polling manner.
+type submittedJob struct {
+	localJob
+	info      *pb.JobInfo
+	responses []*pb.Response
+	// dropped is the number of the responses dropped to keep at most
+	// maxResponses ones, i.e. the offset of responses[0].
+	dropped      int
+	maxResponses int
+	err          error
+	// workflow is the Argo workflow submitted by the job in the workflow
+	// mode.
+	workflow *pb.Job
+}
+
+func newSubmittedJob(id string, req *pb.Request) *submittedJob {
+	j := &submittedJob{maxResponses: jobMaxResponses()}
+	j.owner = req.Session.UserId
+	j.done = make(chan struct{})
+	j.info = &pb.JobInfo{
+		Job:       &pb.Job{Id: id},
+		Status:    JobRunning,
+		Stmts:     req.Stmts,
+		UserId:    req.Session.UserId,
+		CreatedAt: time.Now().Unix(),
+	}
+	return j
+}
+
+func (j *submittedJob) append(res *pb.Response, wf *pb.Job) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.responses = append(j.responses, res)
+	if over := len(j.responses) - j.maxResponses; over > 0 {
+		j.responses = j.responses[over:]
+		j.dropped += over
+	}
+	if wf != nil {
+		j.workflow = wf
+	}
+}
+
+func (j *submittedJob) setStatus(status string) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.info.Status = status
+}
+
+func (j *submittedJob) finish(e error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	switch {
+	case j.cancelled:
+		j.info.Status = argo.StatusCancelled
+	case e != nil:
+		j.err = e
+		j.info.Status = JobFailed
+		j.info.Error = e.Error()
+	case j.workflow != nil:
+		j.info.Status = JobSubmitted
+	default:
+		j.info.Status = JobSucceeded
+	}
+	j.info.FinishedAt = time.Now().Unix()
+	close(j.done)
+}
+
+// jobInfo returns a copy of the job information.
+func (j *submittedJob) jobInfo() *pb.JobInfo {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return proto.Clone(j.info).(*pb.JobInfo)
+}
+
+// fetch returns the responses buffered after the offset req.StepId. The
+// updated offset is returned in the StepId of UpdatedFetchSince, and the job
+// status in its StepPhase. If some responses after the offset are dropped,
+// a message telling so is returned instead of them.
+func (j *submittedJob) fetch(req *pb.FetchRequest) (*pb.FetchResponse, error) {
+	j.mu.Lock()
+	wf := j.workflow
+	j.mu.Unlock()
+	if wf != nil {
+		return fetchWorkflow(req, wf)
+	}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	end := j.dropped + len(j.responses)
+	offset := 0
+	if req.StepId != "" {
+		var e error
+		if offset, e = strconv.Atoi(req.StepId); e != nil || offset < 0 || offset > end {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid fetch offset %s of job %s", req.StepId, req.Job.Id)
+		}
+	}
+	pending := []*pb.Response{}
+	if offset < j.dropped {
+		msg, _ := pb.EncodeMessage(fmt.Sprintf("%d responses of job %s are dropped, since a job keeps the latest %d responses only", j.dropped-offset, req.Job.Id, j.maxResponses))
+		pending = append(pending, msg)
+		offset = j.dropped
+	}
+	pending = append(pending, j.responses[offset-j.dropped:]...)
+	finished := j.info.Status != JobQueued && j.info.Status != JobRunning
+	if finished && len(pending) == 0 && j.err != nil {
+		return nil, j.err
+	}
+	// NOTE: a failed job returns its error only after all the responses
+	// are fetched.
+	eof := finished && j.err == nil
+	return wfrsp.NewFetchResponse(wfrsp.NewFetchRequest(req.Job.Id, req.Job.Namespace,
+		strconv.Itoa(end), j.info.Status), eof, pending), nil
+}
+
+// fetchWorkflow fetches the workflow wf on behalf of the submitted job.
+func fetchWorkflow(req *pb.FetchRequest, wf *pb.Job) (*pb.FetchResponse, error) {
+	wfReq := &pb.FetchRequest{Job: wf, StepId: req.StepId, StepPhase: req.StepPhase}
+	// The offsets of the submitted job are integers, which are never the
+	// step IDs of a workflow, so start fetching the workflow from the
+	// beginning.
+	if _, e := strconv.Atoi(req.StepId); e == nil {
+		wfReq.StepId, wfReq.StepPhase = "", ""
+	}
+	res, e := argo.Fetch(wfReq)
+	if res != nil && res.UpdatedFetchSince != nil {
+		res.UpdatedFetchSince.Job = req.Job
+	}
+	return res, e
+}
+
+// jobStore keeps the submitted jobs in memory. The finished jobs are
+// removed after SQLFLOW_JOB_TTL seconds.
+type jobStore struct {
+	mu   sync.Mutex
+	jobs map[string]*submittedJob
+}
+
+func jobTTL() time.Duration {
+	ttl := os.Getenv("SQLFLOW_JOB_TTL")
+	if ttl == "" {
+		return defaultJobTTL
+	}
+	seconds, e := strconv.Atoi(ttl)
+	if e != nil {
+		log.GetDefaultLogger().Errorf("SQLFLOW_JOB_TTL: %s should be int, using the default", ttl)
+		return defaultJobTTL
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+func jobMaxResponses() int {
+	s := os.Getenv("SQLFLOW_JOB_MAX_RESPONSES")
+	if s == "" {
+		return defaultJobMaxResponses
+	}
+	n, e := strconv.Atoi(s)
+	if e != nil || n <= 0 {
+		log.GetDefaultLogger().Errorf("SQLFLOW_JOB_MAX_RESPONSES: %s should be a positive int, using the default", s)
+		return defaultJobMaxResponses
+	}
+	return n
+}
+
+func (st *jobStore) add(id string, j *submittedJob) error {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	if st.jobs == nil {
+		st.jobs = make(map[string]*submittedJob)
+	}
+	expire := time.Now().Add(-jobTTL()).Unix()
+	for k, v := range st.jobs {
+		if info := v.jobInfo(); info.FinishedAt > 0 && info.FinishedAt < expire {
+			delete(st.jobs, k)
+		}
+	}
+	if _, ok := st.jobs[id]; ok {
+		return status.Errorf(codes.AlreadyExists, "job %s already exists", id)
+	}
+	st.jobs[id] = j
+	return nil
+}
+
+func (st *jobStore) get(id string) *submittedJob {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	return st.jobs[id]
+}
+
+func (st *jobStore) list(req *pb.ListJobsRequest) []*pb.JobInfo {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	infos := []*pb.JobInfo{}
+	for _, j := range st.jobs {
+		info := j.jobInfo()
+		if req.UserId != "" && info.UserId != req.UserId {
+			continue
+		}
+		if req.Status != "" && info.Status != req.Status {
+			continue
+		}
+		infos = append(infos, info)
+	}
+	sort.SliceStable(infos, func(i, k int) bool {
+		if infos[i].CreatedAt != infos[k].CreatedAt {
+			return infos[i].CreatedAt > infos[k].CreatedAt
+		}
+		return infos[i].Job.Id < infos[k].Job.Id
+	})
+	if req.Limit > 0 && int64(len(infos)) > req.Limit {
+		infos = infos[:req.Limit]
+	}
+	return infos
+}
+
+// Submit implements `rpc Submit (Request) returns (Job)`
+func (s *Server) Submit(ctx context.Context, req *pb.Request) (job *pb.Job, e error) {
+	if req.Session == nil {
+		return nil, fmt.Errorf("session is required to submit")
+	}
+	span := startSpan(ctx, req, "SQLFlow/Submit")
+	defer func() { span.End(e) }()
+	id := req.Id
+	if id == "" {
+		id = log.UUID()
+	}
+	if _, ok := s.localJobs.Load(id); ok {
+		return nil, status.Errorf(codes.AlreadyExists, "request %s is already running", id)
+	}
+	j := newSubmittedJob(id, req)
+	if e := s.inflight.add(j); e != nil {
+		return nil, e
+	}
+	if e := s.jobs.add(id, j); e != nil {
+		s.inflight.remove(j)
+		return nil, e
+	}
+	go s.runJob(req, j)
+	return &pb.Job{Id: id}, nil
+}
+
+func (s *Server) runJob(req *pb.Request, j *submittedJob) {
+	defer s.inflight.remove(j)
+	release, e := s.schedule(j.withCancel(context.Background()), req, func(res *pb.Response) error {
+		if res.GetQueueStatus().GetStatus() == queueStatusQueued {
+			j.setStatus(JobQueued)
+		}
+		j.append(res, nil)
+		return nil
+	})
+	if e != nil {
+		j.finish(e)
+		return
+	}
+	defer release()
+	j.setStatus(JobRunning)
+	rd := s.run(req.Stmts, req.Session)
+	defer rd.Close()
+	j.setReader(rd)
+	for r := range rd.ReadAll() {
+		res, e := encodeResponse(r, req)
+		if e != nil {
+			j.finish(e)
+			return
+		}
+		if res == nil {
+			continue
+		}
+		var wf *pb.Job
+		if job, ok := r.(pb.Job); ok {
+			wf = &job
+		}
+		s.addWorkflow(wf, req)
+		j.append(res, wf)
+	}
+	j.finish(nil)
+}
+
+// List implements `rpc List (ListJobsRequest) returns (ListJobsResponse)`.
+// An authenticated user lists the own jobs only.
+func (s *Server) List(ctx context.Context, req *pb.ListJobsRequest) (*pb.ListJobsResponse, error) {
+	if id := auth.FromContext(ctx); id != nil {
+		req.UserId = id.UserID
+	}
+	return &pb.ListJobsResponse{Jobs: s.jobs.list(req)}, nil
+}
@@ -0,0 +1,289 @@
+// Copyright 2020 The SQLFlow Authors. All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"sqlflow.org/sqlflow/go/log"
+	"sqlflow.org/sqlflow/go/metrics"
+	pb "sqlflow.org/sqlflow/go/proto"
+)
+
+// The status in QueueStatus.
+const (
+	queueStatusQueued  = "Queued"
+	queueStatusStarted = "Started"
+)
+
+// scheduler limits the number of the requests running at the same time,
+// globally and per user. The requests over the limits wait in a queue,
+// ordered by the arrival time, or by the priority first if priority is
+// true. Since the statements in a request run one by one, the limits are
+// also the numbers of the concurrent statements.
+type scheduler struct {
+	maxRunning        int // 0 means unlimited
+	maxRunningPerUser int // 0 means unlimited
+	priority          bool
+	// maxPriority caps the priorities of the requests of the users, and of
+	// the other users by "*". The priorities are capped at 0 by default.
+	maxPriority map[string]int32
+	// aging raises the priority of a queued request by 1 for every aging
+	// it waits, 0 means no aging.
+	aging time.Duration
+
+	mu          sync.Mutex
+	running     int
+	userRunning map[string]int
+	queue       []*ticket
+}
+
+type ticket struct {
+	user     string
+	priority int32
+	queuedAt time.Time
+	granted  bool
+	ready    chan struct{}
+}
+
+// newSchedulerFromEnv returns the scheduler configured by the environment
+// variables SQLFLOW_MAX_CONCURRENT_REQUESTS,
+// SQLFLOW_MAX_CONCURRENT_REQUESTS_PER_USER and SQLFLOW_SCHEDULING_POLICY,
+// which is fifo or priority. The priority policy is configured by
+// SQLFLOW_SCHEDULING_MAX_PRIORITY and SQLFLOW_SCHEDULING_AGING. It returns
+// nil if there is no limit.
+func newSchedulerFromEnv() *scheduler {
+	maxRunning := intFromEnv("SQLFLOW_MAX_CONCURRENT_REQUESTS")
+	maxRunningPerUser := intFromEnv("SQLFLOW_MAX_CONCURRENT_REQUESTS_PER_USER")
+	if maxRunning <= 0 && maxRunningPerUser <= 0 {
+		return nil
+	}
+	policy := os.Getenv("SQLFLOW_SCHEDULING_POLICY")
+	if policy != "" && policy != "fifo" && policy != "priority" {
+		log.GetDefaultLogger().Errorf("SQLFLOW_SCHEDULING_POLICY: %s should be fifo or priority, using fifo", policy)
+	}
+	s := newScheduler(maxRunning, maxRunningPerUser, policy == "priority")
+	s.maxPriority = maxPriorityFromEnv()
+	if aging := intFromEnv("SQLFLOW_SCHEDULING_AGING"); aging > 0 {
+		s.aging = time.Duration(aging) * time.Second
+	}
+	return s
+}
+
+// maxPriorityFromEnv parses SQLFLOW_SCHEDULING_MAX_PRIORITY, the comma
+// separated user:priority pairs, e.g. "alice:10,*:1".
+func maxPriorityFromEnv() map[string]int32 {
+	const name = "SQLFLOW_SCHEDULING_MAX_PRIORITY"
+	maxPriority := make(map[string]int32)
+	v := os.Getenv(name)
+	if v == "" {
+		return maxPriority
+	}
+	for _, pair := range strings.Split(v, ",") {
+		kv := strings.Split(pair, ":")
+		if len(kv) != 2 {
+			log.GetDefaultLogger().Errorf("%s: %s should be user:priority, ignored", name, pair)
+			continue
+		}
+		p, e := strconv.ParseInt(strings.TrimSpace(kv[1]), 10, 32)
+		if e != nil {
+			log.GetDefaultLogger().Errorf("%s: %s should be user:priority, ignored", name, pair)
+			continue
+		}
+		maxPriority[strings.TrimSpace(kv[0])] = int32(p)
+	}
+	return maxPriority
+}
+
+func intFromEnv(name string) int {
+	v := os.Getenv(name)
+	if v == "" {
+		return 0
+	}
+	i, e := strconv.Atoi(v)
+	if e != nil {
+		log.GetDefaultLogger().Errorf("%s: %s should be int, ignored", name, v)
+		return 0
+	}
+	return i
+}
+
+func newScheduler(maxRunning, maxRunningPerUser int, priority bool) *scheduler {
+	return &scheduler{
+		maxRunning:        maxRunning,
+		maxRunningPerUser: maxRunningPerUser,
+		priority:          priority,
+		maxPriority:       make(map[string]int32),
+		userRunning:       make(map[string]int),
+	}
+}
+
+// capPriority returns priority capped by s.maxPriority of user.
+func (s *scheduler) capPriority(user string, priority int32) int32 {
+	max, ok := s.maxPriority[user]
+	if !ok {
+		max = s.maxPriority["*"]
+	}
+	if priority > max {
+		return max
+	}
+	return priority
+}
+
+// effectivePriority returns the priority of t raised by the time it has
+// waited until now.
+func (s *scheduler) effectivePriority(t *ticket, now time.Time) int64 {
+	p := int64(t.priority)
+	if s.aging > 0 {
+		p += int64(now.Sub(t.queuedAt) / s.aging)
+	}
+	return p
+}
+
+// acquire waits until the request of user could run, and returns the
+// function to be called after the request finishes. The priority is capped
+// by s.maxPriority. If the request has to wait, it calls queued with the
+// number of the requests ahead of it in the queue. It returns the error if
+// ctx is done before the request could run.
+func (s *scheduler) acquire(ctx context.Context, user string, priority int32, queued func(ahead int)) (func(), error) {
+	s.mu.Lock()
+	t := &ticket{user: user, priority: s.capPriority(user, priority), queuedAt: time.Now(), ready: make(chan struct{})}
+	s.queue = append(s.queue, t)
+	s.dispatch()
+	ahead := 0
+	for _, q := range s.queue {
+		if q == t {
+			break
+		}
+		ahead++
+	}
+	s.mu.Unlock()
+
+	release := func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.running--
+		s.userRunning[user]--
+		if s.userRunning[user] == 0 {
+			delete(s.userRunning, user)
+		}
+		s.dispatch()
+	}
+	select {
+	case <-t.ready:
+		return release, nil
+	default:
+	}
+	queued(ahead)
+	metrics.QueuedJobs.Inc()
+	defer metrics.QueuedJobs.Dec()
+	select {
+	case <-t.ready:
+		return release, nil
+	case <-ctx.Done():
+		s.mu.Lock()
+		granted := t.granted
+		if !granted {
+			s.remove(t)
+		}
+		s.mu.Unlock()
+		if granted {
+			release()
+		}
+		return nil, ctx.Err()
+	}
+}
+
+// dispatch grants the queued tickets in order as long as the limits allow.
+// A ticket whose user reaches the per user limit doesn't block the tickets
+// behind it. With the priority policy, the tickets are ordered by the
+// priorities raised by aging, then by the arrival time.
+func (s *scheduler) dispatch() {
+	if s.priority {
+		now := time.Now()
+		sort.SliceStable(s.queue, func(i, j int) bool {
+			pi, pj := s.effectivePriority(s.queue[i], now), s.effectivePriority(s.queue[j], now)
+			if pi != pj {
+				return pi > pj
+			}
+			return s.queue[i].queuedAt.Before(s.queue[j].queuedAt)
+		})
+	}
+	for i := 0; i < len(s.queue); {
+		if s.maxRunning > 0 && s.running >= s.maxRunning {
+			return
+		}
+		t := s.queue[i]
+		if s.maxRunningPerUser > 0 && s.userRunning[t.user] >= s.maxRunningPerUser {
+			i++
+			continue
+		}
+		s.remove(t)
+		s.running++
+		s.userRunning[t.user]++
+		t.granted = true
+		close(t.ready)
+	}
+}
+
+func (s *scheduler) remove(t *ticket) {
+	for i, q := range s.queue {
+		if q == t {
+			s.queue = append(s.queue[:i], s.queue[i+1:]...)
+			return
+		}
+	}
+}
+
+func queueStatusResponse(status string, ahead int) *pb.Response {
+	return &pb.Response{Response: &pb.Response_QueueStatus{QueueStatus: &pb.QueueStatus{
+		Status: status,
+		Ahead:  int64(ahead),
+	}}}
+}
+
+// schedule waits until the request req could run by s.sched, sending the
+// queued and started status by send. It returns the function to be called
+// after the request finishes.
+func (s *Server) schedule(ctx context.Context, req *pb.Request, send func(*pb.Response) error) (func(), error) {
+	release := func() {}
+	if s.sched != nil {
+		queued := false
+		var e error
+		release, e = s.sched.acquire(ctx, req.Session.GetUserId(), req.Priority, func(ahead int) {
+			queued = true
+			send(queueStatusResponse(queueStatusQueued, ahead))
+		})
+		if e != nil {
+			return nil, e
+		}
+		if queued {
+			if e := send(queueStatusResponse(queueStatusStarted, 0)); e != nil {
+				release()
+				return nil, e
+			}
+		}
+	}
+	metrics.ActiveJobs.Inc()
+	return func() {
+		metrics.ActiveJobs.Dec()
+		release()
+	}, nil
+}
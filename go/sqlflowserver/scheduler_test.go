@@ -0,0 +1,193 @@
+// Copyright 2020 The SQLFlow Authors. All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	pb "sqlflow.org/sqlflow/go/proto"
+)
+
+// acquireAsync acquires in a goroutine, and returns the channel of the
+// release function, and the number of the requests ahead if queued.
+func acquireAsync(ctx context.Context, s *scheduler, user string, priority int32) (chan func(), chan int) {
+	released := make(chan func(), 1)
+	queued := make(chan int, 1)
+	go func() {
+		release, e := s.acquire(ctx, user, priority, func(ahead int) { queued <- ahead })
+		if e != nil {
+			close(released)
+			return
+		}
+		released <- release
+	}()
+	return released, queued
+}
+
+func TestSchedulerLimits(t *testing.T) {
+	a := assert.New(t)
+	s := newScheduler(2, 1, false)
+	ctx := context.Background()
+
+	alice, e := s.acquire(ctx, "alice", 0, func(int) { t.Fatal("should not be queued") })
+	a.NoError(e)
+	bob, e := s.acquire(ctx, "bob", 0, func(int) { t.Fatal("should not be queued") })
+	a.NoError(e)
+
+	// alice reaches the per user limit, and the server reaches the global limit
+	alice2, aliceQueued := acquireAsync(ctx, s, "alice", 0)
+	a.Equal(0, <-aliceQueued)
+	carol, carolQueued := acquireAsync(ctx, s, "carol", 0)
+	a.Equal(1, <-carolQueued)
+
+	// the queued request of alice is still over the per user limit, so carol
+	// runs first
+	bob()
+	release := <-carol
+	a.NotNil(release)
+	select {
+	case <-alice2:
+		t.Fatal("alice should be over the per user limit")
+	case <-time.After(50 * time.Millisecond):
+	}
+	alice()
+	(<-alice2)()
+	release()
+	a.Equal(0, s.running)
+	a.Empty(s.userRunning)
+	a.Empty(s.queue)
+}
+
+func TestSchedulerPriority(t *testing.T) {
+	a := assert.New(t)
+	s := newScheduler(1, 0, true)
+	s.maxPriority = map[string]int32{"bob": 2, "*": 1}
+	ctx := context.Background()
+	first, e := s.acquire(ctx, "alice", 0, func(int) {})
+	a.NoError(e)
+
+	low, lowQueued := acquireAsync(ctx, s, "alice", 1)
+	a.Equal(0, <-lowQueued)
+	high, highQueued := acquireAsync(ctx, s, "bob", 2)
+	a.Equal(0, <-highQueued)
+	a.Equal(2, len(s.queue))
+
+	first()
+	release := <-high
+	select {
+	case <-low:
+		t.Fatal("the request of the lower priority should wait")
+	case <-time.After(50 * time.Millisecond):
+	}
+	release()
+	(<-low)()
+
+	// the priority is capped per user
+	first, e = s.acquire(ctx, "alice", 0, func(int) {})
+	a.NoError(e)
+	capped, cappedQueued := acquireAsync(ctx, s, "carol", 100)
+	a.Equal(0, <-cappedQueued)
+	bob, bobQueued := acquireAsync(ctx, s, "bob", 100)
+	a.Equal(0, <-bobQueued)
+	a.Equal(int32(2), s.queue[0].priority)
+	a.Equal(int32(1), s.queue[1].priority)
+	first()
+	(<-bob)()
+	(<-capped)()
+}
+
+func TestSchedulerAging(t *testing.T) {
+	a := assert.New(t)
+	s := newScheduler(1, 0, true)
+	s.maxPriority = map[string]int32{"*": 2}
+	s.aging = 10 * time.Millisecond
+	ctx := context.Background()
+	first, e := s.acquire(ctx, "alice", 0, func(int) {})
+	a.NoError(e)
+
+	// the request of the lower priority runs first after waiting long
+	low, lowQueued := acquireAsync(ctx, s, "alice", 0)
+	a.Equal(0, <-lowQueued)
+	time.Sleep(100 * time.Millisecond)
+	high, highQueued := acquireAsync(ctx, s, "bob", 2)
+	a.Equal(1, <-highQueued)
+
+	first()
+	release := <-low
+	select {
+	case <-high:
+		t.Fatal("the request waiting longer should run first")
+	case <-time.After(50 * time.Millisecond):
+	}
+	release()
+	(<-high)()
+}
+
+func TestSchedulerCancel(t *testing.T) {
+	a := assert.New(t)
+	s := newScheduler(1, 0, false)
+	first, e := s.acquire(context.Background(), "alice", 0, func(int) {})
+	a.NoError(e)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	_, e = s.acquire(ctx, "bob", 0, func(int) { cancel() })
+	a.Equal(context.Canceled, e)
+	a.Empty(s.queue)
+	first()
+	a.Equal(0, s.running)
+}
+
+func TestServerSchedule(t *testing.T) {
+	a := assert.New(t)
+	os.Setenv("SQLFLOW_MAX_CONCURRENT_REQUESTS_PER_USER", "1")
+	defer os.Unsetenv("SQLFLOW_MAX_CONCURRENT_REQUESTS_PER_USER")
+	s := &Server{sched: newSchedulerFromEnv()}
+	a.NotNil(s.sched)
+	req := &pb.Request{Session: &pb.Session{UserId: "alice"}}
+
+	sent := make(chan *pb.Response, 2)
+	send := func(res *pb.Response) error {
+		sent <- res
+		return nil
+	}
+	release, e := s.schedule(context.Background(), req, send)
+	a.NoError(e)
+	a.Empty(sent)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		r, e := s.schedule(context.Background(), req, send)
+		a.NoError(e)
+		r()
+	}()
+	res := <-sent
+	a.Equal(queueStatusQueued, res.GetQueueStatus().Status)
+	a.Equal(int64(0), res.GetQueueStatus().Ahead)
+	release()
+	<-done
+	res = <-sent
+	a.Equal(queueStatusStarted, res.GetQueueStatus().Status)
+
+	os.Setenv("SQLFLOW_SCHEDULING_MAX_PRIORITY", "alice:10, *:1,bob")
+	defer os.Unsetenv("SQLFLOW_SCHEDULING_MAX_PRIORITY")
+	a.Equal(map[string]int32{"alice": 10, "*": 1}, newSchedulerFromEnv().maxPriority)
+
+	os.Unsetenv("SQLFLOW_MAX_CONCURRENT_REQUESTS_PER_USER")
+	a.Nil(newSchedulerFromEnv())
+}
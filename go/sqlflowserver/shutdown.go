@@ -0,0 +1,126 @@
+// Copyright 2020 The SQLFlow Authors. All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"sqlflow.org/sqlflow/go/log"
+)
+
+// cleanupTimeout bounds the time for the cancelled requests to kill the
+// subprocesses and remove the temporary files.
+const cleanupTimeout = 10 * time.Second
+
+// canceller is a running request, i.e., *localJob or *submittedJob.
+type canceller interface {
+	cancel()
+}
+
+// inflight tracks the running requests of Run and Submit, so that the
+// server could wait for them, or cancel them, before shutting down.
+type inflight struct {
+	mu       sync.Mutex
+	draining bool
+	running  map[canceller]struct{}
+	wg       sync.WaitGroup
+}
+
+// add adds the running request c. It fails if the server is draining.
+func (f *inflight) add(c canceller) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.draining {
+		return status.Errorf(codes.Unavailable, "the server is shutting down")
+	}
+	if f.running == nil {
+		f.running = map[canceller]struct{}{}
+	}
+	f.running[c] = struct{}{}
+	f.wg.Add(1)
+	return nil
+}
+
+func (f *inflight) remove(c canceller) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.running, c)
+	f.wg.Done()
+}
+
+func (f *inflight) drain() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.draining = true
+}
+
+func (f *inflight) isDraining() bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.draining
+}
+
+// cancelAll cancels the running requests and returns the number of them.
+func (f *inflight) cancelAll() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for c := range f.running {
+		c.cancel()
+	}
+	return len(f.running)
+}
+
+// wait waits for the running requests to finish until ctx is done.
+func (f *inflight) wait(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		f.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Drain rejects the new requests of Run and Submit with the code
+// Unavailable, and the running ones go on.
+func (s *Server) Drain() {
+	s.inflight.drain()
+}
+
+// Shutdown drains the server, and waits for the running requests to finish
+// until ctx is done. Then it cancels the requests not finished yet, which
+// kills their subprocesses, and waits for them to clean up.
+func (s *Server) Shutdown(ctx context.Context) error {
+	s.Drain()
+	if s.inflight.wait(ctx) == nil {
+		return nil
+	}
+	n := s.inflight.cancelAll()
+	log.GetDefaultLogger().Infof("cancelled %d running requests to shut down", n)
+	ctx, cancel := context.WithTimeout(context.Background(), cleanupTimeout)
+	defer cancel()
+	if e := s.inflight.wait(ctx); e != nil {
+		return fmt.Errorf("the cancelled requests didn't clean up in %v", cleanupTimeout)
+	}
+	return nil
+}
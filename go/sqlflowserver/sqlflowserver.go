@@ -23,6 +23,7 @@ import (
 	"os"
 	"os/exec"
 	"strings"
+	"sync"
 	"time"
 
 	"sqlflow.org/sqlflow/go/database"
@@ -31,85 +32,135 @@ import (
 	"sqlflow.org/sqlflow/go/workflow/argo"
 
 	"github.com/golang/protobuf/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 	submitter "sqlflow.org/sqlflow/go/executor"
 	"sqlflow.org/sqlflow/go/parser"
 	"sqlflow.org/sqlflow/go/pipe"
 	pb "sqlflow.org/sqlflow/go/proto"
 	sf "sqlflow.org/sqlflow/go/sql"
+	"sqlflow.org/sqlflow/go/tracing"
 )
 
 // Server is the instance will be used to connect to DB and execute training
 type Server struct {
 	run func(sql string, session *pb.Session) *pipe.Reader
+	// localJobs maps the ids of the running requests to *localJob.
+	localJobs sync.Map
+	// jobs keeps the jobs started by Submit.
+	jobs jobStore
+	// sched queues the requests over the concurrency limits, nil if there
+	// is no limit.
+	sched *scheduler
+	// inflight tracks the running requests to shut down gracefully.
+	inflight inflight
+	// argoMode tells if the jobs unknown to the server could be Argo
+	// workflows.
+	argoMode bool
+	// workflows keeps the workflows submitted in Argo mode.
+	workflows workflowStore
+	// db checks the database SQLFLOW_DATASOURCE for the health checking.
+	db dbChecker
 }
 
 // NewServer returns a server instance
 func NewServer(run func(string, *pb.Session) *pipe.Reader) *Server {
-	return &Server{run: run}
+	return &Server{run: run, sched: newSchedulerFromEnv()}
 }
 
-// Fetch implements `rpc Fetch (Job) returns(JobStatus)`
+// NewArgoServer returns a server instance which runs requests by Argo
+// workflows.
+func NewArgoServer() *Server {
+	s := NewServer(SubmitWorkflow)
+	s.argoMode = true
+	return s
+}
+
+// Fetch implements `rpc Fetch (Job) returns(JobStatus)`. An authenticated
+// user fetches the own submitted jobs and workflows only.
 func (s *Server) Fetch(ctx context.Context, job *pb.FetchRequest) (*pb.FetchResponse, error) {
+	if job.Job == nil {
+		return nil, status.Errorf(codes.InvalidArgument, "job is required to fetch")
+	}
+	if j := s.jobs.get(job.Job.Id); j != nil {
+		if e := j.checkOwner(ctx, job.Job.Id); e != nil {
+			return nil, e
+		}
+		return j.fetch(job)
+	}
+	if e := s.checkWorkflow(ctx, job.Job); e != nil {
+		return nil, e
+	}
 	// FIXME(tony): to make function fetch easily to mock, we should decouple server package
 	// with argo package by introducing s.fetch
 	return argo.Fetch(job)
 }
 
+// startSpan starts the span of req, whose parent is the traceparent in the
+// gRPC metadata of ctx or in req.Session, and sets req.Session.Traceparent to
+// the new span to propagate it.
+func startSpan(ctx context.Context, req *pb.Request, name string) *tracing.Span {
+	traceparent := req.GetSession().GetTraceparent()
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if tp := md.Get("traceparent"); len(tp) > 0 {
+			traceparent = tp[0]
+		}
+	}
+	ctx, span := tracing.StartWithTraceparent(traceparent, name)
+	if tp := tracing.Traceparent(ctx); tp != "" && req.Session != nil {
+		req.Session.Traceparent = tp
+	}
+	return span
+}
+
 // Run implements `rpc Run (Request) returns (stream Response)`
-func (s *Server) Run(req *pb.Request, stream pb.SQLFlow_RunServer) error {
+func (s *Server) Run(req *pb.Request, stream pb.SQLFlow_RunServer) (e error) {
+	span := startSpan(stream.Context(), req, "SQLFlow/Run")
+	defer func() { span.End(e) }()
+	var job *localJob
+	if req.Id != "" {
+		if job, e = s.startLocalJob(req.Id, req.GetSession().GetUserId()); e != nil {
+			return e
+		}
+		defer s.finishLocalJob(req.Id, job)
+	} else {
+		// the request without id could only be cancelled by Shutdown
+		job = &localJob{done: make(chan struct{})}
+	}
+	if e = s.inflight.add(job); e != nil {
+		return e
+	}
+	defer s.inflight.remove(job)
+	defer func() {
+		if !job.isCancelled() {
+			return
+		}
+		if s.inflight.isDraining() {
+			e = status.Errorf(codes.Unavailable, "the request is cancelled since the server is shutting down")
+		} else {
+			e = status.Errorf(codes.Canceled, "request %s is cancelled", req.Id)
+		}
+	}()
+	ctx := job.withCancel(stream.Context())
+	release, e := s.schedule(ctx, req, stream.Send)
+	if e != nil {
+		return e
+	}
+	defer release()
 	rd := s.run(req.Stmts, req.Session)
 	defer rd.Close()
+	job.setReader(rd)
 
 	for r := range rd.ReadAll() {
-		var res *pb.Response
-		var err error
-		switch s := r.(type) {
-		case error:
-			return s
-		case map[string]interface{}:
-			res, err = pb.EncodeHead(s)
-		case []interface{}:
-			res, err = pb.EncodeRow(s)
-		case submitter.Figures:
-			res, err = pb.EncodeMessage(s.Image)
-		case string:
-			res = &pb.Response{}
-			err = proto.UnmarshalText(s, res)
-			if err != nil {
-				res, err = pb.EncodeMessage(s)
-			}
-		case pb.Job:
-			res = &pb.Response{Response: &pb.Response_Job{Job: &s}}
-		case sf.EndOfExecution:
-			// FIXME(tony): decouple server package with sql package by introducing s.numberOfStatement
-			dialect, _, err := database.ParseURL(req.Session.DbConnStr)
-			if err != nil {
-				return err
-			}
-			sqls, err := parser.Parse(dialect, req.Stmts)
-			if err != nil {
-				return err
-			}
-			// if sqlStatements have only one field, do **NOT** return EndOfExecution message.
-			if len(sqls) > 1 {
-				eoeMsg := r.(sf.EndOfExecution)
-				eoe := &pb.EndOfExecution{
-					Sql:              eoeMsg.Statement,
-					SpentTimeSeconds: eoeMsg.EndTime - eoeMsg.StartTime,
-				}
-				eoeResponse := &pb.Response{Response: &pb.Response_Eoe{Eoe: eoe}}
-				if err := stream.Send(eoeResponse); err != nil {
-					return err
-				}
-			} else {
-				continue
-			}
-		default:
-			return fmt.Errorf("unrecognized run channel return type %#v", s)
-		}
+		res, err := encodeResponse(r, req)
 		if err != nil {
 			return err
 		}
+		if res == nil {
+			continue
+		}
+		s.addWorkflow(res.GetJob(), req)
 		if err := stream.Send(res); err != nil {
 			return err
 		}
@@ -117,12 +168,74 @@ func (s *Server) Run(req *pb.Request, stream pb.SQLFlow_RunServer) error {
 	return nil
 }
 
+// addWorkflow records the workflow job submitted by req in Argo mode. job is
+// nil if the response isn't a workflow.
+func (s *Server) addWorkflow(job *pb.Job, req *pb.Request) {
+	if job != nil && s.argoMode {
+		s.workflows.add(job.Id, req.GetSession().GetUserId(), job.Namespace)
+	}
+}
+
+// encodeResponse encodes r read from the pipe returned by s.run as a
+// Response. It returns the error if r is an error, and returns nil if r
+// should not be sent to the client.
+func encodeResponse(r interface{}, req *pb.Request) (*pb.Response, error) {
+	switch s := r.(type) {
+	case error:
+		return nil, s
+	case map[string]interface{}:
+		return pb.EncodeHead(s)
+	case []interface{}:
+		return pb.EncodeRow(s)
+	case submitter.Figures:
+		return pb.EncodeMessage(s.Image)
+	case submitter.Progress:
+		return &pb.Response{Response: &pb.Response_Progress{Progress: &pb.Progress{
+			Epoch:      s.Epoch,
+			Step:       s.Step,
+			Metric:     s.Metric,
+			Value:      s.Value,
+			EtaSeconds: s.ETASeconds,
+		}}}, nil
+	case string:
+		res := &pb.Response{}
+		if err := proto.UnmarshalText(s, res); err != nil {
+			return pb.EncodeMessage(s)
+		}
+		return res, nil
+	case pb.Job:
+		return &pb.Response{Response: &pb.Response_Job{Job: &s}}, nil
+	case sf.EndOfExecution:
+		// FIXME(tony): decouple server package with sql package by introducing s.numberOfStatement
+		dialect, _, err := database.ParseURL(req.Session.DbConnStr)
+		if err != nil {
+			return nil, err
+		}
+		sqls, err := parser.Parse(dialect, req.Stmts)
+		if err != nil {
+			return nil, err
+		}
+		// if sqlStatements have only one field, do **NOT** return EndOfExecution message.
+		if len(sqls) <= 1 {
+			return nil, nil
+		}
+		eoe := &pb.EndOfExecution{
+			Sql:              s.Statement,
+			SpentTimeSeconds: s.EndTime - s.StartTime,
+		}
+		return &pb.Response{Response: &pb.Response_Eoe{Eoe: eoe}}, nil
+	default:
+		return nil, fmt.Errorf("unrecognized run channel return type %#v", s)
+	}
+}
+
 // SubmitWorkflow submits an Argo workflow
 //
 // TODO(wangkuiyi): Make SubmitWorkflow return an error in addition to
 // *pipe.Reader, and remove the calls to log.Printf.
 func SubmitWorkflow(sqlProgram string, session *pb.Session) *pipe.Reader {
-	logger := log.WithFields(log.Fields{
+	ctx := tracing.ContextWithTraceparent(context.Background(), session.Traceparent)
+	logger := tracing.Logger(ctx, log.Fields{
 		"requestID": log.UUID(),
 		"user":      session.UserId,
 		"submitter": session.Submitter,
@@ -141,11 +254,16 @@ func SubmitWorkflow(sqlProgram string, session *pb.Session) *pipe.Reader {
 		defer wr.Close()
 		var yaml string
 		var err error
+		// submitErr is the error to submit the workflow, recorded in the
+		// job history.
+		var submitErr error
+		defer func() { sf.RecordSubmission(ctx, sqlProgram, session, startTime, submitErr) }()
 		if useCoulerSubmitter {
 			var pycode string
 			pycode, err = workflow.CompileToCoulerSubmitCode(sqlProgram, session, logger)
 			if err != nil {
 				logger.Printf("compile error: %v", err)
+				submitErr = err
 				if e := wr.Write(err); e != nil {
 					logger.Errorf("piping error: %v", e)
 				}
@@ -158,6 +276,7 @@ func SubmitWorkflow(sqlProgram string, session *pb.Session) *pipe.Reader {
 			out, err := cmd.CombinedOutput()
 			if err != nil {
 				logger.Printf("run couler program to submit: %v", err)
+				submitErr = err
 				if e := wr.Write(err); e != nil {
 					logger.Errorf("piping error: %v", e)
 				}
@@ -173,6 +292,7 @@ func SubmitWorkflow(sqlProgram string, session *pb.Session) *pipe.Reader {
 			yaml, err = workflow.CompileToYAML(sqlProgram, session, logger)
 			if err != nil {
 				logger.Printf("compile error: %v", err)
+				submitErr = err
 				if e := wr.Write(err); e != nil {
 					logger.Errorf("piping error: %v", e)
 				}
@@ -182,6 +302,7 @@ func SubmitWorkflow(sqlProgram string, session *pb.Session) *pipe.Reader {
 			yaml, err = workflow.CompileToYAMLExperimental(sqlProgram, session)
 			if err != nil {
 				logger.Printf("compile error: %v", err)
+				submitErr = err
 				if e := wr.Write(err); e != nil {
 					logger.Errorf("piping error: %v", e)
 				}
@@ -189,10 +310,11 @@ func SubmitWorkflow(sqlProgram string, session *pb.Session) *pipe.Reader {
 			}
 		}
 
-		wfID, e := argo.Submit(yaml)
+		wfID, e := argo.Submit(ctx, yaml)
 		defer logger.Infof("submitted, workflowID:%s, namespace:%s, spent:%.f, SQL:%s, error:%v",
 			wfID, session.WfNamespace, time.Since(startTime).Seconds(), sqlProgram, e)
 		if e != nil {
+			submitErr = e
 			if e := wr.Write(e); e != nil {
 				logger.Errorf("piping error: %v", e)
 			}
@@ -0,0 +1,94 @@
+// Copyright 2020 The SQLFlow Authors. All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"sqlflow.org/sqlflow/go/auth"
+	pb "sqlflow.org/sqlflow/go/proto"
+)
+
+// workflow is an Argo workflow submitted by a request.
+type workflow struct {
+	owner     string
+	namespace string // the wf_namespace of the session of the request
+	accessed  time.Time
+}
+
+// workflowStore keeps the owners and the namespaces of the workflows
+// submitted by the requests in memory, so that a user fetches and cancels
+// the own workflows only. A workflow is removed if it isn't accessed for
+// SQLFLOW_JOB_TTL seconds.
+type workflowStore struct {
+	mu        sync.Mutex
+	workflows map[string]*workflow
+}
+
+func (st *workflowStore) add(id, owner, namespace string) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	if st.workflows == nil {
+		st.workflows = make(map[string]*workflow)
+	}
+	now := time.Now()
+	expire := now.Add(-jobTTL())
+	for k, w := range st.workflows {
+		if w.accessed.Before(expire) {
+			delete(st.workflows, k)
+		}
+	}
+	st.workflows[id] = &workflow{owner: owner, namespace: namespace, accessed: now}
+}
+
+func (st *workflowStore) get(id string) *workflow {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	w := st.workflows[id]
+	if w != nil {
+		w.accessed = time.Now()
+	}
+	return w
+}
+
+// checkWorkflow returns an error if the workflow job couldn't be fetched or
+// cancelled by the authenticated user of ctx. The workflow must be
+// submitted by the user in Argo mode, and job.Namespace must be the
+// namespace it is submitted to. Without authentication, the workflows
+// unknown to the server, e.g., submitted before the server restarts, are
+// allowed.
+func (s *Server) checkWorkflow(ctx context.Context, job *pb.Job) error {
+	if !s.argoMode {
+		return status.Errorf(codes.NotFound, "job %s not found", job.Id)
+	}
+	user := auth.FromContext(ctx)
+	w := s.workflows.get(job.Id)
+	if w == nil {
+		if user != nil {
+			return status.Errorf(codes.NotFound, "workflow %s not found", job.Id)
+		}
+		return nil
+	}
+	if user != nil && user.UserID != w.owner {
+		return status.Errorf(codes.PermissionDenied, "workflow %s is not owned by %s", job.Id, user.UserID)
+	}
+	if job.Namespace != w.namespace {
+		return status.Errorf(codes.PermissionDenied, "workflow %s is not in namespace %s", job.Id, job.Namespace)
+	}
+	return nil
+}
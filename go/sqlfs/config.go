@@ -0,0 +1,44 @@
+// Copyright 2020 The SQLFlow Authors. All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlfs
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+)
+
+const (
+	// EnvReadConcurrency is the environment variable of the number of
+	// concurrent queries a reader fetches blocks with.
+	EnvReadConcurrency = "SQLFLOW_SQLFS_READ_CONCURRENCY"
+	// EnvWriteBatchSize is the environment variable of the number of
+	// blocks a writer inserts in one statement.
+	EnvWriteBatchSize = "SQLFLOW_SQLFS_WRITE_BATCH_SIZE"
+
+	defaultReadConcurrency = 4
+	defaultWriteBatchSize  = 16
+)
+
+func envPositiveInt(name string, defaultValue int) (int, error) {
+	s := os.Getenv(name)
+	if s == "" {
+		return defaultValue, nil
+	}
+	n, e := strconv.Atoi(s)
+	if e != nil || n <= 0 {
+		return 0, fmt.Errorf("%s should be a positive integer, got %q", name, s)
+	}
+	return n, nil
+}
@@ -19,6 +19,9 @@ import (
 	"fmt"
 	"io"
 	"sort"
+	"sync"
+
+	"sqlflow.org/sqlflow/go/metrics"
 )
 
 type fragment struct {
@@ -35,13 +38,50 @@ type reader struct {
 	fragmentIdx int
 	rowIdx      int
 	rowBufSize  int
+	concurrency int  // the number of queries to fetch blocks concurrently
+	eof         bool // if the last block has been fetched
 }
 
+// readNextFragments fetches the next concurrency*rowBufSize blocks by
+// concurrent queries and reassembles them in the order of id.
 func (r *reader) readNextFragments() error {
-	stmt := fmt.Sprintf("SELECT id,block FROM %s WHERE id>=%d AND id<%d;", r.table, r.rowIdx, r.rowIdx+r.rowBufSize)
+	results := make([][]*fragment, r.concurrency)
+	errs := make([]error, r.concurrency)
+	var wg sync.WaitGroup
+	for i := 0; i < r.concurrency; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i], errs[i] = r.fetchFragments(r.rowIdx + i*r.rowBufSize)
+		}(i)
+	}
+	wg.Wait()
+
+	var fragments []*fragment
+	for i, result := range results {
+		if errs[i] != nil {
+			return errs[i]
+		}
+		fragments = append(fragments, result...)
+	}
+	for i, f := range fragments {
+		if f.id != i+r.rowIdx {
+			return fmt.Errorf("invalid sqlfs db table %s", r.table)
+		}
+	}
+	r.fragments = fragments
+	r.rowIdx += len(r.fragments)
+	r.fragmentIdx = 0
+	r.eof = len(r.fragments) < r.concurrency*r.rowBufSize
+	return nil
+}
+
+// fetchFragments fetches at most rowBufSize blocks starting from id start.
+func (r *reader) fetchFragments(start int) ([]*fragment, error) {
+	stmt := fmt.Sprintf("SELECT id,block FROM %s WHERE id>=%d AND id<%d;", r.table, start, start+r.rowBufSize)
 	rows, err := r.db.Query(stmt)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	defer rows.Close()
 
@@ -49,33 +89,27 @@ func (r *reader) readNextFragments() error {
 	for rows.Next() {
 		f := &fragment{}
 		if err := rows.Scan(&f.id, &f.block); err != nil {
-			return err
+			return nil, err
 		}
 		fragments = append(fragments, f)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
 
 	if len(fragments) > r.rowBufSize {
-		return fmt.Errorf("invalid sqlfs db table %s", r.table)
+		return nil, fmt.Errorf("invalid sqlfs db table %s", r.table)
 	}
 
 	sort.Slice(fragments, func(i, j int) bool {
 		return fragments[i].id < fragments[j].id
 	})
-
-	for i, f := range fragments {
-		if f.id != i+r.rowIdx {
-			return fmt.Errorf("invalid sqlfs db table %s", r.table)
-		}
-	}
-	r.fragments = fragments
-	r.rowIdx += len(r.fragments)
-	r.fragmentIdx = 0
-	return nil
+	return fragments, nil
 }
 
 func (r *reader) nextBlock() (string, error) {
 	if r.fragmentIdx == len(r.fragments) {
-		if r.rowIdx > 0 && len(r.fragments) < r.rowBufSize {
+		if r.eof {
 			// reset r.fragments and r.fragmentIdx when EOF
 			r.fragments = nil
 			r.fragmentIdx = 0
@@ -98,7 +132,9 @@ func (r *reader) nextBlock() (string, error) {
 	return block, nil
 }
 
-// Open returns a reader to read from the given table in db.
+// Open returns a reader to read from the given table in db. The reader
+// fetches rowBufSize blocks by each query, and runs SQLFLOW_SQLFS_READ_CONCURRENCY
+// queries concurrently.
 func Open(db *sql.DB, table string, rowBufSize int) (io.ReadCloser, error) {
 	has, e := hasTable(db, table)
 	if !has {
@@ -110,6 +146,10 @@ func Open(db *sql.DB, table string, rowBufSize int) (io.ReadCloser, error) {
 	if rowBufSize <= 0 {
 		return nil, fmt.Errorf("rowBufSize must be larger than 0")
 	}
+	concurrency, e := envPositiveInt(EnvReadConcurrency, defaultReadConcurrency)
+	if e != nil {
+		return nil, e
+	}
 
 	return &reader{
 		db:          db,
@@ -119,6 +159,7 @@ func Open(db *sql.DB, table string, rowBufSize int) (io.ReadCloser, error) {
 		fragmentIdx: 0,
 		rowIdx:      0,
 		rowBufSize:  rowBufSize,
+		concurrency: concurrency,
 	}, nil
 }
 
@@ -144,6 +185,7 @@ func (r *reader) Read(p []byte) (n int, e error) {
 			}
 		}
 	}
+	metrics.SQLFSBytes.WithLabelValues("read").Add(float64(n))
 	if e == io.EOF && n > 0 {
 		return n, nil
 	}
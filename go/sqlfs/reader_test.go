@@ -14,9 +14,12 @@
 package sqlfs
 
 import (
+	"bytes"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"math/rand"
+	"os"
 	"strings"
 	"testing"
 
@@ -88,3 +91,37 @@ func caseSQLFSWriteAndRead(t *testing.T, rowBufSize int) {
 
 	a.NoError(dropTableIfExists(db.DB, tbl))
 }
+
+func TestSQLFSConcurrentReadAndBatchedWrite(t *testing.T) {
+	a := assert.New(t)
+	createSQLFSTestingDatabaseOnce.Do(createSQLFSTestingDatabase)
+	db := database.GetTestingDBSingleton()
+
+	os.Setenv(EnvWriteBatchSize, "3")
+	defer os.Unsetenv(EnvWriteBatchSize)
+	tbl := fmt.Sprintf("%s.unittest%d", testDatabaseName, rand.Int())
+	w, e := Create(db, tbl, database.GetSessionFromTestingDB())
+	a.NoError(e)
+	// 50 blocks and a half, which are not a multiple of the batch size.
+	data := make([]byte, bufSize*50+bufSize/2)
+	rand.Read(data)
+	n, e := w.Write(data)
+	a.NoError(e)
+	a.Equal(len(data), n)
+	a.NoError(w.Close())
+	defer dropTableIfExists(db.DB, tbl)
+
+	for _, concurrency := range []string{"1", "3", "64"} {
+		os.Setenv(EnvReadConcurrency, concurrency)
+		r, e := Open(db.DB, tbl, 2)
+		a.NoError(e)
+		read, e := ioutil.ReadAll(r)
+		a.NoError(e)
+		a.True(bytes.Equal(data, read), "concurrency %s", concurrency)
+		a.NoError(r.Close())
+	}
+	os.Setenv(EnvReadConcurrency, "0")
+	_, e = Open(db.DB, tbl, 2)
+	a.Error(e)
+	os.Unsetenv(EnvReadConcurrency)
+}
@@ -18,28 +18,44 @@ import (
 	"encoding/base64"
 	"fmt"
 	"io"
+	"strings"
 
 	"sqlflow.org/sqlflow/go/database"
 )
 
-func flushToSQLTable(db *sql.DB, table string) func([]byte) error {
+// flushToSQLTable returns a flush function that inserts blocks into table
+// by batches of batchSize rows, and a wrapup function that inserts the
+// last batch.
+func flushToSQLTable(db *sql.DB, table string, batchSize int) (func([]byte) error, func() error) {
 	row := 0
-	return func(buf []byte) error {
+	var values []string
+	insert := func() error {
+		if len(values) == 0 {
+			return nil
+		}
+		query := fmt.Sprintf("INSERT INTO %s (id, block) VALUES%s", table, strings.Join(values, ","))
+		if _, e := db.Exec(query); e != nil {
+			return fmt.Errorf("cannot flush to table %s: %v", table, e)
+		}
+		values = values[:0]
+		return nil
+	}
+	flush := func(buf []byte) error {
 		if db == nil {
 			return fmt.Errorf("flushToSQLTable: no database connection")
 		}
 
 		if len(buf) > 0 {
 			block := base64.StdEncoding.EncodeToString(buf)
-			query := fmt.Sprintf("INSERT INTO %s (id, block) VALUES(%d, '%s')",
-				table, row, block)
-			if _, e := db.Exec(query); e != nil {
-				return fmt.Errorf("cannot flush to table %s: %v", table, e)
-			}
+			values = append(values, fmt.Sprintf("(%d, '%s')", row, block))
 			row++
+			if len(values) >= batchSize {
+				return insert()
+			}
 		}
 		return nil
 	}
+	return flush, insert
 }
 
 func noopWrapUp() error {
@@ -47,11 +63,16 @@ func noopWrapUp() error {
 }
 
 func newSQLWriter(db *database.DB, table string, bufSize int) (io.WriteCloser, error) {
+	batchSize, e := envPositiveInt(EnvWriteBatchSize, defaultWriteBatchSize)
+	if e != nil {
+		return nil, e
+	}
 	if e := dropTableIfExists(db.DB, table); e != nil {
 		return nil, fmt.Errorf("cannot drop table %s: %v", table, e)
 	}
 	if e := createTable(db, table); e != nil {
 		return nil, fmt.Errorf("cannot create table %s: %v", table, e)
 	}
-	return newFlushWriteCloser(flushToSQLTable(db.DB, table), noopWrapUp, bufSize), nil
+	flush, wrapup := flushToSQLTable(db.DB, table, batchSize)
+	return newFlushWriteCloser(flush, wrapup, bufSize), nil
 }
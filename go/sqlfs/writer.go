@@ -17,6 +17,7 @@ import (
 	"io"
 
 	"sqlflow.org/sqlflow/go/database"
+	"sqlflow.org/sqlflow/go/metrics"
 	pb "sqlflow.org/sqlflow/go/proto"
 )
 
@@ -25,8 +26,26 @@ const bufSize = 32 * 1024
 // Create creates a new table or truncates an existing table and
 // returns a writer.
 func Create(db *database.DB, table string, session *pb.Session) (io.WriteCloser, error) {
+	var w io.WriteCloser
+	var e error
 	if db.DriverName == "hive" {
-		return newHiveWriter(db, table, bufSize)
+		w, e = newHiveWriter(db, table, bufSize)
+	} else {
+		w, e = newSQLWriter(db, table, bufSize)
 	}
-	return newSQLWriter(db, table, bufSize)
+	if e != nil {
+		return nil, e
+	}
+	return &meteredWriter{w}, nil
+}
+
+// meteredWriter counts the bytes written to sqlfs in the metrics.
+type meteredWriter struct {
+	io.WriteCloser
+}
+
+func (w *meteredWriter) Write(p []byte) (int, error) {
+	n, e := w.WriteCloser.Write(p)
+	metrics.SQLFSBytes.WithLabelValues("write").Add(float64(n))
+	return n, e
 }
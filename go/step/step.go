@@ -25,6 +25,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/golang/protobuf/proto"
 	"github.com/mattn/go-sixel"
 	"sqlflow.org/sqlflow/go/executor"
 	pb "sqlflow.org/sqlflow/go/proto"
@@ -69,6 +70,20 @@ func Render(rsp interface{}, table tablewriter.TableWriter, isTerminal, it2Check
 			log.Fatalf("workflow step failed: %v", s)
 		}
 	case sql.EndOfExecution:
+	case executor.Progress:
+		if isTerminal {
+			log.Println(s)
+		} else {
+			// print the progress in protobuf text format, so that
+			// Fetch could decode it from the workflow step logs.
+			log.Println(proto.CompactTextString(&pb.Response{Response: &pb.Response_Progress{Progress: &pb.Progress{
+				Epoch:      s.Epoch,
+				Step:       s.Step,
+				Metric:     s.Metric,
+				Value:      s.Value,
+				EtaSeconds: s.ETASeconds,
+			}}}))
+		}
 	case executor.Figures:
 		if isHTMLCode(s.Image) {
 			if !isTerminal {
@@ -17,23 +17,31 @@ import (
 	"archive/tar"
 	"bytes"
 	"compress/gzip"
+	"fmt"
 	"io"
+	"io/ioutil"
 	"os"
 	"path/filepath"
+	"strings"
 )
 
 // ZipDir tar a given directory into file 'output'
 func ZipDir(dir string, output string) error {
+	// NOTE: compress into a buffer first, so that output is not included
+	// if it's under dir.
 	var buf bytes.Buffer
-	if err := compress(dir, &buf); err != nil {
+	if err := compress(dir, &buf, func(file string) (string, error) {
+		return filepath.ToSlash(file), nil
+	}); err != nil {
 		return err
 	}
 
 	// write the .tar.gzip
-	fileToWrite, err := os.OpenFile(output, os.O_CREATE|os.O_RDWR, os.FileMode(0644))
+	fileToWrite, err := os.OpenFile(output, os.O_CREATE|os.O_RDWR|os.O_TRUNC, os.FileMode(0644))
 	if err != nil {
 		return err
 	}
+	defer fileToWrite.Close()
 	if _, err := io.Copy(fileToWrite, &buf); err != nil {
 		return err
 	}
@@ -42,44 +50,95 @@ func ZipDir(dir string, output string) error {
 
 // UnzipDir untar a compressed file into directory 'output'
 func UnzipDir(tarball string, output string) error {
-	_, err := os.Stat(tarball)
-	if os.IsNotExist(err) {
-		return err
-	}
-
 	rd, err := os.Open(tarball)
 	if err != nil {
 		return err
 	}
+	defer rd.Close()
 	return uncompress(rd, output)
 }
 
-func compress(src string, buf io.Writer) error {
+// Compress writes the files under dir to w as a tar.gz stream, with the
+// file names relative to dir like `tar czf - -C dir .` does.
+func Compress(dir string, w io.Writer) error {
+	return compress(dir, w, func(file string) (string, error) {
+		rel, err := filepath.Rel(dir, file)
+		if err != nil {
+			return "", err
+		}
+		if rel == "." {
+			return "./", nil
+		}
+		return "./" + filepath.ToSlash(rel), nil
+	})
+}
+
+// Uncompress extracts the tar.gz stream r into directory dst. It fails if
+// any file in the stream would be extracted out of dst.
+func Uncompress(r io.Reader, dst string) error {
+	return uncompress(r, dst)
+}
+
+// ExtractFile returns the content of the file name in the tar.gz stream r.
+// It stops reading r once the file is found.
+func ExtractFile(r io.Reader, name string) ([]byte, error) {
+	zr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+	tr := tar.NewReader(zr)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil, fmt.Errorf("%s not found in the tarball", name)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if header.Typeflag == tar.TypeReg && filepath.Clean(header.Name) == filepath.Clean(name) {
+			return ioutil.ReadAll(tr)
+		}
+	}
+}
+
+func compress(src string, buf io.Writer, name func(file string) (string, error)) error {
 	// tar > gzip > buf
 	zr := gzip.NewWriter(buf)
 	tw := tar.NewWriter(zr)
 
 	// walk through every file in the folder
 	err := filepath.Walk(src, func(file string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		link := ""
+		if fi.Mode()&os.ModeSymlink != 0 {
+			if link, err = os.Readlink(file); err != nil {
+				return err
+			}
+		}
 		// generate tar header
-		header, err := tar.FileInfoHeader(fi, file)
+		header, err := tar.FileInfoHeader(fi, link)
 		if err != nil {
 			return err
 		}
 		// must provide real name
 		// (see https://golang.org/src/archive/tar/common.go?#L626)
-		header.Name = filepath.ToSlash(file)
+		if header.Name, err = name(file); err != nil {
+			return err
+		}
 
 		// write header
 		if err := tw.WriteHeader(header); err != nil {
 			return err
 		}
-		// if not a dir, write file content
-		if !fi.IsDir() {
+		// if a regular file, write file content
+		if fi.Mode().IsRegular() {
 			data, err := os.Open(file)
 			if err != nil {
 				return err
 			}
+			defer data.Close()
 			if _, err := io.Copy(tw, data); err != nil {
 				return err
 			}
@@ -101,6 +160,44 @@ func compress(src string, buf io.Writer) error {
 	return nil
 }
 
+// extractPath returns the path to extract the file name in a tarball to,
+// or an error if the path is out of dst.
+func extractPath(dst, name string) (string, error) {
+	dst = filepath.Clean(dst)
+	target := filepath.Join(dst, name)
+	if target != dst && !strings.HasPrefix(target, dst+string(os.PathSeparator)) {
+		return "", fmt.Errorf("illegal file path in tarball: %s", name)
+	}
+	return target, nil
+}
+
+// checkSymlinks returns an error if any existing component of the path
+// target under dst is a symbolic link, so that an extracted symbolic link,
+// which points into dst by itself, can't be chained with another one to
+// write out of dst.
+func checkSymlinks(dst, target string) error {
+	dst = filepath.Clean(dst)
+	rel, err := filepath.Rel(dst, target)
+	if err != nil || rel == "." {
+		return err
+	}
+	p := dst
+	for _, c := range strings.Split(rel, string(os.PathSeparator)) {
+		p = filepath.Join(p, c)
+		fi, err := os.Lstat(p)
+		if os.IsNotExist(err) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if fi.Mode()&os.ModeSymlink != 0 {
+			return fmt.Errorf("illegal file path through symbolic link in tarball: %s", target)
+		}
+	}
+	return nil
+}
+
 func uncompress(src io.Reader, dst string) error {
 	// ungzip
 	zr, err := gzip.NewReader(src)
@@ -121,7 +218,13 @@ func uncompress(src io.Reader, dst string) error {
 		}
 
 		// add dst + re-format slashes according to system
-		target := filepath.Join(dst, header.Name)
+		target, err := extractPath(dst, header.Name)
+		if err != nil {
+			return err
+		}
+		if err := checkSymlinks(dst, target); err != nil {
+			return err
+		}
 
 		// check the type
 		switch header.Typeflag {
@@ -135,17 +238,51 @@ func uncompress(src io.Reader, dst string) error {
 			}
 		// if it's a file create it (with same permission)
 		case tar.TypeReg:
-			fileToWrite, err := os.OpenFile(target, os.O_CREATE|os.O_RDWR, os.FileMode(header.Mode))
+			if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+				return err
+			}
+			fileToWrite, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, os.FileMode(header.Mode))
 			if err != nil {
 				return err
 			}
 			// copy over contents
 			if _, err := io.Copy(fileToWrite, tr); err != nil {
+				fileToWrite.Close()
 				return err
 			}
 			// manually close here after each file operation; defering would cause each file close
 			// to wait until all operations have completed.
-			fileToWrite.Close()
+			if err := fileToWrite.Close(); err != nil {
+				return err
+			}
+		// links must point into dst too
+		case tar.TypeSymlink:
+			if filepath.IsAbs(header.Linkname) {
+				return fmt.Errorf("illegal symbolic link in tarball: %s -> %s", header.Name, header.Linkname)
+			}
+			if _, err := extractPath(dst, filepath.Join(filepath.Dir(header.Name), header.Linkname)); err != nil {
+				return fmt.Errorf("illegal symbolic link in tarball: %s -> %s", header.Name, header.Linkname)
+			}
+			if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+				return err
+			}
+			if err := os.Symlink(header.Linkname, target); err != nil {
+				return err
+			}
+		case tar.TypeLink:
+			source, err := extractPath(dst, header.Linkname)
+			if err == nil {
+				err = checkSymlinks(dst, source)
+			}
+			if err != nil {
+				return fmt.Errorf("illegal hard link in tarball: %s -> %s", header.Name, header.Linkname)
+			}
+			if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+				return err
+			}
+			if err := os.Link(source, target); err != nil {
+				return err
+			}
 		}
 	}
 	return nil
@@ -14,9 +14,13 @@
 package tar
 
 import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
 	"fmt"
 	"io/ioutil"
 	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -74,3 +78,102 @@ func TestTarUnTarDir(t *testing.T) {
 	a.Equal(pythonFile, string(buf))
 
 }
+
+func TestCompressUncompress(t *testing.T) {
+	a := assert.New(t)
+	src, err := ioutil.TempDir("/tmp", "sqlflow_tar")
+	a.NoError(err)
+	defer os.RemoveAll(src)
+	a.NoError(os.Mkdir(filepath.Join(src, "sub_dir"), 0755))
+	a.NoError(ioutil.WriteFile(filepath.Join(src, "README.md"), []byte(readmeMdFile), 0644))
+	a.NoError(ioutil.WriteFile(filepath.Join(src, "sub_dir", "main.py"), []byte(pythonFile), 0755))
+	a.NoError(os.Symlink("sub_dir/main.py", filepath.Join(src, "main.py")))
+
+	var buf bytes.Buffer
+	a.NoError(Compress(src, &buf))
+
+	content, err := ExtractFile(bytes.NewReader(buf.Bytes()), "./sub_dir/main.py")
+	a.NoError(err)
+	a.Equal(pythonFile, string(content))
+	content, err = ExtractFile(bytes.NewReader(buf.Bytes()), "README.md")
+	a.NoError(err)
+	a.Equal(readmeMdFile, string(content))
+	_, err = ExtractFile(bytes.NewReader(buf.Bytes()), "no_such_file")
+	a.Error(err)
+
+	dst, err := ioutil.TempDir("/tmp", "sqlflow_tar")
+	a.NoError(err)
+	defer os.RemoveAll(dst)
+	a.NoError(Uncompress(&buf, dst))
+	content, err = ioutil.ReadFile(filepath.Join(dst, "main.py"))
+	a.NoError(err)
+	a.Equal(pythonFile, string(content))
+	fi, err := os.Stat(filepath.Join(dst, "sub_dir", "main.py"))
+	a.NoError(err)
+	a.Equal(os.FileMode(0755), fi.Mode().Perm())
+}
+
+func tarball(headers ...*tar.Header) *bytes.Buffer {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(zw)
+	for _, h := range headers {
+		tw.WriteHeader(h)
+	}
+	tw.Close()
+	zw.Close()
+	return &buf
+}
+
+func TestUncompressPathTraversal(t *testing.T) {
+	a := assert.New(t)
+	dst, err := ioutil.TempDir("/tmp", "sqlflow_tar")
+	a.NoError(err)
+	defer os.RemoveAll(dst)
+
+	for _, h := range []*tar.Header{
+		{Name: "../evil", Typeflag: tar.TypeReg, Mode: 0644},
+		{Name: "./a/../../evil", Typeflag: tar.TypeReg, Mode: 0644},
+		{Name: "link", Typeflag: tar.TypeSymlink, Linkname: "/etc"},
+		{Name: "a/link", Typeflag: tar.TypeSymlink, Linkname: "../../etc"},
+		{Name: "hard", Typeflag: tar.TypeLink, Linkname: "../evil"},
+	} {
+		err := Uncompress(tarball(h), dst)
+		a.Error(err)
+		a.Contains(err.Error(), "illegal")
+	}
+	_, err = os.Stat(filepath.Join(filepath.Dir(dst), "evil"))
+	a.True(os.IsNotExist(err))
+
+	a.NoError(Uncompress(tarball(&tar.Header{Name: "/abs", Typeflag: tar.TypeReg, Mode: 0644}), dst))
+	a.FileExists(filepath.Join(dst, "abs"))
+}
+
+func TestUncompressChainedSymlinks(t *testing.T) {
+	a := assert.New(t)
+	dst, err := ioutil.TempDir("/tmp", "sqlflow_tar")
+	a.NoError(err)
+	defer os.RemoveAll(dst)
+	dst = filepath.Join(dst, "model")
+
+	// each link points into dst, but a/b/c resolves to the parent of dst
+	err = Uncompress(tarball(
+		&tar.Header{Name: "a/", Typeflag: tar.TypeDir, Mode: 0755},
+		&tar.Header{Name: "a/b", Typeflag: tar.TypeSymlink, Linkname: ".."},
+		&tar.Header{Name: "a/b/c", Typeflag: tar.TypeSymlink, Linkname: ".."},
+		&tar.Header{Name: "a/b/c/x", Typeflag: tar.TypeReg, Mode: 0644},
+	), dst)
+	a.Error(err)
+	a.Contains(err.Error(), "illegal")
+	_, err = os.Lstat(filepath.Join(filepath.Dir(dst), "x"))
+	a.True(os.IsNotExist(err))
+
+	// a file written through a symbolic link
+	a.NoError(os.RemoveAll(dst))
+	err = Uncompress(tarball(
+		&tar.Header{Name: "link", Typeflag: tar.TypeSymlink, Linkname: "dir"},
+		&tar.Header{Name: "link/x", Typeflag: tar.TypeReg, Mode: 0644},
+	), dst)
+	a.Error(err)
+	a.Contains(err.Error(), "illegal")
+}
@@ -0,0 +1,146 @@
+// Copyright 2020 The SQLFlow Authors. All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"sync"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+const (
+	defaultOTLPEndpoint = "http://localhost:4318/v1/traces"
+	defaultServiceName  = "sqlflow"
+)
+
+var (
+	mu       sync.RWMutex
+	provider *sdktrace.TracerProvider
+	// file is the file of the file exporter, closed by Shutdown.
+	file io.Closer
+)
+
+func currentProvider() *sdktrace.TracerProvider {
+	mu.RLock()
+	defer mu.RUnlock()
+	return provider
+}
+
+// setProvider sets the tracer provider, nil to disable the tracing.
+func setProvider(tp *sdktrace.TracerProvider, f io.Closer) {
+	mu.Lock()
+	defer mu.Unlock()
+	provider, file = tp, f
+}
+
+// InitFromEnv sets the exporter by the environment variable
+// SQLFLOW_TRACING_EXPORTER, which is one of:
+//
+//   - stdout writes the spans to the standard output.
+//   - file writes the spans to the file SQLFLOW_TRACING_FILE.
+//   - otlp sends the spans to the OTLP/HTTP endpoint
+//     SQLFLOW_TRACING_OTLP_ENDPOINT, e.g., an OpenTelemetry collector.
+//
+// The tracing is disabled if it is empty. The service name of the spans is
+// SQLFLOW_TRACING_SERVICE_NAME, or sqlflow by default.
+func InitFromEnv() error {
+	service := os.Getenv("SQLFLOW_TRACING_SERVICE_NAME")
+	if service == "" {
+		service = defaultServiceName
+	}
+	var exporter sdktrace.SpanExporter
+	var closer io.Closer
+	var e error
+	switch typ := os.Getenv("SQLFLOW_TRACING_EXPORTER"); typ {
+	case "":
+		setProvider(nil, nil)
+		return nil
+	case "stdout":
+		exporter, e = stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+	case "file":
+		fn := os.Getenv("SQLFLOW_TRACING_FILE")
+		if fn == "" {
+			return fmt.Errorf("SQLFLOW_TRACING_FILE is required by the file exporter")
+		}
+		f, err := os.OpenFile(fn, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+		if err != nil {
+			return fmt.Errorf("cannot open the tracing file: %v", err)
+		}
+		closer = f
+		exporter, e = stdouttrace.New(stdouttrace.WithWriter(f))
+	case "otlp":
+		endpoint := os.Getenv("SQLFLOW_TRACING_OTLP_ENDPOINT")
+		if endpoint == "" {
+			endpoint = defaultOTLPEndpoint
+		}
+		exporter, e = newOTLPExporter(endpoint)
+	default:
+		return fmt.Errorf("unknown SQLFLOW_TRACING_EXPORTER %s, should be stdout, file or otlp", typ)
+	}
+	if e != nil {
+		if closer != nil {
+			closer.Close()
+		}
+		return fmt.Errorf("cannot create the tracing exporter: %v", e)
+	}
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewSchemaless(attribute.String("service.name", service))))
+	setProvider(tp, closer)
+	return nil
+}
+
+// newOTLPExporter returns the exporter sending the spans to the OTLP/HTTP
+// endpoint, e.g., http://localhost:4318/v1/traces.
+func newOTLPExporter(endpoint string) (sdktrace.SpanExporter, error) {
+	u, e := url.Parse(endpoint)
+	if e != nil || u.Host == "" {
+		return nil, fmt.Errorf("invalid SQLFLOW_TRACING_OTLP_ENDPOINT %s", endpoint)
+	}
+	opts := []otlptracehttp.Option{otlptracehttp.WithEndpoint(u.Host)}
+	if u.Path != "" {
+		opts = append(opts, otlptracehttp.WithURLPath(u.Path))
+	}
+	if u.Scheme == "http" {
+		opts = append(opts, otlptracehttp.WithInsecure())
+	}
+	return otlptracehttp.New(context.Background(), opts...)
+}
+
+// Shutdown exports the spans not exported yet, and disables the tracing.
+func Shutdown(ctx context.Context) error {
+	mu.Lock()
+	tp, f := provider, file
+	provider, file = nil, nil
+	mu.Unlock()
+	if tp == nil {
+		return nil
+	}
+	e := tp.Shutdown(ctx)
+	if f != nil {
+		if err := f.Close(); err != nil && e == nil {
+			e = err
+		}
+	}
+	return e
+}
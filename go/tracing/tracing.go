@@ -0,0 +1,154 @@
+// Copyright 2020 The SQLFlow Authors. All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package tracing traces the execution of the SQL programs by OpenTelemetry.
+// The traces are propagated by the W3C Trace Context, e.g., the traceparent
+// in the gRPC metadata, the session and the environment variables of the
+// subprocesses.
+package tracing
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	"sqlflow.org/sqlflow/go/log"
+)
+
+const tracerName = "sqlflow.org/sqlflow/go/tracing"
+
+var propagator = propagation.TraceContext{}
+
+// ContextWithTraceparent returns a context derived from ctx, whose parent
+// span is the remote one in traceparent, e.g.,
+// 00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01. It returns ctx if
+// traceparent is empty or invalid.
+func ContextWithTraceparent(ctx context.Context, traceparent string) context.Context {
+	if traceparent == "" {
+		return ctx
+	}
+	return propagator.Extract(ctx, propagation.MapCarrier{"traceparent": traceparent})
+}
+
+// Traceparent returns the W3C traceparent of the current span in ctx, or ""
+// if there is no span.
+func Traceparent(ctx context.Context) string {
+	carrier := propagation.MapCarrier{}
+	propagator.Inject(ctx, carrier)
+	return carrier.Get("traceparent")
+}
+
+// TraceID returns the hex encoded trace ID in ctx, or "" if there is no span.
+func TraceID(ctx context.Context) string {
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		return sc.TraceID().String()
+	}
+	return ""
+}
+
+// Environ returns the environment variables TRACEPARENT and SQLFLOW_TRACE_ID
+// to propagate the trace in ctx to a subprocess.
+func Environ(ctx context.Context) []string {
+	tp := Traceparent(ctx)
+	if tp == "" {
+		return nil
+	}
+	return []string{
+		"TRACEPARENT=" + tp,
+		"SQLFLOW_TRACE_ID=" + TraceID(ctx),
+	}
+}
+
+// Logger returns the logger with fields and the field traceID of the trace
+// in ctx if there is one.
+func Logger(ctx context.Context, fields log.Fields) *log.Logger {
+	f := log.Fields{}
+	for k, v := range fields {
+		f[k] = v
+	}
+	if id := TraceID(ctx); id != "" {
+		f["traceID"] = id
+	}
+	return log.WithFields(f)
+}
+
+// Span is an operation in a trace. A nil *Span does nothing, which is
+// returned by Start if the tracing is disabled.
+type Span struct {
+	span trace.Span
+}
+
+// Start starts a span named name, whose parent is the span in ctx, and
+// returns the context with the new span. If there is no span in ctx, it
+// starts a new trace. The span is exported after calling End.
+func Start(ctx context.Context, name string) (context.Context, *Span) {
+	tp := currentProvider()
+	if tp == nil {
+		return ctx, nil
+	}
+	ctx, span := tp.Tracer(tracerName).Start(ctx, name)
+	return ctx, &Span{span: span}
+}
+
+// StartWithTraceparent starts a span whose parent is the remote one in
+// traceparent, e.g., the traceparent in the session.
+func StartWithTraceparent(traceparent, name string) (context.Context, *Span) {
+	return Start(ContextWithTraceparent(context.Background(), traceparent), name)
+}
+
+// SetAttribute sets the attribute key of the span to value, which is a
+// string, a bool, an integer or a float.
+func (s *Span) SetAttribute(key string, value interface{}) {
+	if s == nil {
+		return
+	}
+	s.span.SetAttributes(attributeOf(key, value))
+}
+
+// End ends the span, which fails if e is not nil, and exports it. Calling End
+// more than once does nothing.
+func (s *Span) End(e error) {
+	if s == nil || !s.span.IsRecording() {
+		return
+	}
+	if e != nil {
+		s.span.SetStatus(codes.Error, e.Error())
+	} else {
+		s.span.SetStatus(codes.Ok, "")
+	}
+	s.span.End()
+}
+
+func attributeOf(key string, value interface{}) attribute.KeyValue {
+	switch v := value.(type) {
+	case string:
+		return attribute.String(key, v)
+	case bool:
+		return attribute.Bool(key, v)
+	case int:
+		return attribute.Int(key, v)
+	case int32:
+		return attribute.Int64(key, int64(v))
+	case int64:
+		return attribute.Int64(key, v)
+	case float32:
+		return attribute.Float64(key, float64(v))
+	case float64:
+		return attribute.Float64(key, v)
+	default:
+		return attribute.String(key, fmt.Sprint(v))
+	}
+}
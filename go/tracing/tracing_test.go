@@ -0,0 +1,174 @@
+// Copyright 2020 The SQLFlow Authors. All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tracing
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	collectortrace "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+const testTraceparent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+func TestTraceparent(t *testing.T) {
+	a := assert.New(t)
+	ctx := ContextWithTraceparent(context.Background(), testTraceparent)
+	a.Equal(testTraceparent, Traceparent(ctx))
+	a.Equal("4bf92f3577b34da6a3ce929d0e0e4736", TraceID(ctx))
+	a.Equal([]string{
+		"TRACEPARENT=" + testTraceparent,
+		"SQLFLOW_TRACE_ID=4bf92f3577b34da6a3ce929d0e0e4736",
+	}, Environ(ctx))
+	a.Equal("4bf92f3577b34da6a3ce929d0e0e4736", Logger(ctx, nil).Data["traceID"])
+
+	for _, tp := range []string{
+		"",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7",
+		"00-4bf92f3577b34da6a3ce929d0e0e473x-00f067aa0ba902b7-01",
+		"00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+	} {
+		a.Equal("", Traceparent(ContextWithTraceparent(context.Background(), tp)))
+	}
+	a.Nil(Environ(context.Background()))
+	a.NotContains(Logger(context.Background(), nil).Data, "traceID")
+}
+
+func TestStart(t *testing.T) {
+	a := assert.New(t)
+	// the spans do nothing if the tracing is disabled
+	setProvider(nil, nil)
+	ctx, span := StartWithTraceparent(testTraceparent, "disabled")
+	a.Nil(span)
+	span.SetAttribute("key", "value")
+	span.End(nil)
+	a.Equal(testTraceparent, Traceparent(ctx))
+
+	exporter := tracetest.NewInMemoryExporter()
+	setProvider(sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)), nil)
+	defer setProvider(nil, nil)
+	ctx, root := StartWithTraceparent(testTraceparent, "root")
+	a.Equal("4bf92f3577b34da6a3ce929d0e0e4736", TraceID(ctx))
+	a.NotEqual(testTraceparent, Traceparent(ctx))
+	_, child := Start(ctx, "child")
+	child.SetAttribute("dialect", "mysql")
+	child.SetAttribute("statements", 2)
+	child.End(fmt.Errorf("failed"))
+	child.End(nil)
+	root.End(nil)
+
+	spans := exporter.GetSpans()
+	a.Equal(2, len(spans))
+	c, r := spans[0], spans[1]
+	a.Equal("child", c.Name)
+	a.Equal(r.SpanContext.TraceID(), c.SpanContext.TraceID())
+	a.Equal(r.SpanContext.SpanID(), c.Parent.SpanID())
+	a.Equal("00f067aa0ba902b7", r.Parent.SpanID().String())
+	a.Equal(sdktrace.Status{Code: codes.Error, Description: "failed"}, c.Status)
+	a.Equal(codes.Ok, r.Status.Code)
+	a.Equal([]attribute.KeyValue{attribute.String("dialect", "mysql"), attribute.Int("statements", 2)}, c.Attributes)
+	a.False(c.EndTime.Before(c.StartTime))
+
+	// a new trace starts without the parent
+	_, span = Start(context.Background(), "new")
+	span.End(nil)
+	spans = exporter.GetSpans()
+	a.NotEqual(r.SpanContext.TraceID(), spans[2].SpanContext.TraceID())
+	a.False(spans[2].Parent.IsValid())
+}
+
+func TestFileExporter(t *testing.T) {
+	a := assert.New(t)
+	dir, e := ioutil.TempDir("/tmp", "sqlflow_tracing")
+	a.NoError(e)
+	defer os.RemoveAll(dir)
+	fn := filepath.Join(dir, "spans.json")
+	os.Setenv("SQLFLOW_TRACING_EXPORTER", "file")
+	os.Setenv("SQLFLOW_TRACING_FILE", fn)
+	defer os.Unsetenv("SQLFLOW_TRACING_EXPORTER")
+	defer os.Unsetenv("SQLFLOW_TRACING_FILE")
+	a.NoError(InitFromEnv())
+
+	_, span := StartWithTraceparent(testTraceparent, "parser.Parse")
+	span.SetAttribute("dialect", "mysql")
+	span.End(fmt.Errorf("syntax error"))
+	a.NoError(Shutdown(context.Background()))
+
+	b, e := ioutil.ReadFile(fn)
+	a.NoError(e)
+	lines := strings.Split(strings.TrimSpace(string(b)), "\n")
+	a.Equal(1, len(lines))
+	s := map[string]interface{}{}
+	a.NoError(json.Unmarshal([]byte(lines[0]), &s))
+	a.Equal("parser.Parse", s["Name"])
+	a.Equal("4bf92f3577b34da6a3ce929d0e0e4736", s["SpanContext"].(map[string]interface{})["TraceID"])
+	a.Equal("00f067aa0ba902b7", s["Parent"].(map[string]interface{})["SpanID"])
+	a.Equal(map[string]interface{}{"Code": "Error", "Description": "syntax error"}, s["Status"])
+	a.Contains(string(b), `"service.name"`)
+
+	os.Setenv("SQLFLOW_TRACING_EXPORTER", "file")
+	os.Setenv("SQLFLOW_TRACING_FILE", "")
+	a.Error(InitFromEnv())
+	os.Setenv("SQLFLOW_TRACING_EXPORTER", "jaeger")
+	a.Error(InitFromEnv())
+}
+
+func TestOTLPExporter(t *testing.T) {
+	a := assert.New(t)
+	received := make(chan *collectortrace.ExportTraceServiceRequest, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		a.Equal("/v1/traces", r.URL.Path)
+		b, e := ioutil.ReadAll(r.Body)
+		a.NoError(e)
+		req := &collectortrace.ExportTraceServiceRequest{}
+		a.NoError(proto.Unmarshal(b, req))
+		received <- req
+	}))
+	defer srv.Close()
+	os.Setenv("SQLFLOW_TRACING_EXPORTER", "otlp")
+	os.Setenv("SQLFLOW_TRACING_OTLP_ENDPOINT", srv.URL+"/v1/traces")
+	defer os.Unsetenv("SQLFLOW_TRACING_EXPORTER")
+	defer os.Unsetenv("SQLFLOW_TRACING_OTLP_ENDPOINT")
+	a.NoError(InitFromEnv())
+
+	ctx, root := Start(context.Background(), "root")
+	_, child := Start(ctx, "child")
+	child.End(nil)
+	root.End(nil)
+	a.NoError(Shutdown(context.Background()))
+
+	req := <-received
+	spans := req.ResourceSpans[0].ScopeSpans[0].Spans
+	a.Equal(2, len(spans))
+	a.Equal(spans[1].SpanId, spans[0].ParentSpanId)
+	a.Equal("service.name", req.ResourceSpans[0].Resource.Attributes[0].Key)
+	a.Equal("sqlflow", req.ResourceSpans[0].Resource.Attributes[0].Value.GetStringValue())
+	// the spans after shutting down are dropped
+	_, span := Start(ctx, "after")
+	a.Nil(span)
+}
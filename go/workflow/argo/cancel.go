@@ -0,0 +1,69 @@
+// Copyright 2020 The SQLFlow Authors. All rights reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package argo
+
+import (
+	"time"
+
+	"sqlflow.org/sqlflow/go/log"
+	pb "sqlflow.org/sqlflow/go/proto"
+)
+
+// StatusCancelled is the final status of a job stopped by Cancel.
+const StatusCancelled = "Cancelled"
+
+var (
+	// cancelTimeout is how long Cancel waits for a terminated workflow to stop.
+	cancelTimeout      = 30 * time.Second
+	cancelPollInterval = time.Second
+)
+
+// Cancel terminates the workflow of job, waits for it to stop, and deletes the
+// workflow and its pods. If the workflow has completed before, Cancel leaves it
+// as is and returns its phase, so that its logs could still be fetched.
+func Cancel(job *pb.Job) (*pb.CancelResponse, error) {
+	logger := log.WithFields(log.Fields{
+		"requestID": log.UUID(),
+		"jobID":     job.Id,
+		"namespace": job.Namespace,
+		"event":     "cancel",
+	})
+	wf, e := k8sReadWorkflow(job.Id, job.Namespace)
+	if e != nil {
+		return nil, e
+	}
+	if isWorkflowCompleted(wf) {
+		return &pb.CancelResponse{Job: job, Status: string(wf.Status.Phase)}, nil
+	}
+	if e := k8sTerminateWorkflow(job.Id, job.Namespace); e != nil {
+		return nil, e
+	}
+	for deadline := time.Now().Add(cancelTimeout); time.Now().Before(deadline); {
+		if wf, e = k8sReadWorkflow(job.Id, job.Namespace); e != nil || isWorkflowCompleted(wf) {
+			break
+		}
+		time.Sleep(cancelPollInterval)
+	}
+	// NOTE: Kubernetes deletes the pods of a workflow with the workflow by
+	// the owner references, delete them explicitly in case that the garbage
+	// collection is disabled or slow.
+	if e := k8sDeleteWorkflow(job.Id, job.Namespace); e != nil {
+		return nil, e
+	}
+	if e := k8sDeleteWorkflowPods(job.Id, job.Namespace); e != nil {
+		return nil, e
+	}
+	logger.Infof("cancelled")
+	return &pb.CancelResponse{Job: job, Status: StatusCancelled}, nil
+}
@@ -22,6 +22,7 @@ import (
 
 	"github.com/argoproj/argo/pkg/apis/workflow/v1alpha1"
 	"sqlflow.org/sqlflow/go/log"
+	"sqlflow.org/sqlflow/go/metrics"
 	pb "sqlflow.org/sqlflow/go/proto"
 	wfrsp "sqlflow.org/sqlflow/go/workflow/response"
 )
@@ -72,6 +73,13 @@ func logViewURL(ns, wfID, podID string) (string, error) {
 // Step [2/3] Log: http://localhost:8001/workflows/default/steps-bdpff?nodeId=steps-bdpff-xx2
 // ...
 func Fetch(req *pb.FetchRequest) (*pb.FetchResponse, error) {
+	start := time.Now()
+	res, e := fetch(req)
+	metrics.ObserveArgo("fetch", start, e)
+	return res, e
+}
+
+func fetch(req *pb.FetchRequest) (*pb.FetchResponse, error) {
 	logger := log.WithFields(log.Fields{
 		"requestID": log.UUID(),
 		"jobID":     req.Job.Id,
@@ -300,3 +300,24 @@ func TestGetPodLogsStress(t *testing.T) {
 	}
 	a.Equal(expected, actual)
 }
+
+func TestCancel(t *testing.T) {
+	a := assert.New(t)
+	if os.Getenv("SQLFLOW_TEST") != "workflow" {
+		t.Skip("argo: skip workflow tests")
+	}
+	if os.Getenv("SQLFLOW_WORKFLOW_STEP_IMAGE") != "" {
+		stepImage = os.Getenv("SQLFLOW_WORKFLOW_STEP_IMAGE")
+	}
+	ds := os.Getenv("SQLFLOW_TEST_DATASOURCE")
+	workflowID, err := k8sCreateResource(fmt.Sprintf(stepYAML, stepImage, ds, stepImage))
+	a.NoError(err)
+	defer k8sDeleteWorkflow(workflowID, "")
+
+	res, err := Cancel(&pb.Job{Id: workflowID})
+	a.NoError(err)
+	a.Equal(workflowID, res.Job.Id)
+	a.Equal(StatusCancelled, res.Status)
+	_, err = k8sReadWorkflow(workflowID, "")
+	a.Error(err)
+}
@@ -105,3 +105,32 @@ func k8sDeleteWorkflow(workflowID, namespace string) error {
 	}
 	return nil
 }
+
+// k8sTerminateWorkflow terminates a running workflow like `argo terminate`
+// does, by setting its activeDeadlineSeconds to 0.
+func k8sTerminateWorkflow(workflowID, namespace string) error {
+	if namespace == "" {
+		namespace = "default"
+	}
+	cmd := exec.Command("kubectl", "-n", namespace, "patch", "workflow", workflowID,
+		"--type", "merge", "-p", `{"spec":{"activeDeadlineSeconds":0}}`)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("failed %s, %v\n%s", cmd, err, string(output))
+	}
+	return nil
+}
+
+// k8sDeleteWorkflowPods deletes the pods created by a workflow.
+func k8sDeleteWorkflowPods(workflowID, namespace string) error {
+	if namespace == "" {
+		namespace = "default"
+	}
+	cmd := exec.Command("kubectl", "-n", namespace, "delete", "pod",
+		"-l", "workflows.argoproj.io/workflow="+workflowID, "--ignore-not-found")
+	_, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("failed %s, %v", cmd, err)
+	}
+	return nil
+}
@@ -13,7 +13,21 @@
 
 package argo
 
+import (
+	"context"
+	"time"
+
+	"sqlflow.org/sqlflow/go/metrics"
+	"sqlflow.org/sqlflow/go/tracing"
+)
+
 // Submit the Argo workflow and returns the workflow ID
-func Submit(argoYAMLContent string) (string, error) {
-	return k8sCreateResource(argoYAMLContent)
+func Submit(ctx context.Context, argoYAMLContent string) (string, error) {
+	_, span := tracing.Start(ctx, "argo.Submit")
+	start := time.Now()
+	id, e := k8sCreateResource(argoYAMLContent)
+	metrics.ObserveArgo("submit", start, e)
+	span.SetAttribute("workflow", id)
+	span.End(e)
+	return id, e
 }
@@ -49,6 +49,12 @@ func isWorkflowPending(wf *wfv1.Workflow) bool {
 	return wf.Status.Phase == wfv1.NodePending || wf.Status.Phase == ""
 }
 
+func isWorkflowCompleted(wf *wfv1.Workflow) bool {
+	return wf.Status.Phase == wfv1.NodeSucceeded ||
+		wf.Status.Phase == wfv1.NodeFailed ||
+		wf.Status.Phase == wfv1.NodeError
+}
+
 func getPodNameByStepGroup(wf *wfv1.Workflow, stepGroupName string) (string, error) {
 	stepGroupNode, ok := wf.Status.Nodes[stepGroupName]
 	if !ok {
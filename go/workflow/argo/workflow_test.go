@@ -344,6 +344,19 @@ func TestUnmarshal(t *testing.T) {
 	a.Equal(wf.Status.Phase, wfv1.NodePhase("Succeeded"))
 }
 
+func TestIsWorkflowCompleted(t *testing.T) {
+	a := assert.New(t)
+	wf, err := parseWorkflowResource([]byte(testWorkflowDescription))
+	a.NoError(err)
+	a.True(isWorkflowCompleted(wf))
+	for _, phase := range []wfv1.NodePhase{"", wfv1.NodePending, wfv1.NodeRunning} {
+		wf.Status.Phase = phase
+		a.False(isWorkflowCompleted(wf))
+	}
+	wf.Status.Phase = wfv1.NodeFailed
+	a.True(isWorkflowCompleted(wf))
+}
+
 func TestGetStepGroup(t *testing.T) {
 	if os.Getenv("SQLFLOW_TEST") != "workflow" {
 		t.Skip("argo: skip workflow tests")
@@ -47,6 +47,7 @@ func fillEnvFromSession(envs *map[string]string, session *pb.Session) {
 	fillMapIfValueNotEmpty(*envs, "SQLFLOW_HADOOP_USER", session.HdfsUser)
 	fillMapIfValueNotEmpty(*envs, "SQLFLOW_HADOOP_PASS", session.HdfsUser)
 	fillMapIfValueNotEmpty(*envs, "SQLFLOW_submitter", session.Submitter)
+	fillMapIfValueNotEmpty(*envs, "TRACEPARENT", session.Traceparent)
 }
 
 // GetStepEnvs returns a map of envs used for couler workflow.
@@ -181,7 +182,7 @@ func GenFiller(programIR []ir.SQLFlowStmt, session *pb.Session, useCoulerSubmitt
 					DockerImage: stepImage}
 				r.SQLStatements = append(r.SQLStatements, sqlStmt)
 			}
-		case *ir.ShowTrainStmt, *ir.OptimizeStmt:
+		case *ir.ShowTrainStmt, *ir.OptimizeStmt, *ir.ImportModelStmt:
 			sqlStmt := &sqlStatement{
 				OriginalSQL:   escapedSQL,
 				IsExtendedSQL: sqlIR.IsExtended(),
@@ -14,6 +14,7 @@
 package workflow
 
 import (
+	"context"
 	"errors"
 	"io/ioutil"
 	"os"
@@ -26,6 +27,7 @@ import (
 	"sqlflow.org/sqlflow/go/parser"
 	pb "sqlflow.org/sqlflow/go/proto"
 	"sqlflow.org/sqlflow/go/sql"
+	"sqlflow.org/sqlflow/go/tracing"
 	"sqlflow.org/sqlflow/go/workflow/couler"
 )
 
@@ -38,7 +40,8 @@ func CompileToYAML(sqlProgram string, session *pb.Session, logger *log.Logger) (
 		return "", e
 	}
 
-	stmts, e := parser.Parse(driverName, sqlProgram)
+	ctx := tracing.ContextWithTraceparent(context.Background(), session.Traceparent)
+	stmts, e := parser.ParseContext(ctx, driverName, sqlProgram)
 	if e != nil {
 		return "", e
 	}
@@ -47,6 +50,9 @@ func CompileToYAML(sqlProgram string, session *pb.Session, logger *log.Logger) (
 	if e != nil {
 		return "", e
 	}
+	if e := sql.Authorize(sqls, spIRs, session); e != nil {
+		return "", e
+	}
 
 	// Generate Fluid/Tekton program
 	py, e := couler.GenCode(spIRs, session, false)
@@ -96,7 +102,8 @@ func CompileToCoulerSubmitCode(sqlProgram string, session *pb.Session, logger *l
 		return "", e
 	}
 
-	stmts, e := parser.Parse(driverName, sqlProgram)
+	ctx := tracing.ContextWithTraceparent(context.Background(), session.Traceparent)
+	stmts, e := parser.ParseContext(ctx, driverName, sqlProgram)
 	if e != nil {
 		return "", e
 	}
@@ -105,6 +112,9 @@ func CompileToCoulerSubmitCode(sqlProgram string, session *pb.Session, logger *l
 	if e != nil {
 		return "", e
 	}
+	if e := sql.Authorize(sqls, spIRs, session); e != nil {
+		return "", e
+	}
 
 	// Generate Fluid/Tekton program
 	py, e := couler.GenCode(spIRs, session, true)
@@ -116,6 +126,20 @@ func CompileToCoulerSubmitCode(sqlProgram string, session *pb.Session, logger *l
 
 // CompileToYAMLExperimental compiles sqlProgram to a YAML workflow.
 func CompileToYAMLExperimental(sqlProgram string, session *pb.Session) (string, error) {
+	driverName, _, e := database.ParseURL(session.DbConnStr)
+	if e != nil {
+		return "", e
+	}
+	ctx := tracing.ContextWithTraceparent(context.Background(), session.Traceparent)
+	stmts, e := parser.ParseContext(ctx, driverName, sqlProgram)
+	if e != nil {
+		return "", e
+	}
+	sqls := sql.RewriteStatementsWithHints(stmts, driverName)
+	if e := sql.Authorize(sqls, nil, session); e != nil {
+		return "", e
+	}
+
 	py, e := experimental.GenerateCodeCouler(sqlProgram, session)
 	if e != nil {
 		return "", e
@@ -350,7 +350,9 @@ def submit_alisa_evaluate(datasource, model_name, select, result_table,
 
     metrics = get_evaluate_metrics(model_type, model_attrs)
     params["metrics"] = metrics
-    create_evaluate_result_table(datasource, result_table, metrics)
+    params["result_column_names"] = create_evaluate_result_table(
+        datasource, result_table, metrics,
+        model_attrs.get("evaluate.mode", "overwrite"))
 
     conf = cluster_conf.get_cluster_config(model_attrs)
 
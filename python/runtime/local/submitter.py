@@ -49,7 +49,8 @@ def submit_local_train(datasource,
                        validation_params,
                        save,
                        load,
-                       user=""):
+                       user="",
+                       lineage=None):
     """This function run train task locally.
 
     Args:
@@ -84,7 +85,10 @@ def submit_local_train(datasource,
         load: string
             The pre-trained model name to load
         user: string
-            Not used for local submitter, used in runtime.pai only.
+            The user who trains the model, recorded in the model catalog.
+        lineage: dict
+            The inputs of the training statement and the code generator,
+            recorded in the model metadata.
     """
     if estimator_string.lower().startswith("xgboost"):
         train_func = xgboost_train
@@ -107,7 +111,9 @@ def submit_local_train(datasource,
                       feature_column_map=feature_column_map,
                       label_column=label_column,
                       save=save,
-                      load=load)
+                      load=load,
+                      user=user,
+                      lineage=lineage)
 
 
 def submit_local_pred(datasource,
@@ -159,6 +165,7 @@ def submit_local_evaluate(datasource,
                           model_params,
                           result_table,
                           user=""):
+    model_name = model
     model = Model.load_from_db(datasource, model)
     if model.get_type() == EstimatorType.XGBOOST:
         evaluate_func = xgboost_evaluate
@@ -170,8 +177,9 @@ def submit_local_evaluate(datasource,
 
     conn = db.connect_with_data_source(datasource)
     validation_metrics = [m.strip() for m in validation_metrics.split(",")]
-    result_column_names = create_evaluate_table(conn, result_table,
-                                                validation_metrics)
+    result_column_names = create_evaluate_table(
+        conn, result_table, validation_metrics,
+        model_params.get("evaluate.mode", "overwrite"))
     conn.close()
 
     evaluate_func(datasource=datasource,
@@ -180,7 +188,8 @@ def submit_local_evaluate(datasource,
                   model=model,
                   label_name=label_name,
                   model_params=model_params,
-                  result_column_names=result_column_names)
+                  result_column_names=result_column_names,
+                  model_name=model_name)
 
 
 def submit_local_explain(datasource,
@@ -0,0 +1,144 @@
+# Copyright 2020 The SQLFlow Authors. All rights reserved.
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+# http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+"""This module indexes the models saved by the Python runtime in the model
+catalog table, in the same way as go/model/catalog.go.
+"""
+import datetime
+import json
+import os
+
+# EnvCatalogTable in go/model/catalog.go
+ENV_CATALOG_TABLE = "SQLFLOW_MODEL_CATALOG_TABLE"
+DEFAULT_CATALOG_TABLE = "sqlflow_models.model_catalog"
+
+CATALOG_COLUMNS = [
+    "name", "owner", "estimator", "train_select", "attributes", "label",
+    "features", "created_at", "size", "version", "storage_uri", "lineage"
+]
+
+_COLUMN_TYPES = {
+    "mysql": ("VARCHAR(255)", "TEXT", "DATETIME"),
+    "hive": ("STRING", "STRING", "TIMESTAMP"),
+    "maxcompute": ("STRING", "STRING", "DATETIME"),
+    "alisa": ("STRING", "STRING", "DATETIME"),
+    "paiio": ("STRING", "STRING", "DATETIME"),
+}
+
+
+def catalog_table():
+    return os.getenv(ENV_CATALOG_TABLE, "") or DEFAULT_CATALOG_TABLE
+
+
+def _quote(s):
+    s = s.replace("\\", "\\\\").replace("'", "\\'").replace("\n", "\\n")
+    return "'%s'" % s
+
+
+def _time_literal(driver, t):
+    literal = _quote(t.strftime("%Y-%m-%d %H:%M:%S"))
+    if driver == "mysql":
+        return literal
+    return "CAST(%s AS %s)" % (literal, _COLUMN_TYPES[driver][2])
+
+
+def _field_names(column):
+    return [fd.name for fd in column.get_field_desc()]
+
+
+def catalog_entry(name, version, owner, model, size, storage_uri):
+    """Return the catalog row of the version of the model as a dict.
+
+    Args:
+        name (str): the model name.
+        version (int): the model version.
+        owner (str): the user who trained the model.
+        model (Model): the saved model.
+        size (int): the size of the model tarball in bytes.
+        storage_uri (str): the sqlfs table of the model.
+    """
+    label = model.get_meta("label")
+    lineage = model.get_meta("lineage")
+    features = []
+    feature_columns = model.get_meta("features") or {}
+    for target in sorted(feature_columns.keys()):
+        for fc in feature_columns[target]:
+            features.extend(_field_names(fc))
+    return {
+        "name": name,
+        "owner": owner,
+        "estimator": model.get_meta("class_name", ""),
+        "train_select": model.get_meta("select", ""),
+        "attributes": json.dumps(model.get_meta("attributes", {})),
+        "label": _field_names(label)[0] if label else "",
+        "features": features,
+        "created_at": datetime.datetime.now(),
+        "size": size,
+        "version": version,
+        "storage_uri": storage_uri,
+        "lineage": json.dumps(lineage) if lineage else "",
+    }
+
+
+def create_catalog_table(conn, table):
+    if conn.driver not in _COLUMN_TYPES:
+        raise ValueError("unsupported driver type %s" % conn.driver)
+    str_type, text_type, datetime_type = _COLUMN_TYPES[conn.driver]
+    types = [
+        str_type, str_type, str_type, text_type, text_type, str_type,
+        text_type, datetime_type, "BIGINT", "BIGINT", text_type, text_type
+    ]
+    fields = ["%s %s" % (c, t) for c, t in zip(CATALOG_COLUMNS, types)]
+    if conn.driver == "mysql":
+        fields.append("PRIMARY KEY (name, version)")
+    conn.execute("CREATE TABLE IF NOT EXISTS %s (%s)" %
+                 (table, ", ".join(fields)))
+
+
+def upsert_catalog(conn, table, entry):
+    """Create the model catalog table if not exists, and replace the row of
+    the model version by entry, see catalog_entry.
+    """
+    create_catalog_table(conn, table)
+    values = []
+    for c in CATALOG_COLUMNS:
+        v = entry[c]
+        if c == "created_at":
+            values.append(_time_literal(conn.driver, v))
+        elif c in ("size", "version"):
+            values.append("%d" % v)
+        elif c == "features":
+            values.append(_quote(",".join(v)))
+        else:
+            values.append(_quote(v))
+    values = ", ".join(values)
+    if conn.driver == "mysql":
+        conn.execute("REPLACE INTO %s (%s) VALUES (%s)" %
+                     (table, ",".join(CATALOG_COLUMNS), values))
+        return
+    delete_catalog(conn, table, entry["name"], [entry["version"]])
+    conn.execute("INSERT INTO TABLE %s SELECT %s" % (table, values))
+
+
+def delete_catalog(conn, table, name, versions):
+    """Remove the versions of the model name from the model catalog table.
+    """
+    if not versions:
+        return
+    cond = "name=%s AND version IN (%s)" % (_quote(name), ",".join(
+        [str(v) for v in versions]))
+    if conn.driver == "mysql":
+        conn.execute("DELETE FROM %s WHERE %s" % (table, cond))
+    else:
+        # Hive and MaxCompute don't support DELETE.
+        conn.execute("INSERT OVERWRITE TABLE %s SELECT * FROM %s WHERE NOT "
+                     "(%s)" % (table, table, cond))
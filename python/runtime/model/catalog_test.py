@@ -0,0 +1,93 @@
+# Copyright 2020 The SQLFlow Authors. All rights reserved.
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+# http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+
+import unittest
+
+from runtime.model import catalog
+
+
+class FakeFieldDesc(object):
+    def __init__(self, name):
+        self.name = name
+
+
+class FakeColumn(object):
+    def __init__(self, *names):
+        self.names = names
+
+    def get_field_desc(self):
+        return [FakeFieldDesc(n) for n in self.names]
+
+
+class FakeModel(object):
+    def __init__(self, meta):
+        self.meta = meta
+
+    def get_meta(self, name, default=None):
+        return self.meta.get(name, default)
+
+
+class FakeConnection(object):
+    def __init__(self, driver):
+        self.driver = driver
+        self.statements = []
+
+    def execute(self, statement):
+        self.statements.append(statement)
+
+
+class TestModelCatalog(unittest.TestCase):
+    def test_upsert_catalog(self):
+        model = FakeModel({
+            "class_name": "DNNClassifier",
+            "select": "SELECT * FROM iris.train WHERE class!='2'",
+            "attributes": {
+                "model.n_classes": 3
+            },
+            "features": {
+                "feature_columns":
+                [FakeColumn("sepal_length"),
+                 FakeColumn("sepal_width")]
+            },
+            "label": FakeColumn("class"),
+            "lineage": {
+                "inputs": ["iris.train"]
+            },
+        })
+        entry = catalog.catalog_entry("db.my_dnn", 2, "alice", model, 1024,
+                                      "db.my_dnn__v2")
+        self.assertEqual("class", entry["label"])
+        self.assertEqual(["sepal_length", "sepal_width"], entry["features"])
+        self.assertEqual('{"model.n_classes": 3}', entry["attributes"])
+        self.assertEqual('{"inputs": ["iris.train"]}', entry["lineage"])
+
+        conn = FakeConnection("mysql")
+        catalog.upsert_catalog(conn, "db.model_catalog", entry)
+        self.assertIn("PRIMARY KEY (name, version)", conn.statements[0])
+        self.assertTrue(conn.statements[1].startswith(
+            "REPLACE INTO db.model_catalog (name,owner,estimator,"))
+        self.assertIn("'SELECT * FROM iris.train WHERE class!=\\'2\\''",
+                      conn.statements[1])
+
+        conn = FakeConnection("hive")
+        catalog.upsert_catalog(conn, "db.model_catalog", entry)
+        self.assertEqual(
+            "INSERT OVERWRITE TABLE db.model_catalog SELECT * FROM "
+            "db.model_catalog WHERE NOT (name='db.my_dnn' AND version IN (2))",
+            conn.statements[1])
+        self.assertTrue(conn.statements[2].startswith(
+            "INSERT INTO TABLE db.model_catalog SELECT 'db.my_dnn', 'alice'"))
+
+
+if __name__ == "__main__":
+    unittest.main()
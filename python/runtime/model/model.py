@@ -19,7 +19,7 @@ import runtime.temp_file as temp_file
 from runtime.dbapi import connect as connect_with_data_source
 from runtime.feature.column import (JSONDecoderWithFeatureColumn,
                                     JSONEncoderWithFeatureColumn)
-from runtime.model import oss
+from runtime.model import catalog, oss, version
 from runtime.model.db import (read_with_generator_and_metadata,
                               write_with_generator_and_metadata)
 from runtime.model.modelzoo import load_model_from_model_zoo
@@ -156,19 +156,23 @@ class Model(object):
                    datasource,
                    table,
                    local_dir=None,
-                   oss_model_dir=None):
+                   oss_model_dir=None,
+                   owner=""):
         """
         This save function would archive all the files on local_dir
-        into a tarball, and save it into DBMS with the specified table
-        name.
+        into a tarball, and save it into DBMS as a new version of the
+        model named table, i.e. the table "<table>__v<version>", and
+        removes the old versions exceeding SQLFLOW_MODEL_VERSION_RETENTION.
+        It also indexes the version in the model catalog table.
 
         Args:
             datasource (str): the connection string to DBMS.
-            table (str): the saved table name.
+            table (str): the model name without a version.
             local_dir (str): the local directory to save.
+            owner (str): the user who trained the model.
 
         Returns:
-            None.
+            The table of the saved version.
         """
         if local_dir is None:
             local_dir = os.getcwd()
@@ -184,7 +188,38 @@ class Model(object):
         if "." not in table:
             project_name = conn.param("database")
             table = project_name + "." + table
+        name, ver = version.parse_version(table)
+        if ver != "":
+            raise ValueError("cannot save model to %s, please specify the "
+                             "model name without a version" % table)
+        version.create_version_registry(conn,
+                                        version.version_registry(name))
+        ver = version.reserve_version(conn, name)
+        table = version.version_table(name, ver)
+        try:
+            size = self._save_to_table(datasource, table, local_dir)
+            conn.persist_table(table)
+        except:  # noqa: E722
+            # release the reservation, so that the version is not left
+            # pending
+            version.remove_versions(conn, name, [ver])
+            raise
+        version.commit_version(conn, name, ver, table)
+        removed = version.remove_old_versions(conn, name)
+        # NOTE: the model catalog is an index of the models, failing to
+        # update it should not fail the saving.
+        try:
+            catalog.upsert_catalog(
+                conn, catalog.catalog_table(),
+                catalog.catalog_entry(name, ver, owner, self, size, table))
+            catalog.delete_catalog(conn, catalog.catalog_table(), name,
+                                   removed)
+        except Exception as e:
+            print("failed to update model catalog: %s" % e)
+        conn.close()
+        return table
 
+    def _save_to_table(self, datasource, table, local_dir):
         with temp_file.TemporaryDirectory() as tmp_dir:
             tarball = os.path.join(tmp_dir, TARBALL_NAME)
             self._zip(local_dir, tarball)
@@ -204,10 +239,7 @@ class Model(object):
             write_with_generator_and_metadata(datasource, table,
                                               _bytes_reader(tarball),
                                               self._to_dict())
-
-        conn.persist_table(table)
-        conn.close()
-        return table
+            return os.path.getsize(tarball)
 
     @staticmethod
     def load_from_db(datasource, table, local_dir=None):
@@ -216,7 +248,8 @@ class Model(object):
 
         Args:
             datasource (str): the connection string to DBMS
-            table (str): the table name which saved in DBMS
+            table (str): the model name which saved in DBMS, optionally
+                with a version like my_model@3, or a model zoo name
             local_dir (str): the local directory to load.
 
         Returns:
@@ -231,6 +264,7 @@ class Model(object):
             gen, metadata = load_model_from_model_zoo(model_zoo_addr, table,
                                                       tag)
         else:
+            table = _resolve_model_table(datasource, table)
             gen, metadata = read_with_generator_and_metadata(datasource, table)
 
         with temp_file.TemporaryDirectory() as tmp_dir:
@@ -260,6 +294,7 @@ class Model(object):
                                                     tag,
                                                     meta_only=True)
         else:
+            table = _resolve_model_table(datasource, table)
             _, metadata = read_with_generator_and_metadata(datasource,
                                                            table,
                                                            meta_only=True)
@@ -325,6 +360,13 @@ class Model(object):
             return model
 
 
+def _resolve_model_table(datasource, model_name):
+    with connect_with_data_source(datasource) as conn:
+        if "." not in model_name:
+            model_name = conn.param("database") + "." + model_name
+        return version.resolve_model_table(conn, model_name)
+
+
 def _decompose_model_name(name):
     idx = name.rfind("/")
     if idx < 0:
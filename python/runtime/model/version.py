@@ -0,0 +1,215 @@
+# Copyright 2020 The SQLFlow Authors. All rights reserved.
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+# http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+"""This module manages the model versions in the version registry, in the
+same way as go/model/version.go, so that the models saved by the Go and the
+Python runtime share the versions.
+"""
+import datetime
+import os
+
+# EnvVersionRetention in go/model/version.go
+ENV_VERSION_RETENTION = "SQLFLOW_MODEL_VERSION_RETENTION"
+LATEST_VERSION = "latest"
+VERSION_REGISTRY_TABLE = "sqlflow_model_versions"
+
+MAX_RESERVE_ATTEMPTS = 10
+
+_COLUMN_TYPES = {
+    "mysql": ("VARCHAR(255)", "DATETIME"),
+    "hive": ("STRING", "TIMESTAMP"),
+    "maxcompute": ("STRING", "DATETIME"),
+    "alisa": ("STRING", "DATETIME"),
+    "paiio": ("STRING", "DATETIME"),
+}
+
+
+def parse_version(model_name):
+    """Split a model name like "my_model@3" into the name and the version.
+    The version is "" if not specified.
+    """
+    idx = model_name.rfind("@")
+    if idx < 0:
+        return model_name, ""
+    name, version = model_name[:idx], model_name[idx + 1:]
+    if version == LATEST_VERSION:
+        return name, version
+    if not version.isdigit() or int(version) <= 0:
+        raise ValueError(
+            "model version should be a positive integer or %s, got %s" %
+            (LATEST_VERSION, model_name))
+    return name, version
+
+
+def version_table(name, version):
+    """Return the sqlfs table of the version of the model."""
+    return "%s__v%d" % (name, version)
+
+
+def version_registry(name):
+    """Return the version registry in the database of the model."""
+    idx = name.rfind(".")
+    if idx >= 0:
+        return name[:idx + 1] + VERSION_REGISTRY_TABLE
+    return VERSION_REGISTRY_TABLE
+
+
+def _quote(s):
+    return "'%s'" % s.replace("\\", "\\\\").replace("'", "\\'")
+
+
+def _time_literal(driver, t):
+    literal = _quote(t.strftime("%Y-%m-%d %H:%M:%S"))
+    if driver == "mysql":
+        return literal
+    return "CAST(%s AS %s)" % (literal, _COLUMN_TYPES[driver][1])
+
+
+def create_version_registry(conn, registry):
+    if conn.driver not in _COLUMN_TYPES:
+        raise ValueError("unsupported driver type %s" % conn.driver)
+    str_type, datetime_type = _COLUMN_TYPES[conn.driver]
+    key = ""
+    if conn.driver == "mysql":
+        key = ", PRIMARY KEY (model_name, version)"
+    conn.execute("CREATE TABLE IF NOT EXISTS %s (model_name %s, "
+                 "version BIGINT, table_name %s, created_at %s%s)" %
+                 (registry, str_type, str_type, datetime_type, key))
+
+
+def list_versions(conn, name):
+    """Return the saved versions of the model in ascending order."""
+    rs = conn.query("SELECT DISTINCT version FROM %s WHERE model_name=%s "
+                    "AND table_name<>''" %
+                    (version_registry(name), _quote(name)))
+    versions = sorted([int(row[0]) for row in rs])
+    rs.close()
+    return versions
+
+
+def resolve_model_table(conn, model_name):
+    """Return the sqlfs table of a model name like "my_model",
+    "my_model@latest" or "my_model@3". A model name without a version
+    refers to the latest version, or the table of the same name if the
+    model is saved before versioning.
+    """
+    name, version = parse_version(model_name)
+    try:
+        versions = list_versions(conn, name)
+    except Exception as e:
+        if version == "":
+            return name
+        raise ValueError("cannot list versions of model %s: %s" % (name, e))
+    if not versions:
+        if version == "":
+            return name
+        raise ValueError("model %s has no versions" % name)
+    if version in ("", LATEST_VERSION):
+        return version_table(name, versions[-1])
+    if int(version) not in versions:
+        raise ValueError("model %s has no version %s, available versions: %s" %
+                         (name, version, versions))
+    return version_table(name, int(version))
+
+
+def _insert_version(conn, name, version, table):
+    values = "%s, %d, %s, %s" % (_quote(name), version, _quote(table),
+                                 _time_literal(conn.driver,
+                                               datetime.datetime.now()))
+    if conn.driver == "mysql":
+        stmt = "INSERT INTO %s VALUES (%s)" % (version_registry(name), values)
+    else:
+        stmt = "INSERT INTO TABLE %s SELECT %s" % (version_registry(name),
+                                                   values)
+    conn.execute(stmt)
+
+
+def reserve_version(conn, name):
+    """Allocate the next version of the model by inserting it into the
+    version registry before writing the model, see reserveVersion in
+    go/model/version.go.
+    """
+    registry = version_registry(name)
+    for _ in range(MAX_RESERVE_ATTEMPTS):
+        rs = conn.query("SELECT MAX(version) FROM %s WHERE model_name=%s" %
+                        (registry, _quote(name)))
+        rows = [row for row in rs]
+        rs.close()
+        latest = rows[0][0] if rows and rows[0][0] is not None else 0
+        version = int(latest) + 1
+        try:
+            _insert_version(conn, name, version, "")
+            return version
+        except Exception as e:
+            # another saving has reserved the version on MySQL
+            if "Duplicate entry" not in str(e):
+                raise
+    raise ValueError("cannot reserve a version of model %s after %d attempts" %
+                     (name, MAX_RESERVE_ATTEMPTS))
+
+
+def commit_version(conn, name, version, table):
+    """Record the sqlfs table of the reserved version."""
+    if conn.driver != "mysql":
+        # Hive and MaxCompute don't support UPDATE, list_versions ignores
+        # the reservation.
+        _insert_version(conn, name, version, table)
+        return
+    conn.execute(
+        "UPDATE %s SET table_name=%s, created_at=%s WHERE model_name=%s "
+        "AND version=%d" %
+        (version_registry(name), _quote(table),
+         _time_literal(conn.driver, datetime.datetime.now()), _quote(name),
+         version))
+
+
+def remove_versions(conn, name, versions):
+    """Drop the sqlfs tables of the versions of the model and remove them
+    from the version registry.
+    """
+    if not versions:
+        return
+    for v in versions:
+        conn.execute("DROP TABLE IF EXISTS %s" % version_table(name, v))
+    registry = version_registry(name)
+    cond = "model_name=%s AND version IN (%s)" % (_quote(name), ",".join(
+        [str(v) for v in versions]))
+    if conn.driver == "mysql":
+        conn.execute("DELETE FROM %s WHERE %s" % (registry, cond))
+    else:
+        # Hive and MaxCompute don't support DELETE.
+        conn.execute("INSERT OVERWRITE TABLE %s SELECT * FROM %s WHERE NOT "
+                     "(%s)" % (registry, registry, cond))
+
+
+def version_retention():
+    """Return how many versions of each model are kept, 0 keeps all."""
+    s = os.getenv(ENV_VERSION_RETENTION, "")
+    if s == "":
+        return 0
+    if not s.isdigit():
+        raise ValueError("%s should be a non-negative integer, got %s" %
+                         (ENV_VERSION_RETENTION, s))
+    return int(s)
+
+
+def remove_old_versions(conn, name):
+    """Drop the versions of the model but the latest retention ones, and
+    return the dropped versions.
+    """
+    retention = version_retention()
+    versions = list_versions(conn, name)
+    if retention == 0 or len(versions) <= retention:
+        return []
+    old = versions[:len(versions) - retention]
+    remove_versions(conn, name, old)
+    return old
@@ -0,0 +1,89 @@
+# Copyright 2020 The SQLFlow Authors. All rights reserved.
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+# http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+
+import unittest
+
+from runtime.model import version
+
+
+class FakeResultSet(list):
+    def close(self):
+        pass
+
+
+class FakeConnection(object):
+    """FakeConnection is a MySQL connection to a version registry, which
+    fails the insertions of the duplicated versions like a primary key.
+    """
+    def __init__(self, rows):
+        self.driver = "mysql"
+        self.rows = rows
+        self.statements = []
+        # the results of MAX(version) of the savings racing with us
+        self.stale_max = []
+
+    def query(self, statement):
+        if statement.startswith("SELECT MAX(version)"):
+            if self.stale_max:
+                return FakeResultSet([[self.stale_max.pop(0)]])
+            return FakeResultSet([[max([r[0] for r in self.rows] or [None])]])
+        return FakeResultSet(
+            sorted(set([(r[0], ) for r in self.rows if r[1] != ""])))
+
+    def execute(self, statement):
+        self.statements.append(statement)
+        if statement.startswith("INSERT INTO"):
+            v = int(statement.split(", ")[1])
+            if v in [r[0] for r in self.rows]:
+                raise Exception("Duplicate entry 'm-%d' for key 'PRIMARY'" %
+                                v)
+            self.rows.append((v, ""))
+
+
+class TestModelVersion(unittest.TestCase):
+    def test_parse_version(self):
+        self.assertEqual(("db.m", "3"), version.parse_version("db.m@3"))
+        self.assertEqual(("db.m", ""), version.parse_version("db.m"))
+        self.assertEqual(("m", "latest"), version.parse_version("m@latest"))
+        self.assertRaises(ValueError, version.parse_version, "m@0")
+        self.assertRaises(ValueError, version.parse_version, "m@v1")
+        self.assertEqual("db.sqlflow_model_versions",
+                         version.version_registry("db.m"))
+        self.assertEqual("db.m__v2", version.version_table("db.m", 2))
+
+    def test_resolve_model_table(self):
+        conn = FakeConnection([(1, "db.m__v1"), (2, "db.m__v2"), (3, "")])
+        self.assertEqual("db.m__v2", version.resolve_model_table(conn, "db.m"))
+        self.assertEqual("db.m__v2",
+                         version.resolve_model_table(conn, "db.m@latest"))
+        self.assertEqual("db.m__v1",
+                         version.resolve_model_table(conn, "db.m@1"))
+        # version 3 is reserved but not saved yet
+        self.assertRaises(ValueError, version.resolve_model_table, conn,
+                          "db.m@3")
+        # a model saved before versioning
+        conn = FakeConnection([])
+        self.assertEqual("db.m", version.resolve_model_table(conn, "db.m"))
+
+    def test_reserve_version(self):
+        conn = FakeConnection([(1, "db.m__v1")])
+        self.assertEqual(2, version.reserve_version(conn, "db.m"))
+        self.assertEqual(3, version.reserve_version(conn, "db.m"))
+        # another saving has reserved version 4 after we read MAX(version)
+        conn.rows.append((4, ""))
+        conn.stale_max = [3]
+        self.assertEqual(5, version.reserve_version(conn, "db.m"))
+
+
+if __name__ == "__main__":
+    unittest.main()
@@ -13,7 +13,7 @@
 
 from runtime import db
 from runtime.model import EstimatorType
-from runtime.pai import table_ops
+from runtime.step.create_result_table import create_evaluate_table
 
 
 def create_predict_result_table(datasource, select, result_table, label_column,
@@ -75,21 +75,23 @@ def get_create_shap_result_sql(conn, data_table, result_table, label_column):
                                                    ",".join(fields))
 
 
-def create_evaluate_result_table(datasource, result_table, metrics):
+def create_evaluate_result_table(datasource,
+                                 result_table,
+                                 metrics,
+                                 mode="overwrite"):
     """Create a table to hold the evaluation result
 
     Args:
         datasource: current datasource
         result_table: the table name to save result
         metrics: list of evaluation metrics names
+        mode: "overwrite" or "append", the evaluate.mode attribute
+
+    Returns:
+        The column names of the created table.
     """
-    table_ops.drop_tables([result_table], datasource)
-    # Always add loss
-    ext_metrics = ["loss"]
-    if isinstance(metrics, list):
-        ext_metrics.extend(metrics)
-    fields = ["%s STRING" % m for m in ext_metrics]
-    sql = "CREATE TABLE IF NOT EXISTS %s (%s);" % (result_table,
-                                                   ",".join(fields))
-    conn = db.connect_with_data_source(datasource)
-    conn.execute(sql)
+    # Always add loss in create_evaluate_table
+    if not isinstance(metrics, list):
+        metrics = []
+    with db.connect_with_data_source(datasource) as conn:
+        return create_evaluate_table(conn, result_table, metrics, mode)
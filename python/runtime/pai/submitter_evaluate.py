@@ -82,12 +82,14 @@ def submit_pai_evaluate(datasource,
 
     validation_metrics = [m.strip() for m in validation_metrics.split(",")]
     with db.connect_with_data_source(datasource) as conn:
-        result_column_names = create_evaluate_table(conn, result_table,
-                                                    validation_metrics)
+        result_column_names = create_evaluate_table(
+            conn, result_table, validation_metrics,
+            model_params.get("evaluate.mode", "overwrite"))
 
     with table_ops.create_tmp_tables_guard(select, datasource) as data_table:
         params["pai_table"] = data_table
         params["result_column_names"] = result_column_names
+        params["model_name"] = model
 
         if try_pai_local_run(params, oss_model_path):
             return
@@ -86,7 +86,8 @@ def submit_pai_train(datasource,
                      validation_params,
                      save,
                      load,
-                     user=""):
+                     user="",
+                     lineage=None):
     """This function submit PAI-TF train task to the PAI platform.
 
     Args:
@@ -122,6 +123,9 @@ def submit_pai_train(datasource,
         user: string
             A string to identify the user, used to store models in the user's
             directory.
+        lineage: dict
+            The inputs of the training statement and the code generator,
+            recorded in the model metadata.
     """
     # prepare params for to call runtime.pai.xxx_submitter.train_step(...),
     # the params will be pickled into train_params.pkl
@@ -0,0 +1,105 @@
+# Copyright 2020 The SQLFlow Authors. All rights reserved.
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+# http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+
+import json
+import sys
+import time
+
+# NOTE: keep the prefix the same as progressLinePrefix in
+# go/executor/progress.go
+PROGRESS_LINE_PREFIX = "SQLFLOW_PROGRESS "
+
+
+def report(epoch, step, metric, value, eta_seconds=-1):
+    """
+    report prints a structured training progress record to stdout. The
+    SQLFlow executor parses the line into a Progress message instead of
+    forwarding it as a raw log line.
+    Args:
+        epoch (int): the current epoch.
+        step (int): the current step in the epoch.
+        metric (str): the metric name, e.g. "loss".
+        value (float): the metric value.
+        eta_seconds (int): estimated seconds to finish, -1 if unknown.
+    Return:
+        None
+    """
+    record = {
+        "epoch": int(epoch),
+        "step": int(step),
+        "metric": str(metric),
+        "value": float(value),
+        "eta_seconds": int(eta_seconds),
+    }
+    sys.stdout.write(PROGRESS_LINE_PREFIX + json.dumps(record) + "\n")
+    sys.stdout.flush()
+
+
+class ETAEstimator(object):
+    """
+    ETAEstimator estimates the remaining seconds of a training loop by the
+    average time spent on the finished epochs.
+    """
+    def __init__(self, total_epochs):
+        self.total_epochs = total_epochs
+        self.start_time = time.time()
+
+    def eta(self, finished_epochs):
+        if not self.total_epochs or finished_epochs <= 0:
+            return -1
+        elapsed = time.time() - self.start_time
+        remaining = self.total_epochs - finished_epochs
+        return int(elapsed / finished_epochs * max(remaining, 0))
+
+
+def keras_progress_callback(epochs):
+    """
+    keras_progress_callback returns a Keras callback which reports the
+    metrics at the end of each epoch.
+    """
+    import tensorflow as tf
+
+    class ProgressCallback(tf.keras.callbacks.Callback):
+        def __init__(self):
+            super(ProgressCallback, self).__init__()
+            self.eta = ETAEstimator(epochs)
+            self.step = 0
+
+        def on_epoch_begin(self, epoch, logs=None):
+            self.step = 0
+
+        def on_train_batch_end(self, batch, logs=None):
+            self.step += 1
+
+        def on_epoch_end(self, epoch, logs=None):
+            eta = self.eta.eta(epoch + 1)
+            for name, value in (logs or {}).items():
+                report(epoch + 1, self.step, name, value, eta)
+
+    return ProgressCallback()
+
+
+def xgboost_progress_callback(num_boost_round):
+    """
+    xgboost_progress_callback returns an XGBoost callback which reports
+    the evaluation results after each boosting round.
+    """
+    eta = ETAEstimator(num_boost_round)
+
+    def callback(env):
+        finished = env.iteration - env.begin_iteration + 1
+        remaining = eta.eta(finished)
+        for name, value in env.evaluation_result_list:
+            report(1, env.iteration + 1, name, value, remaining)
+
+    return callback
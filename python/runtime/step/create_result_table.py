@@ -11,6 +11,8 @@
 # See the License for the specific language governing permissions and
 # limitations under the License.
 
+import datetime
+
 from runtime import db
 from runtime.feature.field_desc import DataFormat, DataType
 from runtime.model.model import EstimatorType
@@ -80,33 +82,115 @@ def create_predict_table(conn,
     return result_column_names, train_label_index
 
 
-def create_evaluate_table(conn, result_table, validation_metrics):
+EVALUATE_MODEL_COLUMN = "model_name"
+EVALUATE_TIME_COLUMN = "evaluated_at"
+
+# The column types of the evaluation result table in each dialect, i.e.,
+# the model name, the evaluation time and the metric types. They are the
+# same as createEvaluationResultTable in go/executor/executor.go, so that the
+# table created in the workflow mode could be appended to in the local mode,
+# and vice versa.
+_EVALUATE_COLUMN_TYPES = {
+    "mysql": ("VARCHAR(255)", "DATETIME", "DOUBLE"),
+    "hive": ("STRING", "TIMESTAMP", "DOUBLE"),
+    "maxcompute": ("STRING", "DATETIME", "DOUBLE"),
+    "paiio": ("STRING", "DATETIME", "DOUBLE"),
+}
+
+
+def _normalize_column_type(typ):
+    """
+    Remove the length and the driver specific suffix of a column type,
+    e.g. VARCHAR(255) => VARCHAR, STRING_TYPE => STRING.
     """
-    Create the result table to store the evaluation result.
+    typ = typ.strip().upper().split("(")[0]
+    if typ.endswith("_TYPE"):
+        typ = typ[:-len("_TYPE")]
+    return typ
+
+
+def create_evaluate_table(conn,
+                          result_table,
+                          validation_metrics,
+                          mode="overwrite"):
+    """
+    Create the result table to store the evaluation result. The table has
+    a model_name column, an evaluated_at column and a numeric column for
+    each metric, including loss.
 
     Args:
         conn: the database connection object.
         result_table (str): the output data table.
         validation_metrics (list[str]): the evaluation metric names.
+        mode (str): "overwrite" to recreate the table, or "append" to
+            create the table only if it doesn't exist, in which case the
+            column names and types of the existing table are checked.
 
     Returns:
         The column names of the created table.
     """
-    result_columns = ['loss'] + validation_metrics
-    float_field_type = DataType.to_db_field_type(conn.driver, DataType.FLOAT32)
+    mode = mode.lower()
+    if mode not in ("overwrite", "append"):
+        raise ValueError(
+            "evaluate.mode should be one of overwrite and append, got %s" %
+            mode)
+    if conn.driver not in _EVALUATE_COLUMN_TYPES:
+        raise ValueError("unsupported driver type %s" % conn.driver)
+
+    metric_columns = ['loss'] + [m.strip() for m in validation_metrics]
+    result_columns = [EVALUATE_MODEL_COLUMN, EVALUATE_TIME_COLUMN
+                      ] + metric_columns
+    str_type, time_type, metric_type = _EVALUATE_COLUMN_TYPES[conn.driver]
+    result_types = [str_type, time_type] + [metric_type] * len(metric_columns)
     column_strs = [
-        "%s %s" % (name, float_field_type) for name in result_columns
+        "%s %s" % (name, typ)
+        for name, typ in zip(result_columns, result_types)
     ]
 
-    drop_sql = "DROP TABLE IF EXISTS %s;" % result_table
-    create_sql = "CREATE TABLE %s (%s);" % (result_table,
-                                            ",".join(column_strs))
-    conn.execute(drop_sql)
+    if mode == "overwrite":
+        conn.execute("DROP TABLE IF EXISTS %s;" % result_table)
+    create_sql = "CREATE TABLE IF NOT EXISTS %s (%s);" % (
+        result_table, ",".join(column_strs))
     conn.execute(create_sql)
 
+    if mode == "append":
+        existing = [(c[0].split(".")[-1].lower(), _normalize_column_type(c[1]))
+                    for c in conn.get_table_schema(result_table)]
+        expected = [(name.lower(), _normalize_column_type(typ))
+                    for name, typ in zip(result_columns, result_types)]
+        if existing != expected:
+            raise ValueError(
+                "the columns of table %s (%s) don't match the evaluation "
+                "result (%s)" % (result_table, ",".join(
+                    "%s %s" % c for c in existing), ",".join(column_strs)))
+
     return result_columns
 
 
+def evaluate_result_row(result_column_names, model_name, metrics):
+    """
+    Build a row of the evaluation result table.
+
+    Args:
+        result_column_names (list[str]): the columns of the result table.
+        model_name (str): the name of the evaluated model.
+        metrics (dict): the metric values keyed by the metric names.
+
+    Returns:
+        A list of the column values.
+    """
+    evaluated_at = datetime.datetime.now().replace(microsecond=0)
+    row = []
+    for name in result_column_names:
+        if name == EVALUATE_MODEL_COLUMN:
+            row.append(model_name)
+        elif name == EVALUATE_TIME_COLUMN:
+            row.append(evaluated_at)
+        else:
+            row.append(float(metrics[name]))
+    return row
+
+
 def create_explain_table(conn, model_type, explainer, estimator_string,
                          result_table, feature_column_names):
     drop_sql = "DROP TABLE IF EXISTS %s;" % result_table
@@ -0,0 +1,65 @@
+# Copyright 2020 The SQLFlow Authors. All rights reserved.
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+# http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+
+import unittest
+
+from runtime.step.create_result_table import create_evaluate_table
+
+
+class FakeConnection(object):
+    """FakeConnection is a connection to an existing table with the given
+    schema.
+    """
+    def __init__(self, driver, schema):
+        self.driver = driver
+        self.schema = schema
+        self.statements = []
+
+    def execute(self, statement):
+        self.statements.append(statement)
+
+    def get_table_schema(self, table):
+        return self.schema
+
+
+class TestCreateEvaluateTable(unittest.TestCase):
+    def test_overwrite(self):
+        conn = FakeConnection("mysql", [])
+        columns = create_evaluate_table(conn, "db.t", ["auc", " mse"])
+        self.assertEqual(["model_name", "evaluated_at", "loss", "auc", "mse"],
+                         columns)
+        self.assertEqual("DROP TABLE IF EXISTS db.t;", conn.statements[0])
+        self.assertEqual(
+            "CREATE TABLE IF NOT EXISTS db.t (model_name VARCHAR(255),"
+            "evaluated_at DATETIME,loss DOUBLE,auc DOUBLE,mse DOUBLE);",
+            conn.statements[1])
+
+    def test_append(self):
+        conn = FakeConnection("hive", [("t.model_name", "STRING_TYPE"),
+                                       ("t.evaluated_at", "TIMESTAMP_TYPE"),
+                                       ("t.loss", "DOUBLE_TYPE"),
+                                       ("t.auc", "DOUBLE_TYPE")])
+        create_evaluate_table(conn, "db.t", ["auc"], "append")
+        self.assertEqual(1, len(conn.statements))
+        self.assertRaises(ValueError, create_evaluate_table, conn, "db.t",
+                          ["mse"], "append")
+        # the metric created as FLOAT doesn't match
+        conn = FakeConnection("mysql", [("model_name", "VARCHAR"),
+                                        ("evaluated_at", "DATETIME"),
+                                        ("loss", "FLOAT")])
+        self.assertRaises(ValueError, create_evaluate_table, conn, "db.t", [],
+                          "append")
+
+
+if __name__ == "__main__":
+    unittest.main()
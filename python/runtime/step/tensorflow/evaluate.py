@@ -45,8 +45,10 @@ def evaluate_step(datasource,
                   label_name,
                   model_params,
                   result_column_names,
-                  pai_table=None):
+                  pai_table=None,
+                  model_name=""):
     if isinstance(model, six.string_types):
+        model_name = model_name or model
         model = Model.load_from_db(datasource, model)
     else:
         assert isinstance(model,
@@ -91,7 +93,8 @@ def evaluate_step(datasource,
               batch_size=batch_size,
               validation_steps=validation_steps,
               verbose=verbose,
-              pai_table=pai_table)
+              pai_table=pai_table,
+              model_name=model_name)
 
 
 def _evaluate(datasource,
@@ -109,7 +112,8 @@ def _evaluate(datasource,
               batch_size=1,
               validation_steps=None,
               verbose=0,
-              pai_table=""):
+              pai_table="",
+              model_name=""):
     FLAGS = define_tf_flags()
     set_oss_environs(FLAGS)
 
@@ -150,5 +154,5 @@ def _evaluate(datasource,
         else:
             conn = db.connect_with_data_source(datasource)
         write_result_metrics(result_metrics, result_column_names, result_table,
-                             conn)
+                             conn, model_name)
         conn.close()
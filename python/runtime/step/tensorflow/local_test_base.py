@@ -130,7 +130,9 @@ class TestTensorFlowLocalSubmitter(unittest.TestCase):
 
         eval_schema = self.get_table_schema(conn, "iris.evaluate_result_table")
         eval_schema = set([k.lower() for k in eval_schema.keys()])
-        self.assertEqual(eval_schema, set(['loss', 'accuracy']))
+        self.assertEqual(
+            eval_schema,
+            set(['model_name', 'evaluated_at', 'loss', 'accuracy']))
 
         with temp_file.TemporaryDirectory(as_cwd=True):
             feature_column_names = [
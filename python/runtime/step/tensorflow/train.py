@@ -57,7 +57,9 @@ def train_step(original_sql,
                save,
                load=None,
                pai_table=None,
-               pai_val_table=None):
+               pai_val_table=None,
+               user="",
+               lineage=None):
     if model_params is None:
         model_params = {}
 
@@ -164,6 +166,8 @@ def train_step(original_sql,
                                   attributes=model_params,
                                   features=feature_column_map,
                                   label=label_column)
+    if lineage:
+        model_meta["lineage"] = lineage
 
     # FIXME(typhoonzero): avoid save model_meta twice, keras_train_and_save,
     # estimator_train_and_save also dumps model_meta to a file under cwd.
@@ -190,7 +194,8 @@ def train_step(original_sql,
     if num_workers == 1 or worker_id == 0:
         saved = model.save_to_db(datasource,
                                  save,
-                                 oss_model_dir=FLAGS.sqlflow_oss_modeldir)
+                                 oss_model_dir=FLAGS.sqlflow_oss_modeldir,
+                                 owner=user)
         print("Model saved to DB: %s" % saved)
 
     print("Done training")
@@ -26,6 +26,7 @@ from runtime.feature.field_desc import DataType
 from runtime.model import EstimatorType
 from runtime.model.model import Model
 from runtime.pai.pai_distributed import define_tf_flags
+from runtime.step.create_result_table import evaluate_result_row
 from runtime.step.xgboost.predict import _calc_predict_result
 from runtime.xgboost.dataset import xgb_dataset
 # TODO(typhoonzero): remove runtime.xgboost
@@ -64,7 +65,8 @@ def evaluate(datasource,
              label_name=None,
              model_params=None,
              result_column_names=[],
-             pai_table=None):
+             pai_table=None,
+             model_name=""):
     """TBD
     """
     if model_params is None:
@@ -75,6 +77,7 @@ def evaluate(datasource,
 
     bst = xgb.Booster()
     if isinstance(model, six.string_types):
+        model_name = model_name or model
         with temp_file.TemporaryDirectory(as_cwd=True):
             model = Model.load_from_db(datasource, model)
             bst.load_model("my_model")
@@ -132,13 +135,14 @@ def evaluate(datasource,
             preds = _calc_predict_result(bst, pred_dmatrix, model_params)
             _store_evaluate_result(preds, feature_file_name, train_label_desc,
                                    result_table, result_column_names,
-                                   validation_metrics, conn)
+                                   validation_metrics, conn, model_name)
 
     conn.close()
 
 
 def _store_evaluate_result(preds, feature_file_name, label_desc, result_table,
-                           result_column_names, validation_metrics, conn):
+                           result_column_names, validation_metrics, conn,
+                           model_name):
     """
     Save the evaluation result in the table.
 
@@ -150,6 +154,7 @@ def _store_evaluate_result(preds, feature_file_name, label_desc, result_table,
         result_column_names (list[str]): the result column names.
         validation_metrics (list[str]): the evaluation metric names.
         conn: the database connection object.
+        model_name (str): the name of the evaluated model.
 
     Returns:
         None.
@@ -179,8 +184,8 @@ def _store_evaluate_result(preds, feature_file_name, label_desc, result_table,
         evaluate_results[metric_name] = metric_value
 
     # write evaluation result to result table
+    evaluate_results["loss"] = 0.0
     with db.buffered_db_writer(conn, result_table, result_column_names) as w:
-        row = ["0.0"]
-        for mn in validation_metrics:
-            row.append(str(evaluate_results[mn]))
-        w.write(row)
+        w.write(
+            evaluate_result_row(result_column_names, model_name,
+                                evaluate_results))
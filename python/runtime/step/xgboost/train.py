@@ -41,7 +41,9 @@ def train(original_sql,
           save,
           load=None,
           pai_table="",
-          pai_val_table=""):
+          pai_val_table="",
+          user="",
+          lineage=None):
     is_pai = True if pai_table != "" else False
     is_dist_train = False
     FLAGS = None
@@ -117,7 +119,9 @@ def train(original_sql,
                            is_pai=is_pai,
                            pai_train_table=pai_table,
                            pai_validate_table=pai_val_table,
-                           oss_model_dir=oss_model_dir)
+                           oss_model_dir=oss_model_dir,
+                           user=user,
+                           lineage=lineage)
 
 
 def local_train(original_sql,
@@ -138,7 +142,9 @@ def local_train(original_sql,
                 is_pai=False,
                 pai_train_table="",
                 pai_validate_table="",
-                oss_model_dir=""):
+                oss_model_dir="",
+                user="",
+                lineage=None):
     disk_cache = train_params.pop("disk_cache", False)
     batch_size = train_params.pop("batch_size", None)
     if batch_size is not None and batch_size < 0:
@@ -212,8 +218,10 @@ def local_train(original_sql,
                             label=label_column,
                             evaluation=eval_result,
                             num_workers=num_workers)
+    if lineage:
+        meta["lineage"] = lineage
 
     save_model_to_local_file(bst, model_params, file_name)
     model = Model(EstimatorType.XGBOOST, meta)
-    model.save_to_db(datasource, save)
+    model.save_to_db(datasource, save, owner=user)
     return eval_result
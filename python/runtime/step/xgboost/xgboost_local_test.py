@@ -123,7 +123,9 @@ class TestXGBoostTrain(unittest.TestCase):
                      result_column_names=result_column_names)
 
         eval_schema = self.get_table_schema(conn, "iris.evaluate_result_table")
-        self.assertEqual(eval_schema.keys(), set(['loss', 'accuracy_score']))
+        self.assertEqual(
+            eval_schema.keys(),
+            set(['model_name', 'evaluated_at', 'loss', 'accuracy_score']))
 
         with temp_file.TemporaryDirectory(as_cwd=True):
             feature_column_names = [
@@ -17,6 +17,7 @@ from runtime import db
 from runtime.db import buffered_db_writer
 from runtime.dbapi.paiio import PaiIOConnection
 from runtime.pai.pai_distributed import define_tf_flags, set_oss_environs
+from runtime.step.create_result_table import evaluate_result_row
 from runtime.tensorflow import metrics
 from runtime.tensorflow.get_tf_model_type import is_tf_estimator
 from runtime.tensorflow.import_model import import_model
@@ -158,12 +159,14 @@ def keras_evaluate(keras_model, eval_dataset_fn, save, keras_model_pkg,
     return result_metrics
 
 
-def write_result_metrics(result_metrics, metric_name_list, result_table, conn):
+def write_result_metrics(result_metrics,
+                         metric_name_list,
+                         result_table,
+                         conn,
+                         model_name=""):
     # NOTE: assume that the result table is already created with columns:
-    # loss | metric_names ...
+    # [model_name | evaluated_at |] loss | metric_names ...
     column_names = metric_name_list
     with buffered_db_writer(conn, result_table, column_names, 100) as w:
-        row = []
-        for key in metric_name_list:
-            row.append(result_metrics[key])
-        w.write(row)
+        w.write(
+            evaluate_result_row(metric_name_list, model_name, result_metrics))
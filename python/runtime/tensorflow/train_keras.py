@@ -17,6 +17,7 @@ import warnings
 
 import six
 import tensorflow as tf
+from runtime import progress
 from runtime.model import oss, save_metadata
 from runtime.pai.pai_distributed import (
     dump_into_tf_config, make_distributed_info_without_evaluator)
@@ -154,6 +155,9 @@ def keras_train_compiled(classifier, save, train_dataset, validate_dataset,
     if hasattr(classifier, 'sqlflow_train_loop'):
         classifier.sqlflow_train_loop(train_dataset)
     else:
+        if not epochs:
+            epochs = classifier.default_training_epochs()
+        callbacks = [progress.keras_progress_callback(epochs)]
         if label_meta["feature_name"] != "":
             # FIXME(typhoonzero): this is why need to set validation_steps:
             #  https://github.com/tensorflow/tensorflow/issues/29743#issuecomment-502028891
@@ -165,16 +169,16 @@ def keras_train_compiled(classifier, save, train_dataset, validate_dataset,
                     validation_steps = None
             history = classifier.fit(train_dataset,
                                      validation_steps=validation_steps,
-                                     epochs=epochs if epochs else
-                                     classifier.default_training_epochs(),
+                                     epochs=epochs,
                                      validation_data=validate_dataset,
-                                     verbose=verbose)
+                                     verbose=verbose,
+                                     callbacks=callbacks)
         else:
             history = classifier.fit(train_dataset,
                                      validation_steps=validation_steps,
-                                     epochs=epochs if epochs else
-                                     classifier.default_training_epochs(),
-                                     verbose=verbose)
+                                     epochs=epochs,
+                                     verbose=verbose,
+                                     callbacks=callbacks)
         train_metrics = dict()
         val_metrics = dict()
         for k in history.history.keys():
@@ -143,7 +143,7 @@ def evaluate_and_store_result(bst, dpred, feature_file_id, validation_metrics,
     # write evaluation result to result table
     result_columns = ["loss"] + validation_metrics
     with db.buffered_db_writer(conn, result_table, result_columns, 100) as w:
-        row = ["0.0"]
+        row = [0.0]
         for mn in validation_metrics:
-            row.append(str(evaluate_results[mn]))
+            row.append(float(evaluate_results[mn]))
         w.write(row)
@@ -15,6 +15,7 @@ import sys
 
 import six
 import xgboost as xgb
+from runtime import progress
 from runtime.model import collect_metadata
 from runtime.model import oss as pai_model_store
 from runtime.model import save_metadata
@@ -179,6 +180,10 @@ def train(datasource,
                         evals=watchlist,
                         evals_result=re,
                         xgb_model=bst,
+                        callbacks=[
+                            progress.xgboost_progress_callback(
+                                train_params.get("num_boost_round", 10))
+                        ],
                         **train_params)
         print("Evaluation result: %s" % re)
 